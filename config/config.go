@@ -1,59 +1,356 @@
 package config
 
 import (
-	"errors"
 	"fmt"
-	"github.com/hashicorp/hcl/v2/hclsimple"
-	_ "github.com/joho/godotenv"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/joho/godotenv"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 )
 
 // const DefaultLocation = "/etc/encedeus"
 const DefaultLocation = "./"
 
+// EnvConfigPath is the environment variable used to override the config file location
+const EnvConfigPath = "ENCEDEUS_CONFIG"
+
+// Configuration holds every setting of the panel.
+// Settings tagged with reload:"live" are applied on reload, the others require a restart.
 type Configuration struct {
-	Server ServerConfiguration   `hcl:"server,block"`
-	DB     DatabaseConfiguration `hcl:"database,block"`
-	Auth   AuthConfiguration     `hcl:"auth,block"`
-	CDN    CDNConfiguration      `hcl:"cdn,block"`
+	Server   ServerConfiguration   `hcl:"server,block"`
+	DB       DatabaseConfiguration `hcl:"database,block"`
+	Auth     AuthConfiguration     `hcl:"auth,block"`
+	CDN      CDNConfiguration      `hcl:"cdn,block"`
+	Log      LogConfiguration      `hcl:"log,block"`
+	Metrics  MetricsConfiguration  `hcl:"metrics,block"`
+	Tracing  TracingConfiguration  `hcl:"tracing,block"`
+	Webhooks WebhookConfiguration  `hcl:"webhooks,block"`
+	Plugins  PluginConfiguration   `hcl:"plugins,block"`
 }
 
 type ServerConfiguration struct {
-	Host string `hcl:"host"`
-	Port int    `hcl:"port"`
+	Host string `hcl:"host,optional"`
+	Port int    `hcl:"port,optional"`
+
+	CORSOrigins []string `hcl:"cors_origins,optional" reload:"live"`
+	// RateLimit is the number of requests per second allowed per client IP, 0 disables rate limiting
+	RateLimit      float64 `hcl:"rate_limit,optional" reload:"live"`
+	RateLimitBurst int     `hcl:"rate_limit_burst,optional" reload:"live"`
+
+	// DrainTimeout is how long in-flight requests and background jobs get to finish on shutdown
+	DrainTimeout string `hcl:"drain_timeout,optional"`
+	// APIDocs serves an API reference at /docs, the OpenAPI document at /openapi.json is always served
+	APIDocs bool `hcl:"api_docs,optional"`
+	// MaxBodySize is the size in bytes of the largest request body the API decodes
+	MaxBodySize int64 `hcl:"max_body_size,optional"`
+	// UnknownFields is reject or discard, deciding what happens to request fields the API doesn't know
+	UnknownFields string `hcl:"unknown_fields,optional" reload:"live"`
+	// LegacyAPISunset is the date, like 2027-04-19, the unversioned aliases of the /api/v1 routes are announced to be removed on
+	LegacyAPISunset string `hcl:"legacy_api_sunset,optional" reload:"live"`
+
+	// TLSCert and TLSKey are reloaded when the files change, so renewed certificates don't need a restart
+	TLSCert       string `hcl:"tls_cert,optional"`
+	TLSKey        string `hcl:"tls_key,optional"`
+	TLSMinVersion string `hcl:"tls_min_version,optional"`
+	// TLSClientAuth is one of none, request, require, verify_if_given or require_and_verify
+	TLSClientAuth string `hcl:"tls_client_auth,optional"`
+	// TLSClientCA holds the certificates client certificates are verified against
+	TLSClientCA string `hcl:"tls_client_ca,optional"`
+	// HTTPRedirectPort serves plain HTTP redirecting to HTTPS and answering ACME challenges, 0 disables it
+	HTTPRedirectPort int `hcl:"http_redirect_port,optional"`
+
+	// ACMEDomains enables issuing certificates for these domains with ACME instead of TLSCert and TLSKey
+	ACMEDomains      []string `hcl:"acme_domains,optional"`
+	ACMEEmail        string   `hcl:"acme_email,optional"`
+	ACMEDirectoryURL string   `hcl:"acme_directory_url,optional"`
+	// ACMECARoot is trusted when connecting to the ACME directory, e.g. the root of a local pebble instance
+	ACMECARoot   string `hcl:"acme_ca_root,optional"`
+	ACMECacheDir string `hcl:"acme_cache_dir,optional"`
 }
 
+const (
+	UnknownFieldsReject  = "reject"
+	UnknownFieldsDiscard = "discard"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type DatabaseConfiguration struct {
-	Host     string `hcl:"host"`
-	Port     int    `hcl:"port"`
-	User     string `hcl:"user"`
-	DBName   string `hcl:"name"`
-	Password string `hcl:"password"`
+	// Driver is either postgres or sqlite
+	Driver string `hcl:"driver,optional"`
+	// DSN replaces every other connection setting when set
+	DSN string `hcl:"dsn,optional"`
+
+	Host     string `hcl:"host,optional"`
+	Port     int    `hcl:"port,optional"`
+	User     string `hcl:"user,optional"`
+	DBName   string `hcl:"name,optional"`
+	Password string `hcl:"password,optional"`
+
+	SSLMode     string `hcl:"ssl_mode,optional"`
+	SSLRootCert string `hcl:"ssl_root_cert,optional"`
+	SSLCert     string `hcl:"ssl_cert,optional"`
+	SSLKey      string `hcl:"ssl_key,optional"`
+
+	// Path is the database file used by the sqlite driver
+	Path string `hcl:"path,optional"`
+
+	// SoftDeleteRetention is how long soft-deleted rows are kept before being purged
+	SoftDeleteRetention string `hcl:"soft_delete_retention,optional" reload:"live"`
+	// PurgeInterval is how often soft-deleted rows past the retention period are purged
+	PurgeInterval string `hcl:"purge_interval,optional"`
 }
 
 type AuthConfiguration struct {
-	JWTSecretAccess  string `hcl:"jwt_secret_access"`
-	JWTSecretRefresh string `hcl:"jwt_secret_refresh"`
+	JWTSecretAccess  string `hcl:"jwt_secret_access,optional"`
+	JWTSecretRefresh string `hcl:"jwt_secret_refresh,optional"`
+
+	// RoleGrantExpiryInterval is how often expired role grants are marked as such
+	RoleGrantExpiryInterval string `hcl:"role_grant_expiry_interval,optional"`
+	// SetupTokenFile holds the one-time token required to create the first user, it's removed once setup is done
+	SetupTokenFile string `hcl:"setup_token_file,optional"`
 }
 
 type CDNConfiguration struct {
-	Directory string `hcl:"dir"`
+	Directory string `hcl:"dir,optional"`
+}
+
+type LogConfiguration struct {
+	// Level is one of debug, info, warn, error or off
+	Level string `hcl:"level,optional" reload:"live"`
+	// Format is text or json
+	Format string `hcl:"format,optional"`
+	// Output is stdout, stderr or the path of a file log lines are appended to
+	Output string `hcl:"output,optional"`
+}
+
+// MetricsConfiguration controls the Prometheus endpoint at /metrics, which must be protected by a token,
+// a separate listen address, or both
+type MetricsConfiguration struct {
+	Enabled bool `hcl:"enabled,optional"`
+	// Token is required as a bearer token when set
+	Token string `hcl:"token,optional" reload:"live"`
+	// Listen serves the metrics on their own address, e.g. 127.0.0.1:9100, instead of the panel's
+	Listen string `hcl:"listen,optional"`
+}
+
+// TracingConfiguration controls where OpenTelemetry spans are exported to
+type TracingConfiguration struct {
+	// Exporter is none, stdout or otlp
+	Exporter string `hcl:"exporter,optional"`
+	// Endpoint is the host:port of the OTLP collector, e.g. localhost:4317 for grpc or localhost:4318 for http
+	Endpoint string `hcl:"endpoint,optional"`
+	// Protocol is grpc or http
+	Protocol string `hcl:"protocol,optional"`
+	Insecure bool   `hcl:"insecure,optional"`
+	// SampleRatio is the fraction of new traces recorded, between 0 and 1
+	SampleRatio float64 `hcl:"sample_ratio,optional"`
+	ServiceName string  `hcl:"service_name,optional"`
+}
+
+// WebhookConfiguration controls how events are delivered to webhooks
+type WebhookConfiguration struct {
+	// DeliveryInterval is how often deliveries that are due are sent
+	DeliveryInterval string `hcl:"delivery_interval,optional"`
+	// Timeout is how long a webhook gets to answer a delivery
+	Timeout string `hcl:"timeout,optional" reload:"live"`
+	// MaxAttempts is how many times a delivery is sent before it's marked as failed
+	MaxAttempts int `hcl:"max_attempts,optional" reload:"live"`
+	// RetryBackoff is the delay before the first retry, it doubles with every further attempt up to MaxRetryBackoff
+	RetryBackoff    string `hcl:"retry_backoff,optional" reload:"live"`
+	MaxRetryBackoff string `hcl:"max_retry_backoff,optional" reload:"live"`
+}
+
+// PluginConfiguration controls the plugin host, which runs every .wasm file in Directory in its own VM
+type PluginConfiguration struct {
+	// Directory holds the plugins, the plugin host is disabled if it's empty
+	Directory string `hcl:"dir,optional"`
+}
+
+// DefaultConfiguration returns the configuration used for every setting missing from the config file
+func DefaultConfiguration() Configuration {
+	return Configuration{
+		Server: ServerConfiguration{
+			Host:             "localhost",
+			Port:             8080,
+			CORSOrigins:      []string{"http://localhost:5173"},
+			RateLimitBurst:   1,
+			MaxBodySize:      1 << 20,
+			UnknownFields:    UnknownFieldsDiscard,
+			LegacyAPISunset:  "2027-04-19",
+			TLSMinVersion:    "1.2",
+			TLSClientAuth:    "none",
+			ACMEDirectoryURL: "https://acme-v02.api.letsencrypt.org/directory",
+			ACMECacheDir:     "./acme",
+		},
+		DB: DatabaseConfiguration{
+			Driver:  DriverPostgres,
+			Host:    "localhost",
+			Port:    5432,
+			User:    "postgres",
+			DBName:  "PanelDB",
+			SSLMode: "disable",
+			Path:    "./panel.db",
+		},
+		Auth: AuthConfiguration{
+			SetupTokenFile: "./setup_token",
+		},
+		CDN: CDNConfiguration{
+			Directory: "./pfp",
+		},
+		Log: LogConfiguration{
+			Level:  "info",
+			Format: "text",
+			Output: "stdout",
+		},
+		Tracing: TracingConfiguration{
+			Exporter:    "none",
+			Protocol:    "grpc",
+			SampleRatio: 1,
+			ServiceName: "encedeus-panel",
+		},
+		Webhooks: WebhookConfiguration{
+			MaxAttempts: 8,
+		},
+	}
 }
 
 func (s *ServerConfiguration) URI() string {
 	return fmt.Sprintf("%s:%d", s.Host, s.Port)
 }
 
+// TLSEnabled checks if the server serves HTTPS, with either a certificate or ACME
+func (s *ServerConfiguration) TLSEnabled() bool {
+	return s.TLSCert != "" || len(s.ACMEDomains) != 0
+}
+
+const (
+	DefaultSoftDeleteRetention     = 30 * 24 * time.Hour
+	DefaultPurgeInterval           = time.Hour
+	DefaultRoleGrantExpiryInterval = time.Minute
+	DefaultDrainTimeout            = 30 * time.Second
+	DefaultWebhookDeliveryInterval = 5 * time.Second
+	DefaultWebhookTimeout          = 10 * time.Second
+	DefaultWebhookRetryBackoff     = 30 * time.Second
+	DefaultWebhookMaxRetryBackoff  = time.Hour
+)
+
+// LegacyAPISunsetTime parses LegacyAPISunset, it's the zero time if it isn't set
+func (s *ServerConfiguration) LegacyAPISunsetTime() (time.Time, error) {
+	if s.LegacyAPISunset == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.DateOnly, s.LegacyAPISunset)
+}
+
+// DrainTimeoutPeriod returns the configured drain timeout or the default one if it's not set
+func (s *ServerConfiguration) DrainTimeoutPeriod() time.Duration {
+	return parseDurationOrDefault(s.DrainTimeout, DefaultDrainTimeout)
+}
+
+// SoftDeleteRetentionPeriod returns the configured retention period or the default one if it's not set
+func (d *DatabaseConfiguration) SoftDeleteRetentionPeriod() time.Duration {
+	return parseDurationOrDefault(d.SoftDeleteRetention, DefaultSoftDeleteRetention)
+}
+
+// PurgeIntervalPeriod returns the configured purge interval or the default one if it's not set
+func (d *DatabaseConfiguration) PurgeIntervalPeriod() time.Duration {
+	return parseDurationOrDefault(d.PurgeInterval, DefaultPurgeInterval)
+}
+
+// RoleGrantExpiryIntervalPeriod returns the configured expiry interval or the default one if it's not set
+func (a *AuthConfiguration) RoleGrantExpiryIntervalPeriod() time.Duration {
+	return parseDurationOrDefault(a.RoleGrantExpiryInterval, DefaultRoleGrantExpiryInterval)
+}
+
+// DeliveryIntervalPeriod returns the configured delivery interval or the default one if it's not set
+func (w *WebhookConfiguration) DeliveryIntervalPeriod() time.Duration {
+	return parseDurationOrDefault(w.DeliveryInterval, DefaultWebhookDeliveryInterval)
+}
+
+// TimeoutPeriod returns the configured delivery timeout or the default one if it's not set
+func (w *WebhookConfiguration) TimeoutPeriod() time.Duration {
+	return parseDurationOrDefault(w.Timeout, DefaultWebhookTimeout)
+}
+
+// RetryBackoffPeriod returns the configured delay before the first retry or the default one if it's not set
+func (w *WebhookConfiguration) RetryBackoffPeriod() time.Duration {
+	return parseDurationOrDefault(w.RetryBackoff, DefaultWebhookRetryBackoff)
+}
+
+// MaxRetryBackoffPeriod returns the configured longest delay between retries or the default one if it's not set
+func (w *WebhookConfiguration) MaxRetryBackoffPeriod() time.Duration {
+	return parseDurationOrDefault(w.MaxRetryBackoff, DefaultWebhookMaxRetryBackoff)
+}
+
+func parseDurationOrDefault(s string, def time.Duration) time.Duration {
+	if s == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		log.Printf("invalid duration %q, using default %s", s, def)
+		return def
+	}
+
+	return d
+}
+
+// Config holds the settings loaded on startup, settings that can be reloaded should be read with Current
 var Config Configuration
 
-func InitConfig() {
-	if _, err := os.Stat(fmt.Sprintf("%s/config.hcl", DefaultLocation)); errors.Is(err, os.ErrNotExist) {
-		log.Fatal("Config file does not exist")
+// ResolvePath returns the location of the config file, the flag takes precedence over the environment
+func ResolvePath(flagPath string) string {
+	if flagPath != "" {
+		return flagPath
 	}
+	if envPath := os.Getenv(EnvConfigPath); envPath != "" {
+		return envPath
+	}
+
+	return filepath.Join(DefaultLocation, "config.hcl")
+}
+
+// InitConfig loads the config file at path into Config, exiting with every problem found if it's invalid
+func InitConfig(path string) {
+	// a .env file is optional, it only provides ENCEDEUS_* overrides
+	_ = godotenv.Load()
 
-	err := hclsimple.DecodeFile(fmt.Sprintf("%s/config.hcl", DefaultLocation), nil, &Config)
-	if err != nil {
-		log.Fatalf("Failed to load configuration file: %v", err)
+	cfg, diags := LoadConfig(path)
+	if diags.HasErrors() {
+		WriteDiagnostics(os.Stderr, diags)
+		log.Fatalf("Failed to load configuration file %s", path)
 	}
+
+	Config = cfg
+	current.Store(&cfg)
+}
+
+// CheckConfig loads the config file at path the same way InitConfig does and returns every problem found
+func CheckConfig(path string) hcl.Diagnostics {
+	_ = godotenv.Load()
+	_, diags := LoadConfig(path)
+
+	return diags
+}
+
+// WriteDiagnostics writes config problems along with the file, line and source they were found on
+func WriteDiagnostics(w io.Writer, diags hcl.Diagnostics) {
+	parser := hclparse.NewParser()
+	for _, diag := range diags {
+		if diag.Subject != nil {
+			_, _ = parser.ParseHCLFile(diag.Subject.Filename)
+		}
+	}
+
+	_ = hcl.NewDiagnosticTextWriter(w, parser.Files(), 0, false).WriteDiagnostics(diags)
 }
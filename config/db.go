@@ -2,81 +2,113 @@ package config
 
 import (
     "context"
-    "entgo.io/ent/dialect/sql"
+    "database/sql"
+    "entgo.io/ent/dialect"
+    entsql "entgo.io/ent/dialect/sql"
     "fmt"
     "github.com/Encedeus/panel/ent"
     "github.com/Encedeus/panel/ent/role"
-    "github.com/Encedeus/panel/ent/user"
-    "github.com/Encedeus/panel/hashing"
+    "github.com/Encedeus/panel/metrics"
+    "github.com/Encedeus/panel/tracing"
     "github.com/labstack/gommon/log"
     _ "github.com/lib/pq"
+    _ "modernc.org/sqlite"
+    "strings"
 )
 
-func InitDB() *ent.Client {
-    // Connect to database
+// SuperuserRoleName is the role created on every database, it has every permission
+const SuperuserRoleName = "superuser"
 
-    ctx := context.Background()
+// DataSourceName returns the DSN of the configured database
+func (d *DatabaseConfiguration) DataSourceName() string {
+    if d.DSN != "" {
+        return d.DSN
+    }
 
-    db, err := ent.Open(
-        "postgres",
-        fmt.Sprintf(
-            "host=%s port=%d user=%s dbname=%s password=%s sslmode=disable",
-            Config.DB.Host,
-            Config.DB.Port,
-            Config.DB.User,
-            Config.DB.DBName,
-            Config.DB.Password,
-        ),
-    )
+    if d.Driver == DriverSQLite {
+        // ent requires foreign keys, the rest lets the purger and requests write concurrently.
+        // The busy timeout comes first so switching to WAL waits for other connections too
+        return fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)", d.Path)
+    }
 
-    if err != nil {
-        log.Fatalf("failed opening connection to postgres: %v", err)
+    params := []string{
+        "host=" + quoteDSNValue(d.Host),
+        fmt.Sprintf("port=%d", d.Port),
+        "user=" + quoteDSNValue(d.User),
+        "dbname=" + quoteDSNValue(d.DBName),
+        "password=" + quoteDSNValue(d.Password),
+        "sslmode=" + quoteDSNValue(d.SSLMode),
+    }
+    if d.SSLRootCert != "" {
+        params = append(params, "sslrootcert="+quoteDSNValue(d.SSLRootCert))
+    }
+    if d.SSLCert != "" {
+        params = append(params, "sslcert="+quoteDSNValue(d.SSLCert), "sslkey="+quoteDSNValue(d.SSLKey))
     }
 
-    // db.Schema.Create(context.Background())
+    return strings.Join(params, " ")
+}
 
-    // update Db schema
-    if err := db.Schema.Create(ctx); err != nil {
-        log.Fatalf("failed creating schema resources: %v", err)
+// EntDialect returns the ent dialect of the configured driver
+func (d *DatabaseConfiguration) EntDialect() string {
+    if d.Driver == DriverSQLite {
+        return dialect.SQLite
     }
 
-    // creates an admin user if it does not exist
-    createSuperuserRole(db, ctx)
-    createSuperuser(db, ctx)
+    return dialect.Postgres
+}
 
-    return db
+// quoteDSNValue quotes a libpq connection string value so it can contain spaces and quotes
+func quoteDSNValue(value string) string {
+    value = strings.ReplaceAll(value, `\`, `\\`)
+    value = strings.ReplaceAll(value, `'`, `\'`)
+
+    return "'" + value + "'"
 }
 
-func createSuperuser(db *ent.Client, ctx context.Context) {
-    exists, err := db.User.Query().Where(user.Name("admin")).Exist(ctx)
+// OpenDB opens a connection pool to the configured database
+func OpenDB() *sql.DB {
+    db, err := sql.Open(Config.DB.Driver, Config.DB.DataSourceName())
     if err != nil {
-        log.Fatalf("failed creating superuser: %e", err)
-        return
-    }
-    if exists {
-        return
+        log.Fatalf("failed opening connection to %s: %v", Config.DB.Driver, err)
     }
 
-    userRole, err := db.Role.Query().Order(role.ByCreatedAt(sql.OrderAsc())).First(ctx)
-    if err != nil {
-        log.Fatalf("failed creating superuser: %e", err)
-        return
-    }
+    return db
+}
 
-    _, err = db.User.Create().
-        SetName("admin").
-        SetPassword(hashing.HashPassword("admin")).
-        SetEmail("admin@admin.com").
-        SetRole(userRole).
-        Save(ctx)
+// InitDB connects to the configured database, exiting if its schema isn't up-to-date
+func InitDB() *ent.Client {
+    db, _ := InitDBWithPool()
+
+    return db
+}
+
+// InitDBWithPool is InitDB also returning the underlying connection pool, e.g. for health checks
+func InitDBWithPool() (*ent.Client, *sql.DB) {
+    ctx := context.Background()
+
+    sqlDB := OpenDB()
 
+    // the schema is only changed by `panel migrate`, refuse to run against an outdated one
+    migrator, err := NewMigrator(sqlDB, Config.DB.Driver)
     if err != nil {
-        log.Fatalf("failed creating superuser: %e", err)
-        return
+        log.Fatalf("failed reading migrations: %v", err)
     }
+    if err := migrator.Check(ctx); err != nil {
+        log.Fatalf("failed checking database schema: %v", err)
+    }
+
+    drv := tracing.NewDriver(entsql.OpenDB(Config.DB.EntDialect(), sqlDB))
+    db := ent.NewClient(ent.Driver(metrics.NewDriver(drv)))
+
+    // the first user is created by the setup flow, see services.CompleteSetup
+    createSuperuserRole(db, ctx)
+
+    return db, sqlDB
 }
+
 func createSuperuserRole(db *ent.Client, ctx context.Context) {
-    exists, err := db.Role.Query().Where(role.Name("superuser")).Exist(ctx)
+    exists, err := db.Role.Query().Where(role.Name(SuperuserRoleName)).Exist(ctx)
 
     if err != nil {
         log.Fatalf("failed creating superuser role: %e", err)
@@ -88,7 +120,7 @@ func createSuperuserRole(db *ent.Client, ctx context.Context) {
     }
 
     _, err = db.Role.Create().
-        SetName("superuser").
+        SetName(SuperuserRoleName).
         SetPermissions([]string{"*"}).
         Save(ctx)
 
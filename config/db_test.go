@@ -0,0 +1,41 @@
+package config
+
+import (
+    "testing"
+)
+
+func TestDataSourceName(t *testing.T) {
+    tests := []struct {
+        name string
+        cfg  DatabaseConfiguration
+        want string
+    }{
+        {
+            "dsn replaces the rest",
+            DatabaseConfiguration{Driver: DriverPostgres, DSN: "postgres://panel@db/panel", Host: "ignored"},
+            "postgres://panel@db/panel",
+        },
+        {
+            "postgres quotes values",
+            DatabaseConfiguration{Driver: DriverPostgres, Host: "db", Port: 5432, User: "panel", DBName: "panel", Password: `it's a \secret`, SSLMode: "disable"},
+            `host='db' port=5432 user='panel' dbname='panel' password='it\'s a \\secret' sslmode='disable'`,
+        },
+        {
+            "postgres client certificate",
+            DatabaseConfiguration{Driver: DriverPostgres, Host: "db", Port: 5432, SSLMode: "verify-full", SSLRootCert: "ca.pem", SSLCert: "client.pem", SSLKey: "client.key"},
+            `host='db' port=5432 user='' dbname='' password='' sslmode='verify-full' sslrootcert='ca.pem' sslcert='client.pem' sslkey='client.key'`,
+        },
+        {
+            "sqlite",
+            DatabaseConfiguration{Driver: DriverSQLite, Path: "panel.db"},
+            "file:panel.db?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := tt.cfg.DataSourceName(); got != tt.want {
+                t.Errorf("got %s, want %s", got, tt.want)
+            }
+        })
+    }
+}
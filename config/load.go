@@ -0,0 +1,309 @@
+package config
+
+import (
+	"fmt"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix prefixes the environment variables overriding config settings,
+// e.g. ENCEDEUS_DATABASE_PASSWORD overrides password in the database block
+const EnvPrefix = "ENCEDEUS_"
+
+// EnvFileSuffix makes an environment override read the setting from a file, e.g. ENCEDEUS_AUTH_JWT_SECRET_ACCESS_FILE
+const EnvFileSuffix = "_FILE"
+
+// settingSources records where each setting was set, keyed by "block.attribute"
+type settingSources struct {
+	ranges map[string]hcl.Range
+	envs   map[string]string
+	// failed holds the settings that couldn't be decoded, they aren't validated again
+	failed map[string]bool
+}
+
+// LoadConfig reads the config file at path on top of the defaults, applies environment overrides and validates the result.
+// All problems found are returned together instead of stopping at the first one.
+func LoadConfig(path string) (Configuration, hcl.Diagnostics) {
+	cfg := DefaultConfiguration()
+	sources := settingSources{
+		ranges: map[string]hcl.Range{},
+		envs:   map[string]string{},
+		failed: map[string]bool{},
+	}
+
+	file, diags := hclparse.NewParser().ParseHCLFile(path)
+	if diags.HasErrors() {
+		return cfg, diags
+	}
+
+	diags = append(diags, decodeBlocks(file.Body, newEvalContext(filepath.Dir(path)), &cfg, sources)...)
+	diags = append(diags, applyEnvOverrides(&cfg, sources)...)
+	sources.markFailed(diags)
+	diags = append(diags, cfg.Validate(sources)...)
+
+	return cfg, dedupeDiagnostics(diags)
+}
+
+// markFailed records the settings the diagnostics point at
+func (s settingSources) markFailed(diags hcl.Diagnostics) {
+	for _, diag := range diags {
+		if diag.Subject == nil {
+			continue
+		}
+
+		for setting, rng := range s.ranges {
+			if rng.Overlaps(*diag.Subject) {
+				s.failed[setting] = true
+			}
+		}
+	}
+}
+
+// dedupeDiagnostics drops errors pointing at the same place as a previous one,
+// e.g. a failed function call is also reported as an unknown value
+func dedupeDiagnostics(diags hcl.Diagnostics) hcl.Diagnostics {
+	var deduped hcl.Diagnostics
+	seen := map[hcl.Range]bool{}
+
+	for _, diag := range diags {
+		if diag.Subject != nil {
+			if seen[*diag.Subject] {
+				continue
+			}
+			seen[*diag.Subject] = true
+		}
+
+		deduped = append(deduped, diag)
+	}
+
+	return deduped
+}
+
+// decodeBlocks decodes each block into the matching, already defaulted, field of cfg
+// so that settings missing from the file keep their default values
+func decodeBlocks(body hcl.Body, evalCtx *hcl.EvalContext, cfg *Configuration, sources settingSources) hcl.Diagnostics {
+	schema, _ := gohcl.ImpliedBodySchema(cfg)
+	content, diags := body.Content(schema)
+
+	seen := map[string]hcl.Range{}
+	for _, block := range content.Blocks {
+		if previous, ok := seen[block.Type]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate block",
+				Detail:   fmt.Sprintf("A %s block was already defined at %s.", block.Type, previous),
+				Subject:  block.DefRange.Ptr(),
+			})
+			continue
+		}
+		seen[block.Type] = block.DefRange
+
+		blockValue := blockField(cfg, block.Type)
+		diags = append(diags, gohcl.DecodeBody(block.Body, evalCtx, blockValue.Addr().Interface())...)
+
+		attrs, _ := block.Body.JustAttributes()
+		for name, attr := range attrs {
+			sources.ranges[block.Type+"."+name] = attr.Range
+		}
+	}
+
+	return diags
+}
+
+// applyEnvOverrides sets every setting that has a matching ENCEDEUS_<BLOCK>_<ATTRIBUTE>(_FILE) environment variable
+func applyEnvOverrides(cfg *Configuration, sources settingSources) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	forEachSetting(cfg, func(block string, attr string, value reflect.Value) {
+		name := EnvPrefix + strings.ToUpper(block+"_"+attr)
+
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			fileName, fileOk := os.LookupEnv(name + EnvFileSuffix)
+			if !fileOk {
+				return
+			}
+
+			content, err := readSecretFile(fileName)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid environment override",
+					Detail:   fmt.Sprintf("Failed to read %s set by %s: %v.", fileName, name+EnvFileSuffix, err),
+				})
+				return
+			}
+
+			raw = content
+			name += EnvFileSuffix
+		}
+
+		if err := setFromString(value, raw); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid environment override",
+				Detail:   fmt.Sprintf("%s: %v.", name, err),
+			})
+			return
+		}
+
+		sources.envs[block+"."+attr] = name
+	})
+
+	return diags
+}
+
+// forEachSetting calls fn with every attribute of every block of cfg
+func forEachSetting(cfg *Configuration, fn func(block string, attr string, value reflect.Value)) {
+	forEachSettingField(cfg, func(block string, field reflect.StructField, value reflect.Value) {
+		fn(block, hclTagName(field), value)
+	})
+}
+
+func forEachSettingField(cfg *Configuration, fn func(block string, field reflect.StructField, value reflect.Value)) {
+	cfgValue := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < cfgValue.NumField(); i++ {
+		block := hclTagName(cfgValue.Type().Field(i))
+		blockValue := cfgValue.Field(i)
+
+		for j := 0; j < blockValue.NumField(); j++ {
+			fn(block, blockValue.Type().Field(j), blockValue.Field(j))
+		}
+	}
+}
+
+func blockField(cfg *Configuration, block string) reflect.Value {
+	cfgValue := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < cfgValue.NumField(); i++ {
+		if hclTagName(cfgValue.Type().Field(i)) == block {
+			return cfgValue.Field(i)
+		}
+	}
+
+	// the body schema is implied from Configuration, so this can't happen
+	panic("unknown config block " + block)
+}
+
+func hclTagName(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("hcl"), ",")[0]
+}
+
+func setFromString(value reflect.Value, raw string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int:
+		i, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		value.SetInt(int64(i))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		value.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		value.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("settings of type %s can't be overridden", value.Type())
+	}
+
+	return nil
+}
+
+// readSecretFile reads a secret stored in a file, ignoring the trailing newline most editors add
+func readSecretFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// newEvalContext returns the functions available in the config file,
+// relative file paths are resolved from the directory of the config file
+func newEvalContext(dir string) *hcl.EvalContext {
+	return &hcl.EvalContext{
+		Functions: map[string]function.Function{
+			"env":       envFunc,
+			"file":      fileFunc(dir),
+			"upper":     stdlib.UpperFunc,
+			"lower":     stdlib.LowerFunc,
+			"trimspace": stdlib.TrimSpaceFunc,
+			"format":    stdlib.FormatFunc,
+			"join":      stdlib.JoinFunc,
+			"split":     stdlib.SplitFunc,
+			"coalesce":  stdlib.CoalesceFunc,
+			"concat":    stdlib.ConcatFunc,
+			"min":       stdlib.MinFunc,
+			"max":       stdlib.MaxFunc,
+		},
+	}
+}
+
+// envFunc returns the value of an environment variable, or the optional default if it isn't set
+var envFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "name", Type: cty.String},
+	},
+	VarParam: &function.Parameter{Name: "default", Type: cty.String},
+	Type:     function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		name := args[0].AsString()
+		if value, ok := os.LookupEnv(name); ok {
+			return cty.StringVal(value), nil
+		}
+		if len(args) > 1 {
+			return args[1], nil
+		}
+
+		return cty.NilVal, fmt.Errorf("environment variable %s is not set", name)
+	},
+})
+
+// fileFunc returns a function reading secrets from files, e.g. Docker or Kubernetes secrets
+func fileFunc(dir string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			path := args[0].AsString()
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+
+			content, err := readSecretFile(path)
+			if err != nil {
+				return cty.NilVal, err
+			}
+
+			return cty.StringVal(content), nil
+		},
+	})
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	testAccessSecret  = "access-secret-that-is-long-enough-1"
+	testRefreshSecret = "refresh-secret-that-is-long-enough-2"
+)
+
+// writeConfig writes a config file with the JWT secrets every valid config needs followed by extra
+func writeConfig(t *testing.T, extra string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.hcl")
+	content := `auth {
+  jwt_secret_access = "` + testAccessSecret + `"
+  jwt_secret_refresh = "` + testRefreshSecret + `"
+}
+` + extra
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadConfigKeepsDefaults(t *testing.T) {
+	cfg, diags := LoadConfig(writeConfig(t, `server {
+  port = 9000
+}
+`))
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	if cfg.Server.Port != 9000 {
+		t.Errorf("server.port is %d, want 9000 from the file", cfg.Server.Port)
+	}
+	defaults := DefaultConfiguration()
+	if cfg.Server.Host != defaults.Server.Host || cfg.DB.Port != defaults.DB.Port || cfg.Log.Level != defaults.Log.Level {
+		t.Errorf("settings missing from the file lost their defaults: %+v", cfg)
+	}
+}
+
+func TestLoadConfigEnvOverrides(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("file-secret-that-is-long-enough-33\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("ENCEDEUS_SERVER_PORT", "9100")
+	t.Setenv("ENCEDEUS_SERVER_CORS_ORIGINS", "https://a.example.com,https://b.example.com")
+	t.Setenv("ENCEDEUS_AUTH_JWT_SECRET_ACCESS_FILE", secretFile)
+	// the variable itself wins over its _FILE counterpart
+	t.Setenv("ENCEDEUS_DATABASE_PASSWORD", "from-env")
+	t.Setenv("ENCEDEUS_DATABASE_PASSWORD_FILE", secretFile)
+
+	cfg, diags := LoadConfig(writeConfig(t, `server {
+  port = 9000
+}
+`))
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	if cfg.Server.Port != 9100 {
+		t.Errorf("server.port is %d, want 9100 from the environment", cfg.Server.Port)
+	}
+	if got := strings.Join(cfg.Server.CORSOrigins, " "); got != "https://a.example.com https://b.example.com" {
+		t.Errorf("server.cors_origins is %s", got)
+	}
+	if cfg.Auth.JWTSecretAccess != "file-secret-that-is-long-enough-33" {
+		t.Errorf("auth.jwt_secret_access is %q, want the file's content without the newline", cfg.Auth.JWTSecretAccess)
+	}
+	if cfg.DB.Password != "from-env" {
+		t.Errorf("database.password is %q, want the variable over the file", cfg.DB.Password)
+	}
+}
+
+func TestLoadConfigFunctions(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "db_password"), []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_DB_USER", "panel")
+
+	path := writeConfig(t, `database {
+  user = env("TEST_DB_USER")
+  name = env("TEST_DB_NAME_UNSET", "fallback")
+  password = trimspace(file("`+filepath.ToSlash(filepath.Join(dir, "db_password"))+`"))
+}
+`)
+	cfg, diags := LoadConfig(path)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	if cfg.DB.User != "panel" || cfg.DB.DBName != "fallback" || cfg.DB.Password != "from-file" {
+		t.Errorf("got user %q, name %q and password %q", cfg.DB.User, cfg.DB.DBName, cfg.DB.Password)
+	}
+}
+
+func TestLoadConfigReportsEveryProblem(t *testing.T) {
+	t.Setenv("ENCEDEUS_SERVER_RATE_LIMIT", "fast")
+	t.Setenv("ENCEDEUS_DATABASE_PORT", "0")
+
+	_, diags := LoadConfig(writeConfig(t, `server {
+  port = 70000
+}
+
+log {
+  level = "loud"
+}
+`))
+
+	var messages []string
+	for _, diag := range diags {
+		messages = append(messages, diag.Summary+": "+diag.Detail)
+	}
+	all := strings.Join(messages, "\n")
+	for _, want := range []string{"server.port", "log.level", "ENCEDEUS_SERVER_RATE_LIMIT", "set by ENCEDEUS_DATABASE_PORT"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("diagnostics don't mention %s:\n%s", want, all)
+		}
+	}
+}
@@ -0,0 +1,504 @@
+package config
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "github.com/Encedeus/panel/ent/migrate/migrations"
+    "io/fs"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// migrationLockID is the key of the advisory lock held while migrating, it's arbitrary but must never change
+const migrationLockID = 7_355_608
+
+var (
+    ErrDatabaseNotMigrated  = errors.New("database schema isn't up to date, run `panel migrate up`")
+    ErrNoMigrationApplied   = errors.New("no migration to roll back")
+    ErrDatabaseNotBaselined = errors.New("database was created before the panel had migrations, run `panel migrate up --baseline`")
+    ErrAlreadyBaselined     = errors.New("database already has migrations applied, run `panel migrate up` without --baseline")
+)
+
+var (
+    // createTablePattern finds the tables created by a migration along with their definitions,
+    // quoted with double quotes or backticks
+    createTablePattern = regexp.MustCompile("CREATE TABLE [`\"]([^`\"]+)[`\"] \\((.*)\\);")
+    // columnPattern finds the columns in the definition of a table, skipping its keys and constraints
+    columnPattern = regexp.MustCompile("(?:^|, )[`\"]([^`\"]+)[`\"] [a-z]")
+    // addColumnPattern finds the columns a migration adds to existing tables
+    addColumnPattern = regexp.MustCompile("ALTER TABLE [`\"]([^`\"]+)[`\"] ADD COLUMN [`\"]([^`\"]+)[`\"]")
+    // createIndexPattern finds the indexes created by a migration
+    createIndexPattern = regexp.MustCompile("CREATE (?:UNIQUE )?INDEX (?:IF NOT EXISTS )?[`\"]([^`\"]+)[`\"]")
+)
+
+// Migration is a versioned SQL migration along with whether it's applied to the database
+type Migration struct {
+    Version   int64
+    Name      string
+    AppliedAt *time.Time
+
+    up   string
+    down string
+}
+
+// Migrator applies the embedded migrations of a driver and records them in the schema_migrations table
+type Migrator struct {
+    db         *sql.DB
+    driver     string
+    migrations []*Migration
+}
+
+func NewMigrator(db *sql.DB, driver string) (*Migrator, error) {
+    dir, err := fs.Sub(migrations.FS, driver)
+    if err != nil {
+        return nil, err
+    }
+
+    ms, err := readMigrations(dir)
+    if err != nil {
+        return nil, err
+    }
+    if len(ms) == 0 {
+        return nil, fmt.Errorf("no migrations for driver %s", driver)
+    }
+
+    return &Migrator{
+        db:         db,
+        driver:     driver,
+        migrations: ms,
+    }, nil
+}
+
+// readMigrations reads every <version>_<name>.up.sql file and its .down.sql counterpart sorted by version
+func readMigrations(fsys fs.FS) ([]*Migration, error) {
+    files, err := fs.Glob(fsys, "*.up.sql")
+    if err != nil {
+        return nil, err
+    }
+
+    var ms []*Migration
+    for _, file := range files {
+        base := strings.TrimSuffix(file, ".up.sql")
+        rawVersion, name, _ := strings.Cut(base, "_")
+        version, err := strconv.ParseInt(rawVersion, 10, 64)
+        if err != nil {
+            return nil, fmt.Errorf("migration %s doesn't start with a version: %w", file, err)
+        }
+
+        up, err := fs.ReadFile(fsys, file)
+        if err != nil {
+            return nil, err
+        }
+        down, err := fs.ReadFile(fsys, base+".down.sql")
+        if err != nil {
+            return nil, fmt.Errorf("migration %s has no down migration: %w", file, err)
+        }
+
+        ms = append(ms, &Migration{
+            Version: version,
+            Name:    name,
+            up:      string(up),
+            down:    string(down),
+        })
+    }
+
+    sort.Slice(ms, func(i, j int) bool {
+        return ms[i].Version < ms[j].Version
+    })
+
+    return ms, nil
+}
+
+// Status returns every known migration with the time it was applied at, if it was
+func (m *Migrator) Status(ctx context.Context) ([]*Migration, error) {
+    applied, err := m.applied(ctx, m.db)
+    if err != nil {
+        return nil, err
+    }
+
+    status := make([]*Migration, len(m.migrations))
+    for i, migration := range m.migrations {
+        withStatus := *migration
+        if appliedAt, ok := applied[migration.Version]; ok {
+            withStatus.AppliedAt = &appliedAt
+        }
+        status[i] = &withStatus
+    }
+
+    return status, nil
+}
+
+// Check returns ErrDatabaseNotMigrated if any migration hasn't been applied,
+// or ErrDatabaseNotBaselined if the database predates the migrations
+func (m *Migrator) Check(ctx context.Context) error {
+    status, err := m.Status(ctx)
+    if err != nil {
+        return err
+    }
+    // migrations are applied and rolled back in order, so the first one is applied if any is
+    if err = m.checkBaselined(ctx, m.db, status[0].AppliedAt != nil); err != nil {
+        return err
+    }
+
+    for _, migration := range status {
+        if migration.AppliedAt == nil {
+            return ErrDatabaseNotMigrated
+        }
+    }
+
+    return nil
+}
+
+// Up applies every pending migration in order and returns the applied ones.
+// On postgres each migration runs in its own transaction, on sqlite they all run in the one holding the lock.
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+    var done []*Migration
+    err := m.withLock(ctx, func(conn *sql.Conn) error {
+        applied, err := m.applied(ctx, conn)
+        if err != nil {
+            return err
+        }
+        if err = m.checkBaselined(ctx, conn, len(applied) != 0); err != nil {
+            return err
+        }
+
+        for _, migration := range m.migrations {
+            if _, ok := applied[migration.Version]; ok {
+                continue
+            }
+
+            err := m.run(ctx, conn, migration.up,
+                "INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)",
+                migration.Version, migration.Name, time.Now())
+            if err != nil {
+                return fmt.Errorf("failed applying migration %d_%s: %w", migration.Version, migration.Name, err)
+            }
+            done = append(done, migration)
+        }
+
+        return nil
+    })
+
+    return done, err
+}
+
+// Baseline records the first migration as applied without running it, adopting a database whose schema was created
+// by `db.Schema.Create` before the panel had migrations. It fails unless no migration was applied yet and the database
+// has exactly what the first migration creates, see checkSchema. The migrations after the first one are left to Up.
+func (m *Migrator) Baseline(ctx context.Context) (*Migration, error) {
+    first := m.migrations[0]
+    err := m.withLock(ctx, func(conn *sql.Conn) error {
+        applied, err := m.applied(ctx, conn)
+        if err != nil {
+            return err
+        }
+        if len(applied) != 0 {
+            return ErrAlreadyBaselined
+        }
+
+        problems, err := m.checkSchema(ctx, conn)
+        if err != nil {
+            return err
+        }
+        if len(problems) != 0 {
+            return fmt.Errorf("database doesn't have the schema of migration %d_%s: %s",
+                first.Version, first.Name, strings.Join(problems, ", "))
+        }
+
+        _, err = conn.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)",
+            first.Version, first.Name, time.Now())
+
+        return err
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return first, nil
+}
+
+// checkSchema compares the database with the first migration, returning every table, column and index it creates
+// that's missing and everything the later migrations create that's already there. A database upgraded only partly
+// by a newer `db.Schema.Create` would otherwise be recorded as migrated while missing part of the schema.
+func (m *Migrator) checkSchema(ctx context.Context, q queryer) ([]string, error) {
+    _, problems, err := m.inspect(ctx, q, m.migrations[0])
+    if err != nil {
+        return nil, err
+    }
+
+    for _, migration := range m.migrations[1:] {
+        present, _, err := m.inspect(ctx, q, migration)
+        if err != nil {
+            return nil, err
+        }
+        for _, object := range present {
+            problems = append(problems, fmt.Sprintf("already has the %s of migration %d_%s", object, migration.Version, migration.Name))
+        }
+    }
+
+    return problems, nil
+}
+
+// inspect looks up the tables, columns and indexes a migration creates, returning the ones present in the database
+// and the missing ones. The columns of a table are only looked up if the table exists.
+func (m *Migrator) inspect(ctx context.Context, q queryer, migration *Migration) (present []string, missing []string, err error) {
+    add := func(exists bool, object string) {
+        if exists {
+            present = append(present, object)
+        } else {
+            missing = append(missing, "misses the "+object)
+        }
+    }
+
+    for _, table := range createTablePattern.FindAllStringSubmatch(migration.up, -1) {
+        exists, err := m.tableExists(ctx, q, table[1])
+        if err != nil {
+            return nil, nil, err
+        }
+        add(exists, "table "+table[1])
+        if !exists {
+            continue
+        }
+
+        for _, column := range columnPattern.FindAllStringSubmatch(table[2], -1) {
+            exists, err := m.columnExists(ctx, q, table[1], column[1])
+            if err != nil {
+                return nil, nil, err
+            }
+            if !exists {
+                missing = append(missing, fmt.Sprintf("misses the column %s.%s", table[1], column[1]))
+            }
+        }
+    }
+
+    for _, column := range addColumnPattern.FindAllStringSubmatch(migration.up, -1) {
+        exists, err := m.columnExists(ctx, q, column[1], column[2])
+        if err != nil {
+            return nil, nil, err
+        }
+        add(exists, fmt.Sprintf("column %s.%s", column[1], column[2]))
+    }
+
+    for _, index := range createIndexPattern.FindAllStringSubmatch(migration.up, -1) {
+        exists, err := m.indexExists(ctx, q, index[1])
+        if err != nil {
+            return nil, nil, err
+        }
+        add(exists, "index "+index[1])
+    }
+
+    return present, missing, nil
+}
+
+// checkBaselined returns ErrDatabaseNotBaselined if no migration was applied to a database that already has users,
+// running the first migration on it would fail since its tables exist
+func (m *Migrator) checkBaselined(ctx context.Context, q queryer, anyApplied bool) error {
+    if anyApplied {
+        return nil
+    }
+
+    exists, err := m.tableExists(ctx, q, "users")
+    if err != nil {
+        return err
+    }
+    if exists {
+        return ErrDatabaseNotBaselined
+    }
+
+    return nil
+}
+
+// Down rolls back the last steps applied migrations in reverse order and returns the rolled back ones
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+    var done []*Migration
+    err := m.withLock(ctx, func(conn *sql.Conn) error {
+        applied, err := m.applied(ctx, conn)
+        if err != nil {
+            return err
+        }
+
+        for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+            migration := m.migrations[i]
+            if _, ok := applied[migration.Version]; !ok {
+                continue
+            }
+
+            err := m.run(ctx, conn, migration.down,
+                "DELETE FROM schema_migrations WHERE version = $1",
+                migration.Version)
+            if err != nil {
+                return fmt.Errorf("failed rolling back migration %d_%s: %w", migration.Version, migration.Name, err)
+            }
+            done = append(done, migration)
+        }
+
+        if len(done) == 0 {
+            return ErrNoMigrationApplied
+        }
+
+        return nil
+    })
+
+    return done, err
+}
+
+// run executes the statements of a migration and records it in a single transaction
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, statements string, record string, args ...any) error {
+    // sqlite migrations already run in the transaction holding the lock
+    if m.driver == DriverSQLite {
+        if _, err := conn.ExecContext(ctx, statements); err != nil {
+            return err
+        }
+        _, err := conn.ExecContext(ctx, record, args...)
+
+        return err
+    }
+
+    tx, err := conn.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+
+    if _, err := tx.ExecContext(ctx, statements); err != nil {
+        _ = tx.Rollback()
+        return err
+    }
+    if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+        _ = tx.Rollback()
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// withLock runs fn while holding the migration lock, so concurrently started instances migrate one at a time
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+    conn, err := m.db.Conn(ctx)
+    if err != nil {
+        return err
+    }
+    defer conn.Close()
+
+    if m.driver == DriverSQLite {
+        return m.withSQLiteLock(ctx, conn, fn)
+    }
+
+    // advisory locks are held by the session, so the lock and the migrations must use the same connection
+    if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+        return fmt.Errorf("failed acquiring migration lock: %w", err)
+    }
+    defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+    if err := m.createTable(ctx, conn); err != nil {
+        return err
+    }
+
+    return fn(conn)
+}
+
+// withSQLiteLock runs fn in an immediate transaction, which takes the database's write lock right away
+func (m *Migrator) withSQLiteLock(ctx context.Context, conn *sql.Conn, fn func(conn *sql.Conn) error) error {
+    if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+        return fmt.Errorf("failed acquiring migration lock: %w", err)
+    }
+
+    err := m.createTable(ctx, conn)
+    if err == nil {
+        err = fn(conn)
+    }
+    if err != nil {
+        _, _ = conn.ExecContext(context.Background(), "ROLLBACK")
+        return err
+    }
+
+    _, err = conn.ExecContext(ctx, "COMMIT")
+
+    return err
+}
+
+func (m *Migrator) createTable(ctx context.Context, conn *sql.Conn) error {
+    timeType := "timestamptz"
+    if m.driver == DriverSQLite {
+        timeType = "datetime"
+    }
+
+    _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+        version bigint NOT NULL PRIMARY KEY,
+        name character varying NOT NULL,
+        applied_at `+timeType+` NOT NULL
+    )`)
+    if err != nil {
+        return fmt.Errorf("failed creating schema_migrations table: %w", err)
+    }
+
+    return nil
+}
+
+type queryer interface {
+    QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+    QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// applied returns the versions recorded in schema_migrations, treating a missing table as no migrations applied
+func (m *Migrator) applied(ctx context.Context, q queryer) (map[int64]time.Time, error) {
+    applied := make(map[int64]time.Time)
+
+    exists, err := m.tableExists(ctx, q, "schema_migrations")
+    if err != nil || !exists {
+        return applied, err
+    }
+
+    rows, err := q.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var version int64
+        var appliedAt time.Time
+        if err := rows.Scan(&version, &appliedAt); err != nil {
+            return nil, err
+        }
+        applied[version] = appliedAt
+    }
+
+    return applied, rows.Err()
+}
+
+func (m *Migrator) tableExists(ctx context.Context, q queryer, table string) (bool, error) {
+    if m.driver == DriverSQLite {
+        return exists(ctx, q, "SELECT count(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = $1", table)
+    }
+
+    return exists(ctx, q, "SELECT to_regclass($1) IS NOT NULL", table)
+}
+
+func (m *Migrator) indexExists(ctx context.Context, q queryer, index string) (bool, error) {
+    if m.driver == DriverSQLite {
+        return exists(ctx, q, "SELECT count(*) > 0 FROM sqlite_master WHERE type = 'index' AND name = $1", index)
+    }
+
+    return exists(ctx, q, "SELECT to_regclass($1) IS NOT NULL", index)
+}
+
+func (m *Migrator) columnExists(ctx context.Context, q queryer, table string, column string) (bool, error) {
+    if m.driver == DriverSQLite {
+        return exists(ctx, q, "SELECT count(*) > 0 FROM pragma_table_info($1) WHERE name = $2", table, column)
+    }
+
+    return exists(ctx, q, `SELECT count(*) > 0 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2`, table, column)
+}
+
+func exists(ctx context.Context, q queryer, query string, args ...any) (bool, error) {
+    var exists bool
+    err := q.QueryRowContext(ctx, query, args...).Scan(&exists)
+
+    return exists, err
+}
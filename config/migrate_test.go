@@ -0,0 +1,225 @@
+package config
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "path/filepath"
+    "strings"
+    "sync"
+    "testing"
+)
+
+// openTestDB opens an empty SQLite database in a file, so concurrent connections share it like in production
+func openTestDB(t *testing.T) *sql.DB {
+    t.Helper()
+
+    cfg := DatabaseConfiguration{Driver: DriverSQLite, Path: filepath.Join(t.TempDir(), "panel.db")}
+    db, err := sql.Open(DriverSQLite, cfg.DataSourceName())
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() {
+        _ = db.Close()
+    })
+
+    return db
+}
+
+// createSchema runs the up statements of migrations without recording them, like db.Schema.Create did
+func createSchema(t *testing.T, db *sql.DB, migrations ...*Migration) {
+    t.Helper()
+
+    for _, migration := range migrations {
+        if _, err := db.Exec(migration.up); err != nil {
+            t.Fatal(err)
+        }
+    }
+}
+
+func newTestMigrator(t *testing.T, db *sql.DB) *Migrator {
+    t.Helper()
+
+    m, err := NewMigrator(db, DriverSQLite)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return m
+}
+
+func TestMigratorCheck(t *testing.T) {
+    ctx := context.Background()
+    m := newTestMigrator(t, openTestDB(t))
+
+    if err := m.Check(ctx); !errors.Is(err, ErrDatabaseNotMigrated) {
+        t.Errorf("empty database: got %v, want %v", err, ErrDatabaseNotMigrated)
+    }
+
+    applied, err := m.Up(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(applied) != len(m.migrations) {
+        t.Errorf("applied %d migrations, want %d", len(applied), len(m.migrations))
+    }
+    if err = m.Check(ctx); err != nil {
+        t.Errorf("migrated database: got %v, want nil", err)
+    }
+
+    if _, err = m.Down(ctx, 1); err != nil {
+        t.Fatal(err)
+    }
+    if err = m.Check(ctx); !errors.Is(err, ErrDatabaseNotMigrated) {
+        t.Errorf("rolled back database: got %v, want %v", err, ErrDatabaseNotMigrated)
+    }
+}
+
+func TestMigratorLockAppliesOnce(t *testing.T) {
+    ctx := context.Background()
+    db := openTestDB(t)
+
+    // like several panels starting at once, each with its own connection pool
+    var wg sync.WaitGroup
+    counts := make([]int, 4)
+    errs := make([]error, len(counts))
+    for i := range counts {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+
+            applied, err := newTestMigrator(t, db).Up(ctx)
+            counts[i], errs[i] = len(applied), err
+        }(i)
+    }
+    wg.Wait()
+
+    total := 0
+    for i, err := range errs {
+        if err != nil {
+            t.Errorf("migrator %d failed: %v", i, err)
+        }
+        total += counts[i]
+    }
+    if want := len(newTestMigrator(t, db).migrations); total != want {
+        t.Errorf("applied %d migrations in total, want each of the %d once", total, want)
+    }
+}
+
+func TestMigratorBaseline(t *testing.T) {
+    ctx := context.Background()
+    db := openTestDB(t)
+    m := newTestMigrator(t, db)
+
+    if _, err := m.Baseline(ctx); err == nil {
+        t.Error("baselined an empty database")
+    }
+
+    // the schema db.Schema.Create left behind, without the schema_migrations table
+    first := m.migrations[0]
+    createSchema(t, db, first)
+
+    if err := m.Check(ctx); !errors.Is(err, ErrDatabaseNotBaselined) {
+        t.Errorf("check: got %v, want %v", err, ErrDatabaseNotBaselined)
+    }
+    if _, err := m.Up(ctx); !errors.Is(err, ErrDatabaseNotBaselined) {
+        t.Errorf("up: got %v, want %v", err, ErrDatabaseNotBaselined)
+    }
+
+    baselined, err := m.Baseline(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if baselined.Version != first.Version {
+        t.Errorf("baselined %d, want %d", baselined.Version, first.Version)
+    }
+    if _, err = m.Up(ctx); err != nil {
+        t.Fatal(err)
+    }
+    if err = m.Check(ctx); err != nil {
+        t.Errorf("baselined and migrated database: got %v, want nil", err)
+    }
+
+    if _, err = m.Baseline(ctx); !errors.Is(err, ErrAlreadyBaselined) {
+        t.Errorf("baselined twice: got %v, want %v", err, ErrAlreadyBaselined)
+    }
+}
+
+func TestMigratorBaselineChecksSchema(t *testing.T) {
+    ctx := context.Background()
+
+    db := openTestDB(t)
+    m := newTestMigrator(t, db)
+    createSchema(t, db, m.migrations[0])
+    if _, err := db.Exec(`ALTER TABLE "api_keys" DROP COLUMN "description"`); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := m.Baseline(ctx); err == nil || !strings.Contains(err.Error(), "api_keys.description") {
+        t.Errorf("baselined a database missing a column: got %v", err)
+    }
+
+    // upgraded by a newer db.Schema.Create, but not all the way
+    db = openTestDB(t)
+    m = newTestMigrator(t, db)
+    createSchema(t, db, m.migrations[:2]...)
+    if _, err := m.Baseline(ctx); err == nil || !strings.Contains(err.Error(), "role_children") {
+        t.Errorf("baselined a partly upgraded database: got %v", err)
+    }
+
+    status, err := m.Status(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if status[0].AppliedAt != nil {
+        t.Error("a refused baseline recorded the first migration")
+    }
+}
+
+func TestMigratorNamesUniqueAmongActive(t *testing.T) {
+    ctx := context.Background()
+    db := openTestDB(t)
+    m := newTestMigrator(t, db)
+
+    createSchema(t, db, m.migrations[0])
+    if _, err := m.Baseline(ctx); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := m.Up(ctx); err != nil {
+        t.Fatal(err)
+    }
+
+    insert := `INSERT INTO "roles" ("id", "created_at", "updated_at", "deleted_at", "name") VALUES (?, 0, 0, ?, 'admin')`
+    if _, err := db.Exec(insert, "1", 0); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := db.Exec(insert, "2", nil); err != nil {
+        t.Errorf("reusing the name of a deleted role: %v", err)
+    }
+    if _, err := db.Exec(insert, "3", nil); err == nil {
+        t.Error("two active roles share a name")
+    }
+}
+
+func TestMigratorDownAndUpAgain(t *testing.T) {
+    ctx := context.Background()
+    m := newTestMigrator(t, openTestDB(t))
+
+    if _, err := m.Up(ctx); err != nil {
+        t.Fatal(err)
+    }
+    rolledBack, err := m.Down(ctx, len(m.migrations))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(rolledBack) != len(m.migrations) {
+        t.Errorf("rolled back %d migrations, want %d", len(rolledBack), len(m.migrations))
+    }
+
+    // every down migration has to leave nothing behind for its up migration to trip over
+    if _, err = m.Up(ctx); err != nil {
+        t.Fatal(err)
+    }
+    if err = m.Check(ctx); err != nil {
+        t.Errorf("migrated again: got %v, want nil", err)
+    }
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+var current atomic.Pointer[Configuration]
+
+var (
+	reloadHooksMu sync.Mutex
+	reloadHooks   []func(cfg *Configuration)
+)
+
+// Current returns the settings currently in effect, including the ones changed by a reload
+func Current() *Configuration {
+	if cfg := current.Load(); cfg != nil {
+		return cfg
+	}
+
+	return &Config
+}
+
+// OnReload registers fn to be called with the new settings after every successful reload
+func OnReload(fn func(cfg *Configuration)) {
+	reloadHooksMu.Lock()
+	defer reloadHooksMu.Unlock()
+
+	reloadHooks = append(reloadHooks, fn)
+}
+
+// Reload re-parses the config file at path and swaps in the settings that can change live.
+// An invalid file is reported and ignored, keeping the settings currently in effect.
+func Reload(path string) error {
+	loaded, diags := LoadConfig(path)
+	if diags.HasErrors() {
+		WriteDiagnostics(os.Stderr, diags)
+		return fmt.Errorf("config file %s is invalid, keeping the current configuration", path)
+	}
+
+	next := *Current()
+	changed, needRestart := mergeLiveSettings(&next, &loaded)
+	for _, setting := range needRestart {
+		log.Printf("Setting %s changed, restart the panel for it to take effect", setting)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	current.Store(&next)
+	log.Printf("Reloaded configuration, changed settings: %v", changed)
+
+	reloadHooksMu.Lock()
+	hooks := append([]func(cfg *Configuration){}, reloadHooks...)
+	reloadHooksMu.Unlock()
+	for _, hook := range hooks {
+		hook(&next)
+	}
+
+	return nil
+}
+
+// mergeLiveSettings copies every live setting of loaded that differs into cfg,
+// returning the names of the copied settings and of the changed ones that require a restart
+func mergeLiveSettings(cfg *Configuration, loaded *Configuration) (changed []string, needRestart []string) {
+	forEachSettingField(cfg, func(block string, field reflect.StructField, value reflect.Value) {
+		loadedField := blockField(loaded, block).FieldByName(field.Name)
+		if reflect.DeepEqual(value.Interface(), loadedField.Interface()) {
+			return
+		}
+
+		name := block + "." + hclTagName(field)
+		if field.Tag.Get("reload") != "live" {
+			needRestart = append(needRestart, name)
+			return
+		}
+
+		value.Set(loadedField)
+		changed = append(changed, name)
+	})
+
+	return changed, needRestart
+}
+
+// WatchConfig reloads the config file at path when the process receives SIGHUP
+// or when the file's modification time changes, checked every pollInterval
+func WatchConfig(ctx context.Context, path string, pollInterval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	lastModified := modTime(path)
+	reload := func() {
+		lastModified = modTime(path)
+		if err := Reload(path); err != nil {
+			log.Println(err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Printf("Received SIGHUP, reloading %s", path)
+			reload()
+		case <-ticker.C:
+			if modified := modTime(path); !modified.Equal(lastModified) {
+				log.Printf("Config file %s changed, reloading", path)
+				reload()
+			}
+		}
+	}
+}
+
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+// useConfig makes cfg the settings in effect for the duration of the test
+func useConfig(t *testing.T, cfg Configuration) {
+	t.Helper()
+
+	previous := current.Load()
+	current.Store(&cfg)
+
+	reloadHooksMu.Lock()
+	hooks := reloadHooks
+	reloadHooksMu.Unlock()
+
+	t.Cleanup(func() {
+		current.Store(previous)
+
+		reloadHooksMu.Lock()
+		reloadHooks = hooks
+		reloadHooksMu.Unlock()
+	})
+}
+
+func TestReloadAppliesLiveSettings(t *testing.T) {
+	path := writeConfig(t, "")
+	cfg, diags := LoadConfig(path)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	useConfig(t, cfg)
+
+	var reloaded *Configuration
+	OnReload(func(cfg *Configuration) {
+		reloaded = cfg
+	})
+
+	if err := os.WriteFile(path, []byte(`auth {
+  jwt_secret_access = "`+testAccessSecret+`"
+  jwt_secret_refresh = "`+testRefreshSecret+`"
+}
+
+server {
+  port = 9000
+  rate_limit = 5
+}
+
+log {
+  level = "debug"
+}
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Reload(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got := Current()
+	if got.Server.RateLimit != 5 || got.Log.Level != "debug" {
+		t.Errorf("live settings weren't applied: rate_limit %v, level %q", got.Server.RateLimit, got.Log.Level)
+	}
+	if got.Server.Port != cfg.Server.Port {
+		t.Errorf("server.port changed to %d without a restart", got.Server.Port)
+	}
+	if reloaded != got {
+		t.Error("reload hook wasn't called with the new settings")
+	}
+}
+
+func TestReloadKeepsSettingsOnInvalidFile(t *testing.T) {
+	path := writeConfig(t, "")
+	cfg, diags := LoadConfig(path)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	useConfig(t, cfg)
+	before := Current()
+
+	if err := os.WriteFile(path, []byte("log {\n  level = \"loud\"\n}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Reload(path); err == nil {
+		t.Error("reloaded an invalid file")
+	}
+	if Current() != before {
+		t.Error("invalid file replaced the settings in effect")
+	}
+}
+
+func TestMergeLiveSettings(t *testing.T) {
+	cfg := DefaultConfiguration()
+	loaded := DefaultConfiguration()
+	loaded.Server.CORSOrigins = []string{"https://example.com"}
+	loaded.Server.Host = "0.0.0.0"
+
+	changed, needRestart := mergeLiveSettings(&cfg, &loaded)
+	if len(changed) != 1 || changed[0] != "server.cors_origins" {
+		t.Errorf("changed %v, want [server.cors_origins]", changed)
+	}
+	if len(needRestart) != 1 || needRestart[0] != "server.host" {
+		t.Errorf("need restart %v, want [server.host]", needRestart)
+	}
+	if cfg.Server.Host == loaded.Server.Host {
+		t.Error("copied server.host, which requires a restart")
+	}
+}
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"fmt"
+	"github.com/hashicorp/hcl/v2"
+	"golang.org/x/exp/slices"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// minSecretLength is the minimum length of JWT secrets, HS256 keys shouldn't be shorter than the hash
+const minSecretLength = 32
+
+// Validate checks every setting and returns a diagnostic for each invalid one,
+// pointing at the line or environment variable the value came from
+func (c *Configuration) Validate(sources settingSources) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	invalid := func(setting string, format string, args ...any) {
+		if sources.failed[setting] {
+			return
+		}
+
+		diags = append(diags, sources.diagnostic(setting, fmt.Sprintf(format, args...)))
+	}
+
+	if c.Server.Host == "" {
+		invalid("server.host", "host can't be empty")
+	}
+	if !isPort(c.Server.Port) {
+		invalid("server.port", "%d is not a valid port", c.Server.Port)
+	}
+	for _, origin := range c.Server.CORSOrigins {
+		if u, err := url.Parse(origin); origin != "*" && (err != nil || u.Scheme == "" || u.Host == "") {
+			invalid("server.cors_origins", "%q is not a valid origin", origin)
+		}
+	}
+	if c.Server.RateLimit < 0 {
+		invalid("server.rate_limit", "rate limit can't be negative")
+	}
+	if c.Server.RateLimitBurst < 1 {
+		invalid("server.rate_limit_burst", "burst must be at least 1")
+	}
+	if !isDuration(c.Server.DrainTimeout) {
+		invalid("server.drain_timeout", "%q is not a valid duration", c.Server.DrainTimeout)
+	}
+	if c.Server.MaxBodySize < 1 {
+		invalid("server.max_body_size", "size must be at least 1 byte")
+	}
+	if !slices.Contains([]string{UnknownFieldsReject, UnknownFieldsDiscard}, c.Server.UnknownFields) {
+		invalid("server.unknown_fields", "%q is not one of reject or discard", c.Server.UnknownFields)
+	}
+	if _, err := c.Server.LegacyAPISunsetTime(); err != nil {
+		invalid("server.legacy_api_sunset", "%q is not a date like 2027-04-19", c.Server.LegacyAPISunset)
+	}
+	if (c.Server.TLSCert == "") != (c.Server.TLSKey == "") {
+		invalid("server.tls_key", "a certificate and key must be set together")
+	}
+	if c.Server.TLSCert != "" && len(c.Server.ACMEDomains) != 0 {
+		invalid("server.acme_domains", "certificates can't be both set and issued with ACME")
+	}
+	if !slices.Contains([]string{"1.0", "1.1", "1.2", "1.3"}, c.Server.TLSMinVersion) {
+		invalid("server.tls_min_version", "%q is not one of 1.0, 1.1, 1.2 or 1.3", c.Server.TLSMinVersion)
+	}
+	if !slices.Contains([]string{"none", "request", "require", "verify_if_given", "require_and_verify"}, c.Server.TLSClientAuth) {
+		invalid("server.tls_client_auth", "%q is not one of none, request, require, verify_if_given or require_and_verify", c.Server.TLSClientAuth)
+	}
+	if strings.Contains(c.Server.TLSClientAuth, "verify") && c.Server.TLSClientCA == "" {
+		invalid("server.tls_client_ca", "verifying client certificates requires a CA")
+	}
+	if c.Server.HTTPRedirectPort != 0 && (!isPort(c.Server.HTTPRedirectPort) || c.Server.HTTPRedirectPort == c.Server.Port) {
+		invalid("server.http_redirect_port", "%d is not a valid port different from server.port", c.Server.HTTPRedirectPort)
+	}
+	if c.Server.HTTPRedirectPort != 0 && !c.Server.TLSEnabled() {
+		invalid("server.http_redirect_port", "redirecting to HTTPS requires a certificate or ACME")
+	}
+	for _, domain := range c.Server.ACMEDomains {
+		if domain == "" || strings.ContainsAny(domain, ":/ ") {
+			invalid("server.acme_domains", "%q is not a valid domain", domain)
+		}
+	}
+	if len(c.Server.ACMEDomains) != 0 {
+		if u, err := url.Parse(c.Server.ACMEDirectoryURL); err != nil || u.Scheme != "https" {
+			invalid("server.acme_directory_url", "%q is not a valid https URL", c.Server.ACMEDirectoryURL)
+		}
+		if c.Server.ACMECacheDir == "" {
+			invalid("server.acme_cache_dir", "directory can't be empty")
+		}
+	}
+
+	switch c.DB.Driver {
+	case DriverPostgres:
+		// a DSN replaces every connection setting
+		if c.DB.DSN != "" {
+			break
+		}
+		if c.DB.Host == "" {
+			invalid("database.host", "host can't be empty")
+		}
+		if !isPort(c.DB.Port) {
+			invalid("database.port", "%d is not a valid port", c.DB.Port)
+		}
+		if c.DB.User == "" {
+			invalid("database.user", "user can't be empty")
+		}
+		if c.DB.DBName == "" {
+			invalid("database.name", "name can't be empty")
+		}
+		if !slices.Contains([]string{"disable", "require", "verify-ca", "verify-full"}, c.DB.SSLMode) {
+			invalid("database.ssl_mode", "%q is not one of disable, require, verify-ca or verify-full", c.DB.SSLMode)
+		}
+		if (c.DB.SSLCert == "") != (c.DB.SSLKey == "") {
+			invalid("database.ssl_key", "a client certificate and key must be set together")
+		}
+	case DriverSQLite:
+		if c.DB.Path == "" && c.DB.DSN == "" {
+			invalid("database.path", "path can't be empty")
+		}
+	default:
+		invalid("database.driver", "%q is not one of %s or %s", c.DB.Driver, DriverPostgres, DriverSQLite)
+	}
+
+	if !isDuration(c.DB.SoftDeleteRetention) {
+		invalid("database.soft_delete_retention", "%q is not a valid duration", c.DB.SoftDeleteRetention)
+	}
+	if !isDuration(c.DB.PurgeInterval) {
+		invalid("database.purge_interval", "%q is not a valid duration", c.DB.PurgeInterval)
+	}
+
+	if len(c.Auth.JWTSecretAccess) < minSecretLength {
+		invalid("auth.jwt_secret_access", "secret must be at least %d characters long", minSecretLength)
+	}
+	if len(c.Auth.JWTSecretRefresh) < minSecretLength {
+		invalid("auth.jwt_secret_refresh", "secret must be at least %d characters long", minSecretLength)
+	}
+	if c.Auth.JWTSecretAccess != "" && c.Auth.JWTSecretAccess == c.Auth.JWTSecretRefresh {
+		invalid("auth.jwt_secret_refresh", "access and refresh tokens must use different secrets")
+	}
+	if !isDuration(c.Auth.RoleGrantExpiryInterval) {
+		invalid("auth.role_grant_expiry_interval", "%q is not a valid duration", c.Auth.RoleGrantExpiryInterval)
+	}
+	if c.Auth.SetupTokenFile == "" {
+		invalid("auth.setup_token_file", "file can't be empty")
+	}
+
+	if c.CDN.Directory == "" {
+		invalid("cdn.dir", "directory can't be empty")
+	}
+
+	if !slices.Contains([]string{"debug", "info", "warn", "error", "off"}, c.Log.Level) {
+		invalid("log.level", "%q is not one of debug, info, warn, error or off", c.Log.Level)
+	}
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		invalid("log.format", "%q is not one of text or json", c.Log.Format)
+	}
+	if c.Log.Output == "" {
+		invalid("log.output", "output can't be empty")
+	}
+
+	if c.Metrics.Enabled && c.Metrics.Token == "" && c.Metrics.Listen == "" {
+		invalid("metrics.token", "exposing metrics requires a token or a separate listen address")
+	}
+	if c.Metrics.Listen != "" {
+		if _, port, err := net.SplitHostPort(c.Metrics.Listen); err != nil || port == "" {
+			invalid("metrics.listen", "%q is not a valid host:port address", c.Metrics.Listen)
+		}
+	}
+
+	if !slices.Contains([]string{"none", "stdout", "otlp"}, c.Tracing.Exporter) {
+		invalid("tracing.exporter", "%q is not one of none, stdout or otlp", c.Tracing.Exporter)
+	}
+	if c.Tracing.Exporter == "otlp" && c.Tracing.Endpoint == "" {
+		invalid("tracing.endpoint", "exporting to OTLP requires the collector's endpoint")
+	}
+	if c.Tracing.Protocol != "grpc" && c.Tracing.Protocol != "http" {
+		invalid("tracing.protocol", "%q is not one of grpc or http", c.Tracing.Protocol)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		invalid("tracing.sample_ratio", "%v is not between 0 and 1", c.Tracing.SampleRatio)
+	}
+	if c.Tracing.ServiceName == "" {
+		invalid("tracing.service_name", "service name can't be empty")
+	}
+
+	if !isDuration(c.Webhooks.DeliveryInterval) {
+		invalid("webhooks.delivery_interval", "%q is not a valid duration", c.Webhooks.DeliveryInterval)
+	}
+	if !isDuration(c.Webhooks.Timeout) {
+		invalid("webhooks.timeout", "%q is not a valid duration", c.Webhooks.Timeout)
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		invalid("webhooks.max_attempts", "%d is not a positive number of attempts", c.Webhooks.MaxAttempts)
+	}
+	if !isDuration(c.Webhooks.RetryBackoff) {
+		invalid("webhooks.retry_backoff", "%q is not a valid duration", c.Webhooks.RetryBackoff)
+	}
+	if !isDuration(c.Webhooks.MaxRetryBackoff) {
+		invalid("webhooks.max_retry_backoff", "%q is not a valid duration", c.Webhooks.MaxRetryBackoff)
+	}
+
+	return diags
+}
+
+// diagnostic describes an invalid setting, including where it was set
+func (s settingSources) diagnostic(setting string, detail string) *hcl.Diagnostic {
+	diag := &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("Invalid %s", setting),
+		Detail:   detail + ".",
+	}
+
+	if env, ok := s.envs[setting]; ok {
+		diag.Detail = fmt.Sprintf("%s (set by %s).", detail, env)
+	} else if rng, ok := s.ranges[setting]; ok {
+		diag.Subject = rng.Ptr()
+	} else {
+		env := EnvPrefix + strings.ToUpper(strings.ReplaceAll(setting, ".", "_"))
+		diag.Detail = fmt.Sprintf("%s (not set, set it in the config file or with %s).", detail, env)
+	}
+
+	return diag
+}
+
+func isPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+// isDuration accepts empty durations since those fall back to their defaults
+func isDuration(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	d, err := time.ParseDuration(s)
+
+	return err == nil && d > 0
+}
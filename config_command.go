@@ -0,0 +1,23 @@
+package main
+
+import (
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "log"
+    "os"
+)
+
+// runConfig handles `panel config check`
+func runConfig(path string, args []string) {
+    if len(args) != 1 || args[0] != "check" {
+        log.Fatalln("usage: panel config check")
+    }
+
+    diags := config.CheckConfig(path)
+    if diags.HasErrors() {
+        config.WriteDiagnostics(os.Stderr, diags)
+        os.Exit(1)
+    }
+
+    fmt.Printf("%s is valid\n", path)
+}
@@ -5,18 +5,16 @@ import (
     "github.com/Encedeus/panel/proto"
     protoapi "github.com/Encedeus/panel/proto/go"
     "github.com/Encedeus/panel/services"
-    "github.com/google/uuid"
     "github.com/labstack/echo/v4"
-    "google.golang.org/protobuf/encoding/protojson"
     "net/http"
 )
 
 type APIKeyController struct {
-    Controller
+    APIController
 }
 
-func (akc APIKeyController) registerRoutes(srv *Server) {
-    keyEndpoint := srv.Group("key/account")
+func (akc APIKeyController) registerAPIRoutes(srv *Server, api *echo.Group) {
+    keyEndpoint := api.Group("/key/account")
     {
         /*        keyEndpoint.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
                   return middleware.AccessJWTAuth(srv.DB, next)
@@ -28,12 +26,13 @@ func (akc APIKeyController) registerRoutes(srv *Server) {
         keyEndpoint.DELETE("/:id", func(c echo.Context) error {
             return akc.handleDeleteAccountAPIKey(c, srv.DB)
         })
-        /*            accountEndpoint.GET("/:id", func(c echo.Context) error {
-                      return akc.handleFindAccountAPIKeyByID(c, srv.DB)
-                  })*/
         keyEndpoint.GET("/:userId", func(c echo.Context) error {
             return akc.handleFindAccountAPIKeysByUserId(c, srv.DB)
         })
+        // under the user since GET /:id would clash with listing their keys
+        keyEndpoint.GET("/:userId/:id", func(c echo.Context) error {
+            return akc.handleFindAccountAPIKeyByID(c, srv.DB)
+        })
     }
 }
 
@@ -41,61 +40,31 @@ func (APIKeyController) handleCreateAccountAPIKey(c echo.Context, db *ent.Client
     ctx := c.Request().Context()
 
     createReq := new(protoapi.AccountAPIKeyCreateRequest)
-
-    body := make([]byte, c.Request().ContentLength)
-    _, err = c.Request().Body.Read(body)
-    err = protojson.Unmarshal(body, createReq)
-    if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": err.Error(),
-        })
+    if err = bindRequest(c, createReq); err != nil {
+        return err
     }
 
     resp, err := services.CreateAccountAPIKey(ctx, db, createReq)
     if err != nil {
-        if services.IsValidationError(err) {
-            return c.JSON(http.StatusBadRequest, echo.Map{
-                "message": err.Error(),
-            })
-        }
-
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": err.Error(),
-        })
+        return err
     }
 
     return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusCreated, resp)
 }
 
 func (APIKeyController) handleDeleteAccountAPIKey(c echo.Context, db *ent.Client) (err error) {
-    ctx := c.Request().Context()
+    ctx := ifMatchContext(c)
 
-    id, err := uuid.Parse(c.Param("id"))
+    id, err := parseUUIDParam(c, "id")
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": err.Error(),
-        })
+        return err
     }
 
     _, err = services.DeleteAccountAPIKey(ctx, db, &protoapi.AccountAPIKeyDeleteRequest{
         Id: proto.UUIDToProtoUUID(id),
     })
     if err != nil {
-        if ent.IsNotFound(err) {
-            return c.JSON(http.StatusNotFound, echo.Map{
-                "message": "api key not found",
-            })
-        }
-
-        if err.Error() == "already deleted" {
-            return c.JSON(http.StatusGone, echo.Map{
-                "message": "api key already deleted",
-            })
-        }
-
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": err.Error(),
-        })
+        return err
     }
 
     return c.NoContent(http.StatusOK)
@@ -104,31 +73,16 @@ func (APIKeyController) handleDeleteAccountAPIKey(c echo.Context, db *ent.Client
 func (APIKeyController) handleFindAccountAPIKeysByUserId(c echo.Context, db *ent.Client) (err error) {
     ctx := c.Request().Context()
 
-    userId, err := uuid.Parse(c.Param("userId"))
+    userId, err := parseUUIDParam(c, "userId")
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "invalid UUID",
-        })
+        return err
     }
 
     resp, err := services.FindAccountAPIKeysByUserID(ctx, db, &protoapi.AccountAPIKeyFindManyByUserRequest{
         UserId: proto.UUIDToProtoUUID(userId),
     })
     if err != nil {
-        if ent.IsNotFound(err) {
-            return c.JSON(http.StatusNotFound, echo.Map{
-                "message": "user not found",
-            })
-        }
-        if err.Error() == "already deleted" {
-            return c.JSON(http.StatusGone, echo.Map{
-                "message": "user already deleted",
-            })
-        }
-
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+        return err
     }
 
     return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
@@ -137,21 +91,26 @@ func (APIKeyController) handleFindAccountAPIKeysByUserId(c echo.Context, db *ent
 func (APIKeyController) handleFindAccountAPIKeyByID(c echo.Context, db *ent.Client) (err error) {
     ctx := c.Request().Context()
 
-    id, err := uuid.Parse(c.Param("id"))
+    userId, err := parseUUIDParam(c, "userId")
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "invalid UUID",
-        })
+        return err
+    }
+    id, err := parseUUIDParam(c, "id")
+    if err != nil {
+        return err
     }
 
     resp, err := services.FindAccountAPIKeyByID(ctx, db, &protoapi.AccountAPIKeyFindOneRequest{
         Id: proto.UUIDToProtoUUID(id),
     })
     if err != nil {
-        return c.JSON(http.StatusNotFound, echo.Map{
-            "message": "api key not found",
-        })
+        return err
     }
+    if resp.AccountApiKey.UserId.GetValue() != userId.String() {
+        return services.ErrAPIKeyNotFound
+    }
+
+    setETag(c, resp.AccountApiKey.Version)
 
     return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
 }
@@ -2,24 +2,20 @@ package controllers
 
 import (
     "github.com/Encedeus/panel/ent"
-    "github.com/Encedeus/panel/hashing"
     "github.com/Encedeus/panel/middleware"
     protoapi "github.com/Encedeus/panel/proto/go"
     "github.com/Encedeus/panel/services"
     "github.com/labstack/echo/v4"
-    "github.com/labstack/gommon/log"
     "net/http"
-    "net/mail"
-    "strings"
     "time"
 )
 
 type AuthController struct {
-    Controller
+    APIController
 }
 
-func (ac AuthController) registerRoutes(srv *Server) {
-    authEndpoint := srv.Group("auth")
+func (ac AuthController) registerAPIRoutes(srv *Server, api *echo.Group) {
+    authEndpoint := api.Group("/auth")
     {
         authEndpoint.POST("/signin", func(c echo.Context) error {
             return ac.handleUserSignIn(c, srv.DB)
@@ -39,61 +35,19 @@ func (ac AuthController) registerRoutes(srv *Server) {
 func (AuthController) handleUserSignIn(c echo.Context, db *ent.Client) error {
     ctx := c.Request().Context()
     signInReq := new(protoapi.UserSignInRequest)
-    // error safe because of the json syntax middleware
-    err := c.Bind(signInReq)
-    if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": err.Error(),
-        })
-    }
-
-    var (
-        passwordHash string
-        tokenData    *protoapi.Token
-    )
-
-    // check which method was used for log in
-    if _, err := mail.ParseAddress(signInReq.Uid); err != nil {
-        passwordHash, tokenData, err = services.GetUserAuthDataAndHashByUsername(ctx, db, signInReq.Uid)
-    } else if strings.TrimSpace(signInReq.Uid) != "" {
-        passwordHash, tokenData, err = services.GetUserAuthDataAndHashByEmail(ctx, db, signInReq.Uid)
-    } else {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "either username or email must be specified",
-        })
+    if err := bindRequest(c, signInReq); err != nil {
+        return err
     }
 
-    // handle errors
+    resp, err := services.SignIn(ctx, db, signInReq)
     if err != nil {
-        if ent.IsNotFound(err) {
-            return c.JSON(http.StatusNotFound, echo.Map{
-                "message": "user not found",
-            })
-        }
-
-        log.Errorf("uncaught error querying user: %v", err)
-
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+        return err
     }
 
-    // check if the password hash is a match
-    auth := hashing.VerifyHash(signInReq.Password, passwordHash)
-
-    if !auth {
-        return c.JSON(http.StatusUnauthorized, echo.Map{
-            "message": "unauthorised",
-        })
-    }
-
-    // generate access and refresh tokens
-    accessToken, refreshToken, err := services.GetTokenPair(tokenData)
-
     // set refresh token cookie
     c.SetCookie(&http.Cookie{
         Name:     "encedeus_refreshToken",
-        Value:    refreshToken,
+        Value:    resp.RefreshToken,
         Secure:   true,
         Expires:  time.Now().Add(services.RefreshTokenExpireTime),
         SameSite: http.SameSiteStrictMode,
@@ -102,7 +56,7 @@ func (AuthController) handleUserSignIn(c echo.Context, db *ent.Client) error {
     })
 
     return c.JSON(http.StatusCreated, echo.Map{
-        "accessToken": accessToken,
+        "accessToken": resp.AccessToken,
     })
 }
 
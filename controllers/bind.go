@@ -0,0 +1,78 @@
+package controllers
+
+import (
+    "errors"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/services"
+    "github.com/labstack/echo/v4"
+    "google.golang.org/protobuf/encoding/protojson"
+    "google.golang.org/protobuf/proto"
+    "io"
+    "net/http"
+)
+
+// bindRequest decodes the JSON body into req and checks it against the rules declared in its proto definition,
+// handlers filling in fields from the path or the authenticated user use decodeRequest and validate afterwards
+func bindRequest(c echo.Context, req proto.Message) error {
+    if err := decodeRequest(c, req); err != nil {
+        return err
+    }
+
+    return services.ValidateRequest(req)
+}
+
+// decodeRequest decodes the JSON body into req with protojson, rejecting bodies larger than max_body_size.
+// Unknown fields are rejected or discarded as unknown_fields says, and an empty body decodes into an empty message
+func decodeRequest(c echo.Context, req proto.Message) error {
+    cfg := &config.Current().Server
+
+    body, err := readBody(c)
+    if err != nil {
+        return err
+    }
+    if len(body) == 0 {
+        proto.Reset(req)
+
+        return nil
+    }
+
+    opts := protojson.UnmarshalOptions{
+        DiscardUnknown: cfg.UnknownFields == config.UnknownFieldsDiscard,
+    }
+    if err = opts.Unmarshal(body, req); err != nil {
+        return malformedBody(err)
+    }
+
+    return nil
+}
+
+// readBody reads the whole request body, rejecting bodies larger than max_body_size
+func readBody(c echo.Context) ([]byte, error) {
+    maxBodySize := config.Current().Server.MaxBodySize
+
+    httpReq := c.Request()
+    if httpReq.ContentLength > maxBodySize {
+        return nil, errBodyTooLarge()
+    }
+
+    body, err := io.ReadAll(http.MaxBytesReader(c.Response(), httpReq.Body, maxBodySize))
+    if err != nil {
+        var maxBytesErr *http.MaxBytesError
+        if errors.As(err, &maxBytesErr) {
+            return nil, errBodyTooLarge()
+        }
+
+        return nil, malformedBody(err)
+    }
+
+    return body, nil
+}
+
+func errBodyTooLarge() error {
+    return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "request body too large")
+}
+
+// malformedBody reports a request body that can't be decoded into the request message
+func malformedBody(err error) error {
+    return services.NewValidationError("malformed request body: " + err.Error())
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+    "github.com/Encedeus/panel/config"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/testdb"
+    "google.golang.org/protobuf/encoding/protojson"
+    "net/http"
+    "strings"
+    "testing"
+)
+
+func TestUpdateRoleBodyValidation(t *testing.T) {
+    srv := newTestServer(t)
+    admin := createTestUser(t, srv.DB, "admin", "*")
+    editor := testdb.CreateRole(t, srv.DB, "editor", []string{"read"})
+    id := `"id":{"value":"` + editor.ID.String() + `"}`
+    config.Config.Server.MaxBodySize = 256
+
+    tests := []struct {
+        name          string
+        unknownFields string
+        body          string
+        want          int
+        fields        []string
+    }{
+        {"valid", config.UnknownFieldsReject, `{` + id + `,"name":"editor","permissions":["read"]}`, http.StatusOK, nil},
+        {"unknown field rejected", config.UnknownFieldsReject, `{` + id + `,"name":"editor","colour":"red"}`, http.StatusBadRequest, nil},
+        {"unknown field discarded", config.UnknownFieldsDiscard, `{` + id + `,"name":"editor","colour":"red"}`, http.StatusOK, nil},
+        {"malformed", config.UnknownFieldsReject, `{"name":`, http.StatusBadRequest, nil},
+        {"wrong type", config.UnknownFieldsReject, `{` + id + `,"name":1}`, http.StatusBadRequest, nil},
+        {"every broken rule", config.UnknownFieldsReject, `{"parentIds":[{"value":"not-a-uuid"}]}`, http.StatusBadRequest, []string{"id", "parentIds"}},
+        {"too large", config.UnknownFieldsReject, `{` + id + `,"name":"editor","permissions":["` + strings.Repeat("a", 300) + `"]}`, http.StatusRequestEntityTooLarge, nil},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config.Config.Server.UnknownFields = tt.unknownFields
+
+            rec := serveAs(t, srv, admin.ID, http.MethodPatch, "/api/v1/role", tt.body)
+            if rec.Code != tt.want {
+                t.Fatalf("got %d, want %d: %s", rec.Code, tt.want, rec.Body)
+            }
+            if tt.fields == nil {
+                return
+            }
+
+            resp := &protoapi.HttpResponse{}
+            if err := protojson.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+                t.Fatal(err)
+            }
+            var fields []string
+            for _, field := range resp.FieldErrors {
+                fields = append(fields, field.Field)
+            }
+            if strings.Join(fields, " ") != strings.Join(tt.fields, " ") {
+                t.Errorf("got field errors for %v, want %v", fields, tt.fields)
+            }
+        })
+    }
+}
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/logging"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "github.com/google/uuid"
+    "github.com/labstack/echo/v4"
+    "google.golang.org/protobuf/encoding/protojson"
+    "log/slog"
+    "net/http"
+    "strings"
+)
+
+var kindStatus = map[services.Kind]int{
+    services.KindValidation:         http.StatusBadRequest,
+    services.KindUnauthorized:       http.StatusUnauthorized,
+    services.KindForbidden:          http.StatusForbidden,
+    services.KindNotFound:           http.StatusNotFound,
+    services.KindConflict:           http.StatusConflict,
+    services.KindGone:               http.StatusGone,
+    services.KindPreconditionFailed: http.StatusPreconditionFailed,
+}
+
+// HTTPErrorHandler writes the errors returned by handlers and middleware as an HttpResponse,
+// domain errors keep their message while unexpected ones are logged and answered with a generic one
+func HTTPErrorHandler(err error, c echo.Context) {
+    if c.Response().Committed {
+        return
+    }
+
+    ctx := c.Request().Context()
+    resp := errorResponse(ctx, err)
+    resp.RequestId = logging.RequestID(ctx)
+    if resp.RequestId == "" {
+        resp.RequestId = c.Response().Header().Get(echo.HeaderXRequestID)
+    }
+
+    if c.Request().Method == http.MethodHead {
+        err = c.NoContent(int(resp.StatusCode))
+    } else {
+        var body []byte
+        body, err = protojson.Marshal(resp)
+        if err == nil {
+            err = c.JSONBlob(int(resp.StatusCode), body)
+        }
+    }
+    if err != nil {
+        slog.ErrorContext(ctx, "failed writing error response", "error", err)
+    }
+}
+
+func errorResponse(ctx context.Context, err error) *protoapi.HttpResponse {
+    if domainErr := services.DomainError(err); domainErr != nil {
+        resp := newErrorResponse(kindStatus[domainErr.Kind], domainErr.Message)
+        for _, field := range domainErr.Fields {
+            resp.FieldErrors = append(resp.FieldErrors, &protoapi.FieldError{
+                Field:   field.Field,
+                Message: field.Message,
+            })
+        }
+
+        return resp
+    }
+
+    var httpErr *echo.HTTPError
+    if errors.As(err, &httpErr) {
+        if httpErr.Code >= http.StatusInternalServerError {
+            slog.ErrorContext(ctx, "uncaught error", "error", err)
+        }
+
+        message := http.StatusText(httpErr.Code)
+        if httpErr.Message != nil {
+            message = fmt.Sprint(httpErr.Message)
+        }
+        // echo's own errors use the capitalised status text, like "Not Found"
+        if message == http.StatusText(httpErr.Code) {
+            message = strings.ToLower(message)
+        }
+
+        return newErrorResponse(httpErr.Code, message)
+    }
+
+    slog.ErrorContext(ctx, "uncaught error", "error", err)
+
+    return newErrorResponse(http.StatusInternalServerError, "internal server error")
+}
+
+func newErrorResponse(status int, message string) *protoapi.HttpResponse {
+    return &protoapi.HttpResponse{
+        StatusCode: int32(status),
+        Message:    message,
+        Code:       errorCode(status),
+    }
+}
+
+// errorCode turns a status code into a machine readable code, like not_found for 404
+func errorCode(status int) string {
+    text := strings.ToLower(http.StatusText(status))
+    if text == "" {
+        return "error"
+    }
+
+    return strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text)
+}
+
+// parseUUIDParam parses the path parameter name as a UUID
+func parseUUIDParam(c echo.Context, name string) (uuid.UUID, error) {
+    id, err := uuid.Parse(c.Param(name))
+    if err != nil {
+        return uuid.Nil, services.NewFieldError(name, "invalid UUID")
+    }
+
+    return id, nil
+}
+
+// requirePermission fails unless the authenticated user has the permission
+func requirePermission(ctx context.Context, db *ent.Client, permission string, userId uuid.UUID) error {
+    if !services.DoesUserHavePermission(ctx, db, permission, userId) {
+        return services.ErrMissingPermission
+    }
+
+    return nil
+}
+
+// requireSelfOrPermission fails unless the authenticated user is userId or has the permission
+func requireSelfOrPermission(ctx context.Context, db *ent.Client, permission string, authId uuid.UUID, userId uuid.UUID) error {
+    if authId != uuid.Nil && authId == userId {
+        return nil
+    }
+
+    return requirePermission(ctx, db, permission, authId)
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "github.com/google/uuid"
+    "github.com/labstack/echo/v4"
+    "google.golang.org/protobuf/encoding/protojson"
+    "net/http"
+    "testing"
+)
+
+func TestErrorResponse(t *testing.T) {
+    tests := []struct {
+        name    string
+        err     error
+        status  int32
+        code    string
+        message string
+    }{
+        {"domain error", services.ErrUserNotFound, http.StatusNotFound, "not_found", "user not found"},
+        {"wrapped domain error", fmt.Errorf("finding owner: %w", services.ErrMissingPermission), http.StatusForbidden, "forbidden", services.ErrMissingPermission.Message},
+        {"echo error", echo.ErrMethodNotAllowed, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed"},
+        {"echo error with message", echo.NewHTTPError(http.StatusTooManyRequests, "slow down"), http.StatusTooManyRequests, "too_many_requests", "slow down"},
+        {"unexpected error", errors.New("connection reset"), http.StatusInternalServerError, "internal_server_error", "internal server error"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            resp := errorResponse(context.Background(), tt.err)
+            if resp.StatusCode != tt.status || resp.Code != tt.code || resp.Message != tt.message {
+                t.Errorf("got %d %s %q, want %d %s %q", resp.StatusCode, resp.Code, resp.Message, tt.status, tt.code, tt.message)
+            }
+        })
+    }
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+    srv := newTestServer(t)
+    admin := createTestUser(t, srv.DB, "admin", "*")
+
+    tests := []struct {
+        name   string
+        target string
+        status int
+        field  string
+    }{
+        {"invalid id", "/api/v1/user/not-a-uuid", http.StatusBadRequest, "id"},
+        {"missing user", "/api/v1/user/" + uuid.NewString(), http.StatusNotFound, ""},
+        {"missing route", "/api/v1/nowhere", http.StatusNotFound, ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            rec := serveAs(t, srv, admin.ID, http.MethodGet, tt.target, "")
+
+            resp := &protoapi.HttpResponse{}
+            if err := protojson.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+                t.Fatalf("%v: %s", err, rec.Body)
+            }
+            if rec.Code != tt.status || int(resp.StatusCode) != tt.status {
+                t.Errorf("got %d with body status %d, want %d", rec.Code, resp.StatusCode, tt.status)
+            }
+            if resp.RequestId == "" || resp.RequestId != rec.Header().Get(echo.HeaderXRequestID) {
+                t.Errorf("body request ID %q doesn't match the header %q", resp.RequestId, rec.Header().Get(echo.HeaderXRequestID))
+            }
+            if tt.field != "" && (len(resp.FieldErrors) != 1 || resp.FieldErrors[0].Field != tt.field) {
+                t.Errorf("got field errors %v, want one for %s", resp.FieldErrors, tt.field)
+            }
+        })
+    }
+}
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+    "context"
+    "github.com/Encedeus/panel/services"
+    "github.com/labstack/echo/v4"
+)
+
+// ifMatchContext returns the request's context restricted to the versions in its If-Match header,
+// for the handlers of PATCH and DELETE routes, see services.WithIfMatch
+func ifMatchContext(c echo.Context) context.Context {
+    return services.WithIfMatch(c.Request().Context(), c.Request().Header.Get("If-Match"))
+}
+
+// setETag sets the ETag header to the version of the resource in the response
+func setETag(c echo.Context, version int64) {
+    c.Response().Header().Set("ETag", services.ETag(version))
+}
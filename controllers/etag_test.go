@@ -0,0 +1,62 @@
+package controllers
+
+import (
+    "context"
+    "github.com/Encedeus/panel/testdb"
+    "net/http"
+    "testing"
+)
+
+func TestAPIKeyETag(t *testing.T) {
+    srv := newTestServer(t)
+    owner := createTestUser(t, srv.DB, "owner")
+    other := createTestUser(t, srv.DB, "other")
+
+    key, err := srv.DB.ApiKey.Create().SetKey("key").SetDescription("ci").SetUserID(owner.ID).Save(context.Background())
+    if err != nil {
+        t.Fatal(err)
+    }
+    path := "/api/v1/key/account/" + owner.ID.String() + "/" + key.ID.String()
+
+    rec := serveAs(t, srv, owner.ID, http.MethodGet, path, "")
+    if rec.Code != http.StatusOK {
+        t.Fatalf("GET returned %d: %s", rec.Code, rec.Body)
+    }
+    if etag := rec.Header().Get("ETag"); etag != `"1"` {
+        t.Errorf("ETag is %s, want \"1\"", etag)
+    }
+
+    rec = serveAs(t, srv, owner.ID, http.MethodGet, "/api/v1/key/account/"+other.ID.String()+"/"+key.ID.String(), "")
+    if rec.Code != http.StatusNotFound {
+        t.Errorf("GET under another user returned %d, want %d", rec.Code, http.StatusNotFound)
+    }
+
+    deletePath := "/api/v1/key/account/" + key.ID.String()
+    if rec = serveAs(t, srv, owner.ID, http.MethodDelete, deletePath, "", "If-Match", `"2"`); rec.Code != http.StatusPreconditionFailed {
+        t.Errorf("DELETE with a stale If-Match returned %d, want %d", rec.Code, http.StatusPreconditionFailed)
+    }
+    if rec = serveAs(t, srv, owner.ID, http.MethodDelete, deletePath, "", "If-Match", `"1"`); rec.Code != http.StatusOK {
+        t.Errorf("DELETE with a matching If-Match returned %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+    }
+}
+
+func TestRoleUpdateIfMatch(t *testing.T) {
+    srv := newTestServer(t)
+    admin := createTestUser(t, srv.DB, "admin", "update_role")
+
+    r := testdb.CreateRole(t, srv.DB, "editor", []string{"read"})
+    body := `{"id": {"value": "` + r.ID.String() + `"}, "permissions": ["write"]}`
+
+    rec := serveAs(t, srv, admin.ID, http.MethodPatch, "/api/v1/role", body, "If-Match", `"2"`)
+    if rec.Code != http.StatusPreconditionFailed {
+        t.Errorf("PATCH with a stale If-Match returned %d, want %d: %s", rec.Code, http.StatusPreconditionFailed, rec.Body)
+    }
+
+    rec = serveAs(t, srv, admin.ID, http.MethodPatch, "/api/v1/role", body, "If-Match", `"1"`)
+    if rec.Code != http.StatusOK {
+        t.Fatalf("PATCH with a matching If-Match returned %d: %s", rec.Code, rec.Body)
+    }
+    if etag := rec.Header().Get("ETag"); etag != `"2"` {
+        t.Errorf("ETag after the update is %s, want \"2\"", etag)
+    }
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/health"
+    "github.com/Encedeus/panel/middleware"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "github.com/Encedeus/panel/version"
+    "github.com/labstack/echo/v4"
+    "net/http"
+)
+
+// viewHealthPermission lets users see the results of every readiness check and the build details
+const viewHealthPermission = "view_health"
+
+type HealthController struct {
+    Controller
+}
+
+func (hc HealthController) registerRoutes(srv *Server) {
+    // the endpoints are public, credentials only unlock the details
+    optionalAuth := func(next echo.HandlerFunc) echo.HandlerFunc {
+        return middleware.OptionalAccessJWTAuth(srv.DB, next)
+    }
+
+    srv.GET("/healthz", func(c echo.Context) error {
+        return hc.handleHealth(c)
+    })
+    srv.GET("/readyz", func(c echo.Context) error {
+        return hc.handleReadiness(c, srv.DB, srv.Readiness)
+    }, optionalAuth)
+    srv.GET("/version", func(c echo.Context) error {
+        return hc.handleVersion(c, srv.DB)
+    }, optionalAuth)
+}
+
+func (HealthController) handleHealth(c echo.Context) error {
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, &protoapi.HealthResponse{
+        Status: health.StatusOK,
+    })
+}
+
+func (HealthController) handleReadiness(c echo.Context, db *ent.Client, checker *health.Checker) error {
+    resp := &protoapi.ReadinessResponse{
+        Status: health.StatusOK,
+    }
+    if checker != nil {
+        resp = checker.Run(c.Request().Context())
+    }
+
+    status := http.StatusOK
+    if resp.Status != health.StatusOK {
+        status = http.StatusServiceUnavailable
+    }
+    if !canViewHealth(c, db) {
+        resp.Checks = nil
+    }
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, status, resp)
+}
+
+func (HealthController) handleVersion(c echo.Context, db *ent.Client) error {
+    info := version.Get()
+    resp := &protoapi.VersionResponse{
+        Version: info.Version,
+    }
+    if canViewHealth(c, db) {
+        resp.Commit = info.Commit
+        resp.CommitTime = info.CommitTime
+        resp.Modified = info.Modified
+        resp.GoVersion = info.GoVersion
+        resp.DatabaseDriver = config.Config.DB.Driver
+    }
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+}
+
+// canViewHealth checks if the request was authenticated by a user allowed to see the details
+func canViewHealth(c echo.Context, db *ent.Client) bool {
+    ctx := c.Request().Context()
+    userId, err := middleware.IDFromAccessContext(ctx)
+    if err != nil {
+        return false
+    }
+
+    return services.DoesUserHavePermission(ctx, db, viewHealthPermission, userId)
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/health"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "google.golang.org/protobuf/encoding/protojson"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestReadiness(t *testing.T) {
+    srv := newTestServer(t)
+    viewer := createTestUser(t, srv.DB, "viewer", viewHealthPermission)
+    other := createTestUser(t, srv.DB, "other")
+
+    failing := false
+    srv.Readiness = health.NewChecker()
+    srv.Readiness.Add("database", func(ctx context.Context) error {
+        _, err := srv.DB.User.Query().Exist(ctx)
+        return err
+    })
+    srv.Readiness.Add("metrics", func(ctx context.Context) error {
+        return health.Disabled("metrics are turned off")
+    })
+    srv.Readiness.Add("cache", func(ctx context.Context) error {
+        if failing {
+            return errors.New("connection refused")
+        }
+        return nil
+    })
+
+    readiness := func(t *testing.T, rec *httptest.ResponseRecorder) *protoapi.ReadinessResponse {
+        t.Helper()
+
+        resp := &protoapi.ReadinessResponse{}
+        if err := protojson.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+            t.Fatalf("%v: %s", err, rec.Body)
+        }
+        return resp
+    }
+
+    rec := httptest.NewRecorder()
+    srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+    if resp := readiness(t, rec); rec.Code != http.StatusOK || resp.Status != health.StatusOK || len(resp.Checks) != 0 {
+        t.Errorf("anonymous probe got %d %v, want 200 without details", rec.Code, resp)
+    }
+
+    failing = true
+    rec = serveAs(t, srv, other.ID, http.MethodGet, "/readyz", "")
+    if resp := readiness(t, rec); rec.Code != http.StatusServiceUnavailable || len(resp.Checks) != 0 {
+        t.Errorf("user without %s got %d %v, want 503 without details", viewHealthPermission, rec.Code, resp)
+    }
+
+    rec = serveAs(t, srv, viewer.ID, http.MethodGet, "/readyz", "")
+    resp := readiness(t, rec)
+    if rec.Code != http.StatusServiceUnavailable || resp.Status != health.StatusUnavailable {
+        t.Fatalf("got %d %s, want 503 %s", rec.Code, resp.Status, health.StatusUnavailable)
+    }
+    want := map[string]string{"database": health.StatusOK, "metrics": health.StatusDisabled, "cache": health.StatusFailed}
+    for _, check := range resp.Checks {
+        if check.Status != want[check.Name] {
+            t.Errorf("check %s is %s, want %s", check.Name, check.Status, want[check.Name])
+        }
+    }
+    if len(resp.Checks) != len(want) {
+        t.Errorf("got %d checks, want %d", len(resp.Checks), len(want))
+    }
+}
+
+func TestVersionDetailsNeedPermission(t *testing.T) {
+    srv := newTestServer(t)
+    viewer := createTestUser(t, srv.DB, "viewer", viewHealthPermission)
+
+    version := func(t *testing.T, rec *httptest.ResponseRecorder) *protoapi.VersionResponse {
+        t.Helper()
+
+        resp := &protoapi.VersionResponse{}
+        if err := protojson.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+            t.Fatalf("%v: %s", err, rec.Body)
+        }
+        return resp
+    }
+
+    rec := httptest.NewRecorder()
+    srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+    if resp := version(t, rec); resp.Version == "" || resp.GoVersion != "" || resp.DatabaseDriver != "" {
+        t.Errorf("anonymous request got %v, want only the version", resp)
+    }
+
+    rec = serveAs(t, srv, viewer.ID, http.MethodGet, "/version", "")
+    if resp := version(t, rec); resp.GoVersion == "" || resp.DatabaseDriver == "" {
+        t.Errorf("user with %s got %v, want the build details", viewHealthPermission, resp)
+    }
+}
@@ -0,0 +1,373 @@
+package controllers
+
+import (
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/openapi"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/version"
+    "github.com/labstack/echo/v4"
+    "net/http"
+)
+
+var (
+    // importQuery are the query parameters of the bulk imports
+    importQuery = []openapi.Parameter{
+        {Name: "dryRun", Schema: &openapi.Schema{Type: "boolean"}},
+        {Name: "bestEffort", Schema: &openapi.Schema{Type: "boolean"}},
+    }
+    // exportQuery are the query parameters of the exports, format is json or csv
+    exportQuery = []openapi.Parameter{
+        {Name: "format", Schema: &openapi.Schema{Type: "string", Enum: []string{"json", "csv"}}},
+    }
+)
+
+// apiOperations documents the routes of API v1 by their key without the /api/v1 prefix,
+// a test fails when a route is missing or no longer registered
+var apiOperations = map[string]openapi.Operation{
+    "POST /auth/signin": {
+        Summary:  "Sign in with a username or email, setting the refresh token cookie",
+        Request:  &protoapi.UserSignInRequest{},
+        Response: &protoapi.AccessTokenRefreshResponse{},
+        Status:   http.StatusCreated,
+    },
+    "GET /auth/refresh": {
+        Summary:  "Issue a new access token",
+        Auth:     openapi.AuthRefresh,
+        Response: &protoapi.AccessTokenRefreshResponse{},
+    },
+    "DELETE /auth/signout": {
+        Summary: "Sign out, clearing the refresh token cookie",
+        Auth:    openapi.AuthRefresh,
+    },
+
+    "GET /role/:id": {
+        Summary:  "Find a role",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.RoleFindOneResponse{},
+    },
+    "GET /role/:id/effective": {
+        Summary:  "Find the permissions of a role including the inherited ones",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.RoleEffectivePermissionsResponse{},
+    },
+    "POST /role/import": {
+        Summary:  "Import roles from a JSON or text/csv body, parents have to exist or come first",
+        Auth:     openapi.AuthAccess,
+        Request:  &protoapi.RoleImportRequest{},
+        Response: &protoapi.ImportReport{},
+        Query:    importQuery,
+    },
+    "GET /role/export": {
+        Summary:  "Export every role, each after its parents",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.RoleExportResponse{},
+        Query:    exportQuery,
+    },
+    "POST /role": {
+        Summary:  "Create a role",
+        Auth:     openapi.AuthAccess,
+        Request:  &protoapi.RoleCreateRequest{},
+        Response: &protoapi.RoleCreateResponse{},
+    },
+    "PATCH /role": {
+        Summary:     "Update a role",
+        Auth:        openapi.AuthAccess,
+        Request:     &protoapi.RoleUpdateRequest{},
+        Response:    &protoapi.RoleUpdateResponse{},
+        Conditional: true,
+    },
+    "DELETE /role/:id": {
+        Summary:     "Soft-delete a role",
+        Auth:        openapi.AuthAccess,
+        Conditional: true,
+    },
+    "POST /role/:id/restore": {
+        Summary:  "Restore a soft-deleted role",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.Role{},
+    },
+
+    "GET /user/pfp*": {
+        Summary:     "Download a profile picture by user ID",
+        ContentType: "application/octet-stream",
+    },
+    "GET /user/:id": {
+        Summary:  "Find a user",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.UserFindOneResponse{},
+    },
+    "POST /user/import": {
+        Summary:  "Import users from a JSON or text/csv body, bcrypt password hashes are kept as they are",
+        Auth:     openapi.AuthAccess,
+        Request:  &protoapi.UserImportRequest{},
+        Response: &protoapi.ImportReport{},
+        Query:    importQuery,
+    },
+    "GET /user/export": {
+        Summary:  "Export every user along with their password hash",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.UserExportResponse{},
+        Query:    exportQuery,
+    },
+    "POST /user": {
+        Summary:  "Create a user",
+        Auth:     openapi.AuthAccess,
+        Request:  &protoapi.UserCreateRequest{},
+        Response: &protoapi.UserCreateResponse{},
+    },
+    "PUT /user": {
+        Summary: "Upload a profile picture as the multipart form fields uuid and file",
+        Auth:    openapi.AuthAccess,
+    },
+    "PATCH /user": {
+        Summary:     "Update a user",
+        Auth:        openapi.AuthAccess,
+        Request:     &protoapi.UserUpdateRequest{},
+        Response:    &protoapi.UserUpdateResponse{},
+        Conditional: true,
+    },
+    "DELETE /user/:id": {
+        Summary:     "Soft-delete a user",
+        Auth:        openapi.AuthAccess,
+        Conditional: true,
+    },
+    "POST /user/:id/restore": {
+        Summary:  "Restore a soft-deleted user",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.User{},
+    },
+    "GET /user/:id/roles": {
+        Summary:  "List the roles granted to a user, anyone but the user needs grant_role",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.UserRoleGrantFindManyResponse{},
+    },
+    "POST /user/:id/roles": {
+        Summary:  "Grant a user an additional role",
+        Auth:     openapi.AuthAccess,
+        Request:  &protoapi.UserRoleGrantRequest{},
+        Response: &protoapi.UserRoleGrantResponse{},
+        Status:   http.StatusCreated,
+    },
+    "DELETE /user/:id/roles/:grantId": {
+        Summary: "Revoke a role grant",
+        Auth:    openapi.AuthAccess,
+    },
+    "PATCH /user/:id/changePassword": {
+        Summary:     "Change the password of the authenticated user",
+        Auth:        openapi.AuthAccess,
+        Request:     &protoapi.UserChangePasswordRequest{},
+        Conditional: true,
+    },
+    "PATCH /user/:id/changeUsername": {
+        Summary:     "Change the username of the authenticated user",
+        Auth:        openapi.AuthAccess,
+        Request:     &protoapi.UserChangeUsernameRequest{},
+        Conditional: true,
+    },
+    "PATCH /user/:id/changeEmail": {
+        Summary:     "Change the email of the authenticated user",
+        Auth:        openapi.AuthAccess,
+        Request:     &protoapi.UserChangeEmailRequest{},
+        Conditional: true,
+    },
+
+    "POST /key/account": {
+        Summary:  "Create an account API key",
+        Request:  &protoapi.AccountAPIKeyCreateRequest{},
+        Response: &protoapi.AccountAPIKeyCreateResponse{},
+        Status:   http.StatusCreated,
+    },
+    "DELETE /key/account/:id": {
+        Summary:     "Delete an account API key",
+        Conditional: true,
+    },
+    "GET /key/account/:userId": {
+        Summary:  "List the API keys of a user",
+        Response: &protoapi.AccountAPIKeyFindManyResponse{},
+    },
+    "GET /key/account/:userId/:id": {
+        Summary:  "Find an API key of a user, its version is returned as the ETag",
+        Response: &protoapi.AccountAPIKeyFindOneResponse{},
+    },
+
+    "GET /webhook": {
+        Summary:  "List every webhook",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.WebhookFindManyResponse{},
+    },
+    "GET /webhook/:id": {
+        Summary:  "Find a webhook",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.WebhookFindOneResponse{},
+    },
+    "POST /webhook": {
+        Summary:  "Create a webhook subscribed to at least one event type or * for all, the response holds its signing secret",
+        Auth:     openapi.AuthAccess,
+        Request:  &protoapi.WebhookCreateRequest{},
+        Response: &protoapi.WebhookCreateResponse{},
+        Status:   http.StatusCreated,
+    },
+    "PATCH /webhook/:id": {
+        Summary:  "Update a webhook or rotate its secret",
+        Auth:     openapi.AuthAccess,
+        Request:  &protoapi.WebhookUpdateRequest{},
+        Response: &protoapi.WebhookUpdateResponse{},
+    },
+    "DELETE /webhook/:id": {
+        Summary: "Delete a webhook along with its deliveries",
+        Auth:    openapi.AuthAccess,
+    },
+    "POST /webhook/:id/test": {
+        Summary:  "Send a ping event to a webhook",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.WebhookTestResponse{},
+    },
+    "GET /webhook/:id/deliveries": {
+        Summary:  "List the latest deliveries of a webhook",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.WebhookDeliveryFindManyResponse{},
+    },
+    "POST /webhook/:id/deliveries/:deliveryId/redeliver": {
+        Summary:  "Send the event of a delivery again",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.WebhookRedeliverResponse{},
+        Status:   http.StatusCreated,
+    },
+
+    "GET /setup": {
+        Summary:  "Check if the first user was created",
+        Response: &protoapi.SetupStatusResponse{},
+    },
+    "POST /setup": {
+        Summary:  "Create the first user with the setup token",
+        Request:  &protoapi.SetupRequest{},
+        Response: &protoapi.SetupResponse{},
+        Status:   http.StatusCreated,
+    },
+}
+
+// apiOperationsV2 documents the routes API v2 changes
+var apiOperationsV2 = map[string]openapi.Operation{
+    "POST /role/import": {
+        Summary:  "Import roles from a JSON or text/csv body, parents have to exist or come first",
+        Auth:     openapi.AuthAccess,
+        Request:  &protoapi.RoleImportRequest{},
+        Response: &protoapi.ImportReport{},
+        Query:    importQuery,
+    },
+    "GET /role/export": {
+        Summary:  "Export every role, each after its parents",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.RoleExportResponse{},
+        Query:    exportQuery,
+    },
+    "POST /role": {
+        Summary:  "Create a role",
+        Auth:     openapi.AuthAccess,
+        Request:  &protoapi.RoleCreateRequest{},
+        Response: &protoapi.RoleCreateResponse{},
+        Status:   http.StatusCreated,
+    },
+    "POST /user/import": {
+        Summary:  "Import users from a JSON or text/csv body, bcrypt password hashes are kept as they are",
+        Auth:     openapi.AuthAccess,
+        Request:  &protoapi.UserImportRequest{},
+        Response: &protoapi.ImportReport{},
+        Query:    importQuery,
+    },
+    "GET /user/export": {
+        Summary:  "Export every user along with their password hash",
+        Auth:     openapi.AuthAccess,
+        Response: &protoapi.UserExportResponse{},
+        Query:    exportQuery,
+    },
+    "POST /user": {
+        Summary:  "Create a user",
+        Auth:     openapi.AuthAccess,
+        Request:  &protoapi.UserCreateRequest{},
+        Response: &protoapi.UserCreateResponse{},
+        Status:   http.StatusCreated,
+    },
+}
+
+// rootOperations documents the routes outside of the API versions
+var rootOperations = map[string]openapi.Operation{
+    "GET /healthz": {
+        Summary:  "Check if the panel is running",
+        Tag:      "system",
+        Response: &protoapi.HealthResponse{},
+    },
+    "GET /readyz": {
+        Summary:  "Check if the panel can serve requests, check details need the view_health permission",
+        Tag:      "system",
+        Auth:     openapi.AuthOptionalAccess,
+        Response: &protoapi.ReadinessResponse{},
+    },
+    "GET /version": {
+        Summary:  "Find the panel's version, build details need the view_health permission",
+        Tag:      "system",
+        Auth:     openapi.AuthOptionalAccess,
+        Response: &protoapi.VersionResponse{},
+    },
+    "GET /metrics": {
+        Summary:     "Prometheus metrics, served here unless the metrics block sets a listen address",
+        Tag:         "system",
+        Auth:        openapi.AuthMetrics,
+        ContentType: "text/plain",
+    },
+    "GET /openapi.json": {
+        Summary:     "This document",
+        Tag:         "system",
+        ContentType: "application/json",
+    },
+    "GET /docs": {
+        Summary:     "API reference rendering this document, served if the server block enables api_docs",
+        Tag:         "system",
+        ContentType: "text/html",
+    },
+}
+
+type OpenAPIController struct {
+    Controller
+}
+
+func (oc OpenAPIController) registerRoutes(srv *Server) {
+    srv.GET("/openapi.json", func(c echo.Context) error {
+        return oc.handleOpenAPI(c, srv)
+    })
+    if config.Config.Server.APIDocs {
+        srv.GET("/docs", func(c echo.Context) error {
+            return c.HTMLBlob(http.StatusOK, openapi.Viewer())
+        })
+    }
+}
+
+// handleOpenAPI documents the routes registered at the time of the request, so routes added after InitRouter are included
+func (OpenAPIController) handleOpenAPI(c echo.Context, srv *Server) error {
+    return c.JSON(http.StatusOK, OpenAPIDocument(srv))
+}
+
+// OpenAPIDocument documents the routes registered on srv
+func OpenAPIDocument(srv *Server) *openapi.Document {
+    return openapi.Generate(openapi.Info{
+        Title:       "Encedeus Panel API",
+        Description: "Errors are returned as an HttpResponse with the status code, a message, field errors and the request ID.",
+        Version:     version.Get().Version,
+    }, serverRoutes(srv), versionedOperations())
+}
+
+func serverRoutes(srv *Server) []openapi.Route {
+    var routes []openapi.Route
+    for _, r := range srv.Routes() {
+        // the RPC services are described by their protobuf definitions instead
+        if r.Name == rpcRouteName {
+            continue
+        }
+        routes = append(routes, openapi.Route{
+            Method: r.Method,
+            Path:   r.Path,
+        })
+    }
+
+    return routes
+}
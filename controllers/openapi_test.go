@@ -0,0 +1,65 @@
+package controllers
+
+import (
+    "encoding/json"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/openapi"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+// newDocumentedServer registers every route, including the ones only registered with some settings
+func newDocumentedServer() *Server {
+    config.Config = config.DefaultConfiguration()
+    config.Config.Metrics.Enabled = true
+    config.Config.Metrics.Token = "token"
+    config.Config.Server.APIDocs = true
+
+    srv := NewEmptyServer(nil)
+    WrapServerWithDefaults(srv, nil)
+
+    return srv
+}
+
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+    srv := newDocumentedServer()
+
+    undocumented, unrouted := openapi.Diff(serverRoutes(srv), versionedOperations())
+    for _, key := range undocumented {
+        t.Errorf("%s is registered but undocumented", key)
+    }
+    for _, key := range unrouted {
+        t.Errorf("%s is documented but isn't registered", key)
+    }
+}
+
+func TestOpenAPIServed(t *testing.T) {
+    srv := newDocumentedServer()
+
+    rec := httptest.NewRecorder()
+    srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+    if rec.Code != http.StatusOK {
+        t.Fatalf("GET /openapi.json returned %d", rec.Code)
+    }
+
+    var doc openapi.Document
+    if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+        t.Fatalf("invalid document: %v", err)
+    }
+
+    for _, path := range []string{"/api/v1/auth/signin", "/api/v1/user/{id}/roles/{grantId}", "/api/v2/user", "/openapi.json"} {
+        if _, ok := doc.Paths[path]; !ok {
+            t.Errorf("document is missing %s", path)
+        }
+    }
+    for name, schema := range doc.Components.Schemas {
+        for field, prop := range schema.Properties {
+            if ref := prop.Ref; ref != "" {
+                if _, ok := doc.Components.Schemas[ref[len("#/components/schemas/"):]]; !ok {
+                    t.Errorf("%s.%s references the missing schema %s", name, field, ref)
+                }
+            }
+        }
+    }
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+    "bytes"
+    "fmt"
+    "github.com/Encedeus/panel/services"
+    "github.com/labstack/echo/v4"
+    "io"
+    "mime"
+    "net/http"
+    "strconv"
+)
+
+// exportUsersPermission is required to export users, exports hold their password hashes
+const exportUsersPermission = "export_users"
+
+// isCSVRequest checks if the body of an import is CSV, any other content type is read as JSON
+func isCSVRequest(c echo.Context) bool {
+    mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+
+    return mediaType == "text/csv"
+}
+
+// importOptions reads the dryRun and bestEffort query parameters of an import, both are false if not set
+func importOptions(c echo.Context) (services.ImportOptions, error) {
+    dryRun, err := queryBool(c, "dryRun")
+    if err != nil {
+        return services.ImportOptions{}, err
+    }
+    bestEffort, err := queryBool(c, "bestEffort")
+    if err != nil {
+        return services.ImportOptions{}, err
+    }
+
+    return services.ImportOptions{
+        DryRun:     dryRun,
+        BestEffort: bestEffort,
+    }, nil
+}
+
+func queryBool(c echo.Context, name string) (bool, error) {
+    param := c.QueryParam(name)
+    if param == "" {
+        return false, nil
+    }
+
+    value, err := strconv.ParseBool(param)
+    if err != nil {
+        return false, services.NewFieldError(name, fmt.Sprintf("invalid %s, expected true or false", name))
+    }
+
+    return value, nil
+}
+
+// exportFormat reads the format query parameter of an export, it defaults to JSON
+func exportFormat(c echo.Context) (services.Format, error) {
+    return services.ParseFormat(c.QueryParam("format"))
+}
+
+// writeExport answers with the records write encodes as an attachment named after the resource, like users.csv
+func writeExport(c echo.Context, resource string, format services.Format, write func(w io.Writer) error) error {
+    var body bytes.Buffer
+    if err := write(&body); err != nil {
+        return err
+    }
+
+    contentType := echo.MIMEApplicationJSON
+    if format == services.FormatCSV {
+        contentType = "text/csv; charset=utf-8"
+    }
+    c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", resource+"."+string(format)))
+
+    return c.Blob(http.StatusOK, contentType, body.Bytes())
+}
@@ -1,23 +1,24 @@
 package controllers
 
 import (
+    "bytes"
+    "errors"
     "github.com/Encedeus/panel/ent"
     "github.com/Encedeus/panel/middleware"
     "github.com/Encedeus/panel/proto"
     protoapi "github.com/Encedeus/panel/proto/go"
     "github.com/Encedeus/panel/services"
-    "github.com/google/uuid"
     "github.com/labstack/echo/v4"
-    "github.com/labstack/gommon/log"
+    "io"
     "net/http"
 )
 
 type RoleController struct {
-    Controller
+    APIController
 }
 
-func (rc RoleController) registerRoutes(srv *Server) {
-    roleEndpoint := srv.Group("role")
+func (rc RoleController) registerAPIRoutes(srv *Server, api *echo.Group) {
+    roleEndpoint := api.Group("/role")
     {
         roleEndpoint.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
             return middleware.AccessJWTAuth(srv.DB, next)
@@ -26,8 +27,17 @@ func (rc RoleController) registerRoutes(srv *Server) {
         roleEndpoint.GET("/:id", func(c echo.Context) error {
             return rc.handleFindRole(c, srv.DB)
         })
+        roleEndpoint.GET("/:id/effective", func(c echo.Context) error {
+            return rc.handleFindRoleEffectivePermissions(c, srv.DB)
+        })
+        roleEndpoint.POST("/import", func(c echo.Context) error {
+            return rc.handleImportRoles(c, srv.DB)
+        })
+        roleEndpoint.GET("/export", func(c echo.Context) error {
+            return rc.handleExportRoles(c, srv.DB)
+        })
         roleEndpoint.POST("", func(c echo.Context) error {
-            return rc.handleCreateRole(c, srv.DB)
+            return rc.handleCreateRole(c, srv.DB, http.StatusOK)
         })
         roleEndpoint.PATCH("", func(c echo.Context) error {
             return rc.handleUpdateRole(c, srv.DB)
@@ -35,196 +45,213 @@ func (rc RoleController) registerRoutes(srv *Server) {
         roleEndpoint.DELETE("/:id", func(c echo.Context) error {
             return rc.handleDeleteRole(c, srv.DB)
         })
+        roleEndpoint.POST("/:id/restore", func(c echo.Context) error {
+            return rc.handleRestoreRole(c, srv.DB)
+        })
+    }
+}
+
+// RoleControllerV2 answers role creation with 201 Created instead of 200
+type RoleControllerV2 struct {
+    APIController
+}
+
+func (RoleControllerV2) registerAPIRoutes(srv *Server, api *echo.Group) {
+    roleEndpoint := api.Group("/role")
+    {
+        roleEndpoint.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+            return middleware.AccessJWTAuth(srv.DB, next)
+        })
+
+        roleEndpoint.POST("", func(c echo.Context) error {
+            return RoleController{}.handleCreateRole(c, srv.DB, http.StatusCreated)
+        })
     }
 }
 
 func (RoleController) handleFindRole(c echo.Context, db *ent.Client) error {
     ctx := c.Request().Context()
 
-    id, err := uuid.Parse(c.Param("id"))
+    id, err := parseUUIDParam(c, "id")
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
+        return err
     }
 
     resp, err := services.FindRole(ctx, db, &protoapi.RoleFindOneRequest{
         Id: proto.UUIDToProtoUUID(id),
     })
     if err != nil {
-        if ent.IsNotFound(err) {
-            return c.JSON(http.StatusNotFound, echo.Map{
-                "message": "role not found",
-            })
-        }
+        return err
+    }
+    setETag(c, resp.Role.Version)
 
-        if err.Error() == "role deleted" {
-            return c.JSON(http.StatusGone, echo.Map{
-                "message": "role deleted",
-            })
-        }
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+}
 
-        log.Errorf("uncaught error querying role: %v", err)
+func (RoleController) handleFindRoleEffectivePermissions(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
 
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+    id, err := parseUUIDParam(c, "id")
+    if err != nil {
+        return err
+    }
+
+    resp, err := services.FindRoleEffectivePermissions(ctx, db, &protoapi.RoleFindOneRequest{
+        Id: proto.UUIDToProtoUUID(id),
+    })
+    if err != nil {
+        return err
     }
 
     return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
 }
 
-func (RoleController) handleCreateRole(c echo.Context, db *ent.Client) error {
+// handleCreateRole answers with status, which differs between API versions
+func (RoleController) handleCreateRole(c echo.Context, db *ent.Client, status int) error {
     ctx := c.Request().Context()
     userId, _ := middleware.IDFromAccessContext(ctx)
 
     // permission check
-    if !services.DoesUserHavePermission(ctx, db, "create_role", userId) {
-        return c.JSON(http.StatusUnauthorized, echo.Map{
-            "message": "unauthorised",
-        })
+    if err := requirePermission(ctx, db, "create_role", userId); err != nil {
+        return err
     }
 
     createReq := new(protoapi.RoleCreateRequest)
-    err := c.Bind(createReq)
-    if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
-    }
-
-    // check if name is specified
-    if createReq.Name == "" || len(createReq.Permissions) == 0 {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
+    if err := bindRequest(c, createReq); err != nil {
+        return err
     }
 
     resp, err := services.CreateRole(ctx, db, createReq)
     if err != nil {
-        if ent.IsNotFound(err) {
-            return c.JSON(http.StatusNotFound, echo.Map{
-                "message": "role not found",
-            })
-        }
-        if ent.IsValidationError(err) {
-            return c.JSON(http.StatusBadRequest, echo.Map{
-                "message": "validate error",
-            })
-        }
-        if ent.IsConstraintError(err) {
-            return c.JSON(http.StatusBadRequest, echo.Map{
-                "message": "constraint error",
-            })
-        }
-
-        log.Errorf("uncaught error creating role: %v", err)
-
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+        return err
     }
 
-    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+    return proto.MarshalControllerProtoResponseToJSON(&c, status, resp)
 }
 
 func (RoleController) handleUpdateRole(c echo.Context, db *ent.Client) error {
-    ctx := c.Request().Context()
+    ctx := ifMatchContext(c)
     userId, _ := middleware.IDFromAccessContext(ctx)
 
-    if !services.DoesUserHavePermission(ctx, db, "update_role", userId) {
-        return c.JSON(http.StatusUnauthorized, echo.Map{
-            "message": "unauthorised",
-        })
+    if err := requirePermission(ctx, db, "update_role", userId); err != nil {
+        return err
     }
 
     updateReq := new(protoapi.RoleUpdateRequest)
-    err := c.Bind(updateReq)
-    if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
-    }
-
-    // check if all fields are provided
-    if (updateReq.Name == "" && len(updateReq.Permissions) == 0) || updateReq.Id.Value == "" {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
+    if err := bindRequest(c, updateReq); err != nil {
+        return err
     }
 
     resp, err := services.UpdateRole(ctx, db, updateReq)
-
     if err != nil {
-        if ent.IsNotFound(err) {
-            return c.JSON(http.StatusNotFound, echo.Map{
-                "message": "role not found",
-            })
-        }
-        if ent.IsValidationError(err) {
-            return c.JSON(http.StatusBadRequest, echo.Map{
-                "message": "validate error",
-            })
-        }
-        if ent.IsConstraintError(err) {
-            return c.JSON(http.StatusBadRequest, echo.Map{
-                "message": "constraint error",
-            })
-        }
-        if err.Error() == "role deleted" {
-            return c.JSON(http.StatusGone, echo.Map{
-                "message": "role deleted",
-            })
-        }
-
-        log.Errorf("uncaught error updating role: %e", err)
-
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+        return err
     }
+    setETag(c, resp.Role.Version)
 
     return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
 }
 
 func (RoleController) handleDeleteRole(c echo.Context, db *ent.Client) error {
-    ctx := c.Request().Context()
+    ctx := ifMatchContext(c)
     userId, _ := middleware.IDFromAccessContext(ctx)
 
-    if !services.DoesUserHavePermission(ctx, db, "delete_role", userId) {
-        return c.JSON(http.StatusUnauthorized, echo.Map{
-            "message": "unauthorised",
-        })
+    if err := requirePermission(ctx, db, "delete_role", userId); err != nil {
+        return err
     }
 
-    id, err := uuid.Parse(c.Param("id"))
+    id, err := parseUUIDParam(c, "id")
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
+        return err
     }
 
     _, err = services.DeleteRole(ctx, db, &protoapi.RoleDeleteRequest{
         Id: proto.UUIDToProtoUUID(id),
     })
     if err != nil {
-        if ent.IsNotFound(err) {
-            return c.JSON(http.StatusNotFound, echo.Map{
-                "message": "role not found",
-            })
+        if errors.Is(err, services.ErrAlreadyDeleted) {
+            return services.NewGoneError("role already deleted")
         }
 
-        if err.Error() == "already deleted" {
-            return c.JSON(http.StatusGone, echo.Map{
-                "message": "role already deleted",
-            })
+        return err
+    }
+
+    return c.NoContent(http.StatusOK)
+}
+
+func (RoleController) handleRestoreRole(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+    userId, _ := middleware.IDFromAccessContext(ctx)
+
+    if err := requirePermission(ctx, db, "restore_role", userId); err != nil {
+        return err
+    }
+
+    id, err := parseUUIDParam(c, "id")
+    if err != nil {
+        return err
+    }
+
+    resp, err := services.RestoreRole(ctx, db, id)
+    if err != nil {
+        return err
+    }
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+}
+
+// handleImportRoles creates the roles of a JSON or CSV body, the report lists how each record went
+func (RoleController) handleImportRoles(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+    userId, _ := middleware.IDFromAccessContext(ctx)
+    if err := requirePermission(ctx, db, "create_role", userId); err != nil {
+        return err
+    }
+
+    opts, err := importOptions(c)
+    if err != nil {
+        return err
+    }
+
+    var records []*protoapi.RoleRecord
+    if isCSVRequest(c) {
+        body, err := readBody(c)
+        if err != nil {
+            return err
+        }
+        if records, err = services.ReadRoleRecords(bytes.NewReader(body), services.FormatCSV); err != nil {
+            return err
         }
-        log.Errorf("uncaught error deleting role: %v", err)
+    } else {
+        importReq := new(protoapi.RoleImportRequest)
+        if err = bindRequest(c, importReq); err != nil {
+            return err
+        }
+        records = importReq.Roles
+    }
 
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+    report, err := services.ImportRoles(ctx, db, records, opts)
+    if err != nil {
+        return err
     }
 
-    return c.NoContent(http.StatusOK)
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, report)
+}
+
+// handleExportRoles answers with every role as JSON or, if the format query parameter is csv, as CSV
+func (RoleController) handleExportRoles(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+
+    format, err := exportFormat(c)
+    if err != nil {
+        return err
+    }
+
+    records, err := services.ExportRoles(ctx, db)
+    if err != nil {
+        return err
+    }
+
+    return writeExport(c, "roles", format, func(w io.Writer) error {
+        return services.WriteRoleRecords(w, format, records)
+    })
 }
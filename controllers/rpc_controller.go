@@ -0,0 +1,21 @@
+package controllers
+
+import (
+    "github.com/Encedeus/panel/rpc"
+    "github.com/labstack/echo/v4"
+)
+
+// rpcRouteName names the routes of the RPC services, the OpenAPI document leaves them out
+const rpcRouteName = "rpc"
+
+// RPCController serves the gRPC, gRPC-Web and Connect services next to the REST API,
+// every procedure is a POST to /<package>.<service>/<method>
+type RPCController struct {
+    Controller
+}
+
+func (RPCController) registerRoutes(srv *Server) {
+    for _, h := range rpc.Handlers(srv.DB) {
+        srv.POST(h.Path+"*", echo.WrapHandler(h.Handler)).Name = rpcRouteName
+    }
+}
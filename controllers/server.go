@@ -1,11 +1,21 @@
 package controllers
 
 import (
+    "errors"
     "github.com/Encedeus/panel/config"
     "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/health"
+    "github.com/Encedeus/panel/metrics"
     encMiddleware "github.com/Encedeus/panel/middleware"
     "github.com/labstack/echo/v4"
     "github.com/labstack/echo/v4/middleware"
+    "go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+    "golang.org/x/crypto/acme/autocert"
+    "golang.org/x/exp/slices"
+    "golang.org/x/net/http2"
+    "log/slog"
+    "net/http"
+    "time"
 )
 
 type Controller interface {
@@ -21,6 +31,11 @@ func registerControllerRoutes(srv *Server, cs ...Controller) {
 type Server struct {
     *echo.Echo
     DB *ent.Client
+
+    // Readiness decides the answer of /readyz, without it the panel is always ready
+    Readiness *health.Checker
+
+    acmeManager *autocert.Manager
 }
 
 func NewEmptyServer(db *ent.Client) *Server {
@@ -28,20 +43,64 @@ func NewEmptyServer(db *ent.Client) *Server {
         Echo: echo.New(),
         DB:   db,
     }
+    // startup and errors of the underlying HTTP server go through slog like everything else
+    srv.HideBanner = true
+    srv.HidePort = true
+    srv.StdLogger = slog.NewLogLogger(slog.Default().Handler(), slog.LevelError)
+    srv.HTTPErrorHandler = HTTPErrorHandler
 
     return srv
 }
 
 func WrapServerWithDefaults(srv *Server, _ *ent.Client) {
-    srv.Use(encMiddleware.JSONSyntaxMiddleware)
+    srv.Use(encMiddleware.RequestIDMiddleware())
+    // outside the access log so its lines include the trace ID
+    srv.Use(otelecho.Middleware(config.Config.Tracing.ServiceName))
+    srv.Use(encMiddleware.AccessLogMiddleware)
+    srv.Use(encMiddleware.MetricsMiddleware)
+    srv.Use(encMiddleware.RateLimitMiddleware())
     srv.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-        AllowMethods:     []string{"GET", "POST", "DELETE", "PUT", "PATCH", "HEAD"},
-        AllowHeaders:     []string{"Accept", "Content-Type", "Authorization"},
-        AllowOrigins:     []string{"http://localhost:5173"},
+        AllowMethods: []string{"GET", "POST", "DELETE", "PUT", "PATCH", "HEAD"},
+        AllowHeaders: []string{"Accept", "Content-Type", "Authorization",
+            "If-Match", "Connect-Protocol-Version", "Connect-Timeout-Ms", "Grpc-Timeout", "X-Grpc-Web", "X-User-Agent"},
+        ExposeHeaders: []string{"ETag", "Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin"},
+        // read on every request so reloaded origins take effect
+        AllowOriginFunc: func(origin string) (bool, error) {
+            return slices.Contains(config.Current().Server.CORSOrigins, origin) ||
+                slices.Contains(config.Current().Server.CORSOrigins, "*"), nil
+        },
         AllowCredentials: true,
     }))
+    srv.Use(encMiddleware.SetupRequiredMiddleware(srv.DB))
 
     InitRouter(srv)
+
+    if config.Config.Metrics.Enabled && config.Config.Metrics.Listen == "" {
+        srv.GET("/metrics", echo.WrapHandler(metrics.Handler(metricsToken)))
+    }
+}
+
+// metricsToken returns the token protecting the metrics, read on every request so a reloaded token takes effect
+func metricsToken() string {
+    return config.Current().Metrics.Token
+}
+
+// NewMetricsServer returns a server exposing only the metrics,
+// it's nil if the metrics are disabled or served on the panel's address
+func NewMetricsServer() *http.Server {
+    cfg := &config.Config.Metrics
+    if !cfg.Enabled || cfg.Listen == "" {
+        return nil
+    }
+
+    mux := http.NewServeMux()
+    mux.Handle("/metrics", metrics.Handler(metricsToken))
+
+    return &http.Server{
+        Addr:              cfg.Listen,
+        Handler:           mux,
+        ReadHeaderTimeout: 10 * time.Second,
+    }
 }
 
 func NewDefaultServer(db *ent.Client) *Server {
@@ -52,18 +111,29 @@ func NewDefaultServer(db *ent.Client) *Server {
 }
 
 func InitRouter(srv *Server) {
+    registerAPIVersions(srv)
     registerControllerRoutes(srv,
-        AuthController{},
-        RoleController{},
-        UserController{},
-        APIKeyController{},
+        HealthController{},
+        OpenAPIController{},
+        RPCController{},
     )
 }
 
-func StartServer(srv *Server) {
-    srv.Logger.Fatal(srv.Start(config.Config.Server.URI()))
-}
+// StartServer serves requests until the server is shut down, over HTTPS if ConfigureTLS enabled it.
+// Without TLS it also accepts HTTP/2 without TLS (h2c), which gRPC clients use on plaintext connections
+func StartServer(srv *Server) error {
+    slog.Info("starting server", "address", config.Config.Server.URI(), "tls", srv.TLSServer.TLSConfig != nil)
+
+    var err error
+    if srv.TLSServer.TLSConfig != nil {
+        srv.TLSServer.Addr = config.Config.Server.URI()
+        err = srv.StartServer(srv.TLSServer)
+    } else {
+        err = srv.StartH2CServer(config.Config.Server.URI(), &http2.Server{})
+    }
+    if errors.Is(err, http.ErrServerClosed) {
+        return nil
+    }
 
-func StartDefaultServer(db *ent.Client) {
-    StartServer(NewDefaultServer(db))
+    return err
 }
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "github.com/Encedeus/panel/testdb"
+    "github.com/google/uuid"
+    "io"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+// newTestServer returns a server with the default middleware backed by an empty, migrated database
+func newTestServer(t *testing.T) *Server {
+    t.Helper()
+
+    config.Config = config.DefaultConfiguration()
+    config.Config.Auth.JWTSecretAccess = "test access secret"
+    config.Config.Auth.JWTSecretRefresh = "test refresh secret"
+
+    srv := NewEmptyServer(testdb.NewClient(t))
+    WrapServerWithDefaults(srv, srv.DB)
+
+    return srv
+}
+
+// createTestUser creates a user with a new role holding permissions straight in the database
+func createTestUser(t *testing.T, db *ent.Client, name string, permissions ...string) *ent.User {
+    t.Helper()
+
+    return testdb.CreateUser(t, db, name, testdb.CreateRole(t, db, name+"-role", permissions).ID)
+}
+
+// serveAs sends a request to srv with an access token of userId, body is sent as JSON if not empty
+func serveAs(t *testing.T, srv *Server, userId uuid.UUID, method string, target string, body string, headers ...string) *httptest.ResponseRecorder {
+    t.Helper()
+
+    token, err := services.GenerateAccessToken(&protoapi.AccessToken{
+        Token: &protoapi.Token{UserId: proto.UUIDToProtoUUID(userId)},
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var reader io.Reader
+    if body != "" {
+        reader = strings.NewReader(body)
+    }
+    req := httptest.NewRequest(method, target, reader)
+    req.Header.Set("Authorization", "Bearer "+token)
+    if body != "" {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    for i := 0; i+1 < len(headers); i += 2 {
+        req.Header.Set(headers[i], headers[i+1])
+    }
+
+    rec := httptest.NewRecorder()
+    srv.ServeHTTP(rec, req)
+
+    return rec
+}
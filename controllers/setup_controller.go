@@ -0,0 +1,55 @@
+package controllers
+
+import (
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "github.com/labstack/echo/v4"
+    "net/http"
+)
+
+type SetupController struct {
+    APIController
+}
+
+func (sc SetupController) registerAPIRoutes(srv *Server, api *echo.Group) {
+    setupEndpoint := api.Group("/setup")
+    {
+        setupEndpoint.GET("", func(c echo.Context) error {
+            return sc.handleSetupStatus(c, srv.DB)
+        })
+        setupEndpoint.POST("", func(c echo.Context) error {
+            return sc.handleSetup(c, srv.DB)
+        })
+    }
+}
+
+func (SetupController) handleSetupStatus(c echo.Context, db *ent.Client) error {
+    complete, err := services.IsSetupComplete(c.Request().Context(), db)
+    if err != nil {
+        return err
+    }
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, &protoapi.SetupStatusResponse{
+        Complete: complete,
+    })
+}
+
+func (SetupController) handleSetup(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+
+    setupReq := new(protoapi.SetupRequest)
+    if err := bindRequest(c, setupReq); err != nil {
+        return err
+    }
+
+    resp, err := services.CompleteSetup(ctx, db, setupReq)
+    if err != nil {
+        return err
+    }
+
+    resp.User.Password = ""
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusCreated, resp)
+}
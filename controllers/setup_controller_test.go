@@ -0,0 +1,33 @@
+package controllers
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestSetupRequired(t *testing.T) {
+    srv := newTestServer(t)
+
+    get := func(path string) int {
+        rec := httptest.NewRecorder()
+        srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+        return rec.Code
+    }
+
+    if code := get("/api/v1/setup"); code != http.StatusOK {
+        t.Errorf("GET /api/v1/setup returned %d before setup, want %d", code, http.StatusOK)
+    }
+    if code := get("/api/v1/setupx"); code != http.StatusServiceUnavailable {
+        t.Errorf("GET /api/v1/setupx returned %d before setup, want %d", code, http.StatusServiceUnavailable)
+    }
+    if code := get("/api/v1/role"); code != http.StatusServiceUnavailable {
+        t.Errorf("GET /api/v1/role returned %d before setup, want %d", code, http.StatusServiceUnavailable)
+    }
+
+    createTestUser(t, srv.DB, "first")
+    if code := get("/api/v1/role"); code != http.StatusUnauthorized {
+        t.Errorf("GET /api/v1/role returned %d after setup, want %d", code, http.StatusUnauthorized)
+    }
+}
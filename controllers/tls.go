@@ -0,0 +1,228 @@
+package controllers
+
+import (
+    "crypto/tls"
+    "crypto/x509"
+    "errors"
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/tracing"
+    "golang.org/x/crypto/acme"
+    "golang.org/x/crypto/acme/autocert"
+    "log/slog"
+    "net"
+    "net/http"
+    "os"
+    "strconv"
+    "sync"
+    "time"
+)
+
+// certCheckInterval is how often the certificate files are checked for changes during handshakes
+const certCheckInterval = 10 * time.Second
+
+var tlsVersions = map[string]uint16{
+    "1.0": tls.VersionTLS10,
+    "1.1": tls.VersionTLS11,
+    "1.2": tls.VersionTLS12,
+    "1.3": tls.VersionTLS13,
+}
+
+var tlsClientAuthTypes = map[string]tls.ClientAuthType{
+    "none":               tls.NoClientCert,
+    "request":            tls.RequestClientCert,
+    "require":            tls.RequireAnyClientCert,
+    "verify_if_given":    tls.VerifyClientCertIfGiven,
+    "require_and_verify": tls.RequireAndVerifyClientCert,
+}
+
+// ConfigureTLS makes the server serve HTTPS if the server block sets a certificate or ACME domains
+func ConfigureTLS(srv *Server) error {
+    cfg := &config.Config.Server
+    if !cfg.TLSEnabled() {
+        return nil
+    }
+
+    var tlsConfig *tls.Config
+    if len(cfg.ACMEDomains) != 0 {
+        manager, err := newACMEManager(cfg)
+        if err != nil {
+            return err
+        }
+
+        srv.acmeManager = manager
+        tlsConfig = manager.TLSConfig()
+    } else {
+        reloader, err := newCertReloader(cfg.TLSCert, cfg.TLSKey)
+        if err != nil {
+            return err
+        }
+
+        tlsConfig = &tls.Config{
+            GetCertificate: reloader.GetCertificate,
+            // gRPC requires HTTP/2
+            NextProtos: []string{"h2", "http/1.1"},
+        }
+    }
+
+    tlsConfig.MinVersion = tlsVersions[cfg.TLSMinVersion]
+    tlsConfig.ClientAuth = tlsClientAuthTypes[cfg.TLSClientAuth]
+    if cfg.TLSClientCA != "" {
+        pool, err := readCertPool(cfg.TLSClientCA)
+        if err != nil {
+            return err
+        }
+        tlsConfig.ClientCAs = pool
+    }
+
+    srv.TLSServer.TLSConfig = tlsConfig
+
+    return nil
+}
+
+func newACMEManager(cfg *config.ServerConfiguration) (*autocert.Manager, error) {
+    transport := http.DefaultTransport.(*http.Transport).Clone()
+    if cfg.ACMECARoot != "" {
+        pool, err := readCertPool(cfg.ACMECARoot)
+        if err != nil {
+            return nil, err
+        }
+        transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+    }
+
+    client := &acme.Client{
+        DirectoryURL: cfg.ACMEDirectoryURL,
+        HTTPClient:   &http.Client{Transport: tracing.Transport(transport)},
+    }
+
+    return &autocert.Manager{
+        Prompt:     autocert.AcceptTOS,
+        Cache:      autocert.DirCache(cfg.ACMECacheDir),
+        HostPolicy: autocert.HostWhitelist(cfg.ACMEDomains...),
+        Email:      cfg.ACMEEmail,
+        Client:     client,
+    }, nil
+}
+
+func readCertPool(path string) (*x509.CertPool, error) {
+    pem, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+
+    pool := x509.NewCertPool()
+    if !pool.AppendCertsFromPEM(pem) {
+        return nil, fmt.Errorf("%s contains no PEM certificates", path)
+    }
+
+    return pool, nil
+}
+
+// NewRedirectServer returns a plain HTTP server redirecting to HTTPS and answering ACME HTTP challenges,
+// it's nil if the server block doesn't set a redirect port
+func NewRedirectServer(srv *Server) *http.Server {
+    cfg := &config.Config.Server
+    if cfg.HTTPRedirectPort == 0 {
+        return nil
+    }
+
+    var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        host, _, err := net.SplitHostPort(r.Host)
+        if err != nil {
+            host = r.Host
+        }
+        if cfg.Port != 443 {
+            host = net.JoinHostPort(host, strconv.Itoa(cfg.Port))
+        }
+
+        http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+    })
+    if srv.acmeManager != nil {
+        handler = srv.acmeManager.HTTPHandler(handler)
+    }
+
+    return &http.Server{
+        Addr:              net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.HTTPRedirectPort)),
+        Handler:           handler,
+        ReadHeaderTimeout: 10 * time.Second,
+    }
+}
+
+// StartHTTPServer serves plain HTTP until the server is shut down, it's used by the redirect and metrics servers
+func StartHTTPServer(s *http.Server) error {
+    err := s.ListenAndServe()
+    if errors.Is(err, http.ErrServerClosed) {
+        return nil
+    }
+
+    return err
+}
+
+// certReloader serves a certificate from files, reloading it once the files change
+type certReloader struct {
+    certFile string
+    keyFile  string
+
+    mu        sync.Mutex
+    cert      *tls.Certificate
+    modTime   time.Time
+    checkedAt time.Time
+}
+
+func newCertReloader(certFile string, keyFile string) (*certReloader, error) {
+    r := &certReloader{
+        certFile: certFile,
+        keyFile:  keyFile,
+    }
+    if err := r.load(); err != nil {
+        return nil, err
+    }
+
+    return r, nil
+}
+
+func (r *certReloader) load() error {
+    cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+    if err != nil {
+        return fmt.Errorf("failed loading TLS certificate: %w", err)
+    }
+
+    r.cert = &cert
+    r.modTime = r.latestModTime()
+
+    return nil
+}
+
+// latestModTime returns the latest modification time of the certificate and key
+func (r *certReloader) latestModTime() time.Time {
+    var latest time.Time
+    for _, file := range []string{r.certFile, r.keyFile} {
+        if info, err := os.Stat(file); err == nil && info.ModTime().After(latest) {
+            latest = info.ModTime()
+        }
+    }
+
+    return latest
+}
+
+// GetCertificate returns the current certificate, an invalid replacement is logged and the old one kept
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    if time.Since(r.checkedAt) < certCheckInterval {
+        return r.cert, nil
+    }
+    r.checkedAt = time.Now()
+
+    if modTime := r.latestModTime(); !modTime.Equal(r.modTime) {
+        if err := r.load(); err != nil {
+            slog.Error("keeping the current TLS certificate", "error", err)
+            r.modTime = modTime
+        } else {
+            slog.Info("reloaded TLS certificate", "file", r.certFile)
+        }
+    }
+
+    return r.cert, nil
+}
@@ -0,0 +1,163 @@
+package controllers
+
+import (
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rand"
+    "crypto/tls"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/pem"
+    "github.com/Encedeus/panel/config"
+    "math/big"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+// writeTestCert writes a self-signed certificate for commonName and its key as PEM files
+func writeTestCert(t *testing.T, certFile string, keyFile string, commonName string) {
+    t.Helper()
+
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    template := &x509.Certificate{
+        SerialNumber: big.NewInt(1),
+        Subject:      pkix.Name{CommonName: commonName},
+        NotBefore:    time.Now().Add(-time.Hour),
+        NotAfter:     time.Now().Add(time.Hour),
+    }
+    der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+    if err != nil {
+        t.Fatal(err)
+    }
+    keyDer, err := x509.MarshalECPrivateKey(key)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    if err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600); err != nil {
+        t.Fatal(err)
+    }
+}
+
+// touch moves the modification time of files forward, so a change is noticed within the file system's time resolution
+func touch(t *testing.T, modTime time.Time, files ...string) {
+    t.Helper()
+
+    for _, file := range files {
+        if err := os.Chtimes(file, modTime, modTime); err != nil {
+            t.Fatal(err)
+        }
+    }
+}
+
+func servedCommonName(t *testing.T, r *certReloader) string {
+    t.Helper()
+
+    cert, err := r.GetCertificate(&tls.ClientHelloInfo{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    leaf, err := x509.ParseCertificate(cert.Certificate[0])
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return leaf.Subject.CommonName
+}
+
+func TestCertReloader(t *testing.T) {
+    dir := t.TempDir()
+    certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+    writeTestCert(t, certFile, keyFile, "first")
+
+    r, err := newCertReloader(certFile, keyFile)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := servedCommonName(t, r); got != "first" {
+        t.Fatalf("serving %s, want first", got)
+    }
+
+    writeTestCert(t, certFile, keyFile, "renewed")
+    touch(t, time.Now().Add(time.Minute), certFile, keyFile)
+    if got := servedCommonName(t, r); got != "first" {
+        t.Errorf("serving %s before the check interval passed, want first", got)
+    }
+
+    r.checkedAt = time.Time{}
+    if got := servedCommonName(t, r); got != "renewed" {
+        t.Errorf("serving %s, want the renewed certificate", got)
+    }
+
+    if err = os.WriteFile(certFile, []byte("not a certificate"), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    touch(t, time.Now().Add(2*time.Minute), certFile)
+    r.checkedAt = time.Time{}
+    if got := servedCommonName(t, r); got != "renewed" {
+        t.Errorf("serving %s, want the last valid certificate kept", got)
+    }
+}
+
+func TestConfigureTLS(t *testing.T) {
+    srv := newTestServer(t)
+
+    dir := t.TempDir()
+    certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+    writeTestCert(t, certFile, keyFile, "panel")
+    config.Config.Server.TLSCert = certFile
+    config.Config.Server.TLSKey = keyFile
+    config.Config.Server.TLSMinVersion = "1.3"
+
+    if err := ConfigureTLS(srv); err != nil {
+        t.Fatal(err)
+    }
+    tlsConfig := srv.TLSServer.TLSConfig
+    if tlsConfig == nil {
+        t.Fatal("server doesn't serve HTTPS")
+    }
+    if tlsConfig.MinVersion != tls.VersionTLS13 {
+        t.Errorf("minimum version is %x, want TLS 1.3", tlsConfig.MinVersion)
+    }
+
+    srv = newTestServer(t)
+    config.Config.Server.TLSCert = certFile
+    config.Config.Server.TLSKey = filepath.Join(dir, "missing.pem")
+    if err := ConfigureTLS(srv); err == nil {
+        t.Error("configured TLS with a missing key")
+    }
+}
+
+func TestRedirectServer(t *testing.T) {
+    newTestServer(t)
+    config.Config.Server.Port = 8443
+
+    if NewRedirectServer(&Server{}) != nil {
+        t.Error("redirect server without a redirect port")
+    }
+
+    config.Config.Server.HTTPRedirectPort = 8080
+    redirect := NewRedirectServer(&Server{})
+    if redirect == nil {
+        t.Fatal("no redirect server with a redirect port")
+    }
+
+    rec := httptest.NewRecorder()
+    redirect.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://panel.example.com:8080/api/v1/user?page=2", nil))
+    if rec.Code != http.StatusMovedPermanently {
+        t.Errorf("got %d, want %d", rec.Code, http.StatusMovedPermanently)
+    }
+    if got, want := rec.Header().Get("Location"), "https://panel.example.com:8443/api/v1/user?page=2"; got != want {
+        t.Errorf("redirected to %s, want %s", got, want)
+    }
+}
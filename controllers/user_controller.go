@@ -1,31 +1,28 @@
 package controllers
 
 import (
+    "bytes"
     "errors"
     "fmt"
     "github.com/Encedeus/panel/config"
     "github.com/Encedeus/panel/ent"
-    "github.com/Encedeus/panel/hashing"
     "github.com/Encedeus/panel/middleware"
     "github.com/Encedeus/panel/proto"
     protoapi "github.com/Encedeus/panel/proto/go"
     "github.com/Encedeus/panel/services"
     "github.com/google/uuid"
     "github.com/labstack/echo/v4"
-    "github.com/labstack/gommon/log"
-    "google.golang.org/protobuf/encoding/protojson"
     "io"
     "net/http"
     "os"
-    "strings"
 )
 
 type UserController struct {
-    Controller
+    APIController
 }
 
-func (uc UserController) registerRoutes(srv *Server) {
-    userEndpoint := srv.Group("user")
+func (uc UserController) registerAPIRoutes(srv *Server, api *echo.Group) {
+    userEndpoint := api.Group("/user")
     {
         userEndpoint.Static("/pfp", config.Config.CDN.Directory)
 
@@ -36,8 +33,14 @@ func (uc UserController) registerRoutes(srv *Server) {
         userEndpoint.GET("/:id", func(c echo.Context) error {
             return handleFindUser(c, srv.DB)
         })
+        userEndpoint.POST("/import", func(c echo.Context) error {
+            return handleImportUsers(c, srv.DB)
+        })
+        userEndpoint.GET("/export", func(c echo.Context) error {
+            return handleExportUsers(c, srv.DB)
+        })
         userEndpoint.POST("", func(c echo.Context) error {
-            return handleCreateUser(c, srv.DB)
+            return handleCreateUser(c, srv.DB, http.StatusOK)
         })
         userEndpoint.PUT("", func(c echo.Context) error {
             return handleSetPfp(c, srv.DB)
@@ -48,6 +51,18 @@ func (uc UserController) registerRoutes(srv *Server) {
         userEndpoint.DELETE("/:id", func(c echo.Context) error {
             return handleDeleteUser(c, srv.DB)
         })
+        userEndpoint.POST("/:id/restore", func(c echo.Context) error {
+            return handleRestoreUser(c, srv.DB)
+        })
+        userEndpoint.GET("/:id/roles", func(c echo.Context) error {
+            return handleFindUserRoleGrants(c, srv.DB)
+        })
+        userEndpoint.POST("/:id/roles", func(c echo.Context) error {
+            return handleGrantUserRole(c, srv.DB)
+        })
+        userEndpoint.DELETE("/:id/roles/:grantId", func(c echo.Context) error {
+            return handleRevokeUserRole(c, srv.DB)
+        })
         userEndpoint.PATCH("/:id/changePassword", func(c echo.Context) error {
             return handleChangePassword(c, srv.DB)
         })
@@ -62,150 +77,144 @@ func (uc UserController) registerRoutes(srv *Server) {
 
 func handleFindUser(c echo.Context, db *ent.Client) error {
     ctx := c.Request().Context()
-    rawUserId := c.Param("id")
 
-    userId, err := uuid.Parse(rawUserId)
+    userId, err := parseUUIDParam(c, "id")
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{"message": "bad request"})
+        return err
     }
 
     resp, err := services.FindOneUser(ctx, db, &protoapi.UserFindOneRequest{
         UserId: proto.UUIDToProtoUUID(userId),
     })
+    if err != nil {
+        return err
+    }
+    setETag(c, resp.User.Version)
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+}
 
+// handleImportUsers creates the users of a JSON or CSV body, the report lists how each record went
+func handleImportUsers(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+    authUUID, _ := middleware.IDFromAccessContext(ctx)
+    if err := requirePermission(ctx, db, "create_user", authUUID); err != nil {
+        return err
+    }
+
+    opts, err := importOptions(c)
     if err != nil {
-        if ent.IsNotFound(err) {
-            return c.JSON(http.StatusNotFound, echo.Map{"message": "user not found"})
+        return err
+    }
+
+    var records []*protoapi.UserRecord
+    if isCSVRequest(c) {
+        body, err := readBody(c)
+        if err != nil {
+            return err
         }
-        if err.Error() == "user deleted" {
-            return c.JSON(http.StatusGone, echo.Map{"message": "user deleted"})
+        if records, err = services.ReadUserRecords(bytes.NewReader(body), services.FormatCSV); err != nil {
+            return err
         }
+    } else {
+        importReq := new(protoapi.UserImportRequest)
+        if err = bindRequest(c, importReq); err != nil {
+            return err
+        }
+        records = importReq.Users
+    }
 
-        log.Errorf("error querying user: %v", err)
-
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+    report, err := services.ImportUsers(ctx, db, authUUID, records, opts)
+    if err != nil {
+        return err
     }
 
-    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, report)
 }
 
-func handleCreateUser(c echo.Context, db *ent.Client) error {
-    // get uuid from header provided by the middleware
+// handleExportUsers answers with every user as JSON or, if the format query parameter is csv, as CSV
+func handleExportUsers(c echo.Context, db *ent.Client) error {
     ctx := c.Request().Context()
     authUUID, _ := middleware.IDFromAccessContext(ctx)
-    // check permissions
-    if !services.DoesUserHavePermission(ctx, db, "create_user", authUUID) {
-        return c.JSON(http.StatusUnauthorized, echo.Map{
-            "message": "unauthorised",
-        })
+    if err := requirePermission(ctx, db, exportUsersPermission, authUUID); err != nil {
+        return err
     }
 
-    createReq := new(protoapi.UserCreateRequest)
-    err := c.Bind(createReq)
+    format, err := exportFormat(c)
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
+        return err
     }
 
-    // check if all the fields are provided
-    if strings.TrimSpace(createReq.Name) == "" || strings.TrimSpace(createReq.Password) == "" || strings.TrimSpace(createReq.Email) == "" || (strings.TrimSpace(createReq.RoleName) == "" && strings.TrimSpace(createReq.RoleId.Value) == "") {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
-    }
-
-    resp, err := services.CreateUser(ctx, db, createReq)
-    // error checking
+    records, err := services.ExportUsers(ctx, db)
     if err != nil {
-        if services.IsValidationError(err) {
-            return c.JSON(http.StatusBadRequest, echo.Map{
-                "message": err.Error(),
-            })
-        }
+        return err
+    }
 
-        if ent.IsNotFound(err) {
-            return c.JSON(http.StatusNotFound, echo.Map{
-                "message": "role not found",
-            })
-        }
+    return writeExport(c, "users", format, func(w io.Writer) error {
+        return services.WriteUserRecords(w, format, records)
+    })
+}
 
-        if ent.IsConstraintError(err) {
-            return c.JSON(http.StatusConflict, echo.Map{
-                "message": "username taken",
-            })
-        }
+// UserControllerV2 answers user creation with 201 Created instead of 200
+type UserControllerV2 struct {
+    APIController
+}
 
-        // log any uncaught errors
-        log.Errorf("uncaught error querying role: %v", err)
+func (UserControllerV2) registerAPIRoutes(srv *Server, api *echo.Group) {
+    userEndpoint := api.Group("/user")
+    {
+        userEndpoint.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+            return middleware.AccessJWTAuth(srv.DB, next)
+        })
 
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
+        userEndpoint.POST("", func(c echo.Context) error {
+            return handleCreateUser(c, srv.DB, http.StatusCreated)
         })
     }
-
-    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
 }
 
-func handleUpdateUser(c echo.Context, db *ent.Client) error {
+// handleCreateUser answers with status, which differs between API versions
+func handleCreateUser(c echo.Context, db *ent.Client, status int) error {
+    // get uuid from header provided by the middleware
     ctx := c.Request().Context()
     authUUID, _ := middleware.IDFromAccessContext(ctx)
-
     // check permissions
-    if !services.DoesUserHavePermission(ctx, db, "update_user", authUUID) {
-        return c.JSON(http.StatusUnauthorized, echo.Map{
-            "message": "unauthorised",
-        })
+    if err := requirePermission(ctx, db, "create_user", authUUID); err != nil {
+        return err
     }
 
-    updateReq := new(protoapi.UserUpdateRequest)
-    err := c.Bind(updateReq)
+    createReq := new(protoapi.UserCreateRequest)
+    if err := bindRequest(c, createReq); err != nil {
+        return err
+    }
+
+    resp, err := services.CreateUser(ctx, db, authUUID, createReq)
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
+        return err
     }
 
-    // check if no fields are provided
-    if (updateReq.Name == "" && updateReq.Email == "" && updateReq.Password == "" && updateReq.RoleName == "" && updateReq.RoleId.Value == "") || updateReq.UserId.Value == "" {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
+    return proto.MarshalControllerProtoResponseToJSON(&c, status, resp)
+}
+
+func handleUpdateUser(c echo.Context, db *ent.Client) error {
+    ctx := ifMatchContext(c)
+    authUUID, _ := middleware.IDFromAccessContext(ctx)
+
+    // check permissions
+    if err := requirePermission(ctx, db, "update_user", authUUID); err != nil {
+        return err
     }
 
-    updateReq.Password = hashing.HashPassword(updateReq.Password)
-    resp, err := services.UpdateUser(ctx, db, updateReq)
+    updateReq := new(protoapi.UserUpdateRequest)
+    if err := bindRequest(c, updateReq); err != nil {
+        return err
+    }
 
-    // error checking
+    resp, err := services.UpdateUser(ctx, db, authUUID, updateReq)
     if err != nil {
-        if ent.IsNotFound(err) {
-            if strings.Contains(err.Error(), "role") {
-                return c.JSON(http.StatusNotFound, echo.Map{
-                    "message": "role not found",
-                })
-            }
-            return c.JSON(http.StatusNotFound, echo.Map{
-                "message": "user not found",
-            })
-        }
-        if ent.IsValidationError(err) || ent.IsConstraintError(err) || services.IsValidationError(err) {
-            return c.JSON(http.StatusBadRequest, echo.Map{
-                "message": "validate error",
-            })
-        }
-        if err.Error() == "user deleted" {
-            return c.JSON(http.StatusGone, echo.Map{
-                "message": "user deleted",
-            })
-        }
-
-        log.Errorf("uncaught error updating user: %v", err)
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+        return err
     }
+    setETag(c, resp.User.Version)
 
     return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
 }
@@ -215,211 +224,241 @@ func handleSetPfp(c echo.Context, db *ent.Client) error {
     // get params from multipart
     userId := c.FormValue("uuid")
     file, err := c.FormFile("file")
-
-    // validate request
-    if err != nil || userId == "" {
-        return c.JSON(http.StatusBadRequest, echo.Map{"message": "bad request"})
+    if err != nil {
+        return services.NewFieldError("file", "missing file")
     }
 
+    // validate request
     userUUID, err := uuid.Parse(userId)
+    if err != nil {
+        return services.NewFieldError("uuid", "invalid UUID")
+    }
     if !services.DoesUserWithUUIDExist(ctx, db, userUUID) {
-        return c.JSON(http.StatusNotFound, echo.Map{"message": "user not found"})
+        return services.ErrUserNotFound
     }
 
     // open file
     src, err := file.Open()
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{"message": "invalid file format"})
+        return services.NewFieldError("file", "invalid file format")
     }
     defer src.Close()
 
     // create file
-    dst, err := os.Create(fmt.Sprintf("%s/%s", config.Config.CDN.Directory, userId))
+    dst, err := os.Create(fmt.Sprintf("%s/%s", config.Config.CDN.Directory, userUUID))
     if err != nil {
-        return c.JSON(http.StatusInternalServerError, echo.Map{"message": "internal server error"})
+        return err
     }
+    defer dst.Close()
 
     // write to file
     if _, err = io.Copy(dst, src); err != nil {
-        return c.JSON(http.StatusInternalServerError, echo.Map{"message": "internal server error"})
+        return err
     }
 
     return c.NoContent(http.StatusOK)
 }
 
 func handleDeleteUser(c echo.Context, db *ent.Client) error {
-    ctx := c.Request().Context()
+    ctx := ifMatchContext(c)
     authUUID, _ := middleware.IDFromAccessContext(ctx)
 
     // check permissions
-    if !services.DoesUserHavePermission(ctx, db, "delete_user", authUUID) {
-        return c.JSON(http.StatusUnauthorized, echo.Map{
-            "message": "unauthorised",
-        })
+    if err := requirePermission(ctx, db, "delete_user", authUUID); err != nil {
+        return err
     }
 
-    rawUserId := c.Param("id")
-
-    userId, err := uuid.Parse(rawUserId)
+    userId, err := parseUUIDParam(c, "id")
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{"message": "bad request"})
+        return err
     }
 
     _, err = services.DeleteUser(ctx, db, &protoapi.UserDeleteRequest{
         UserId: proto.UUIDToProtoUUID(userId),
     })
-
-    // error checking
     if err != nil {
-        if ent.IsNotFound(err) {
-            return c.JSON(http.StatusNotFound, echo.Map{
-                "message": "user not found",
-            })
-        }
-        if err.Error() == "already deleted" {
-            return c.JSON(http.StatusGone, echo.Map{
-                "message": "user already deleted",
-            })
+        if errors.Is(err, services.ErrAlreadyDeleted) {
+            return services.NewGoneError("user already deleted")
         }
 
-        log.Errorf("uncaught error deleting user: %v", err)
-
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+        return err
     }
 
     return c.NoContent(http.StatusOK)
 }
 
-func handleChangePassword(c echo.Context, db *ent.Client) error {
+func handleRestoreUser(c echo.Context, db *ent.Client) error {
     ctx := c.Request().Context()
     authUUID, _ := middleware.IDFromAccessContext(ctx)
 
-    // TODO: add ability for an application API key to change any user's information
+    // check permissions
+    if err := requirePermission(ctx, db, "restore_user", authUUID); err != nil {
+        return err
+    }
 
-    bytes := make([]byte, c.Request().ContentLength)
-    _, err := c.Request().Body.Read(bytes)
+    userId, err := parseUUIDParam(c, "id")
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
+        return err
     }
 
-    req := new(protoapi.UserChangePasswordRequest)
-    err = protojson.Unmarshal(bytes, req)
+    resp, err := services.RestoreUser(ctx, db, userId)
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": err.Error(),
-        })
+        return err
     }
-    req.UserId = proto.UUIDToProtoUUID(authUUID)
 
-    _, err = services.ChangeUserPassword(ctx, db, req)
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+}
+
+func handleFindUserRoleGrants(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+
+    userId, err := parseUUIDParam(c, "id")
     if err != nil {
-        if errors.Is(err, services.ErrOldPasswordDoesNotMatch) {
-            return c.JSON(http.StatusForbidden, echo.Map{
-                "message": services.ErrOldPasswordDoesNotMatch.Error(),
-            })
-        }
-        if services.IsValidationError(err) {
-            return c.JSON(http.StatusBadRequest, echo.Map{
-                "message": err.Error(),
-            })
-        }
-        if errors.Is(err, services.ErrUserNotFound) {
-            return c.JSON(http.StatusNotFound, echo.Map{
-                "message": err.Error(),
-            })
-        }
+        return err
+    }
+    authUUID, _ := middleware.IDFromAccessContext(ctx)
+    if err = requireSelfOrPermission(ctx, db, "grant_role", authUUID, userId); err != nil {
+        return err
+    }
 
-        log.Errorf("uncaught error changing password: %v", err)
+    resp, err := services.FindUserRoleGrants(ctx, db, &protoapi.UserRoleGrantFindManyRequest{
+        UserId: proto.UUIDToProtoUUID(userId),
+    })
+    if err != nil {
+        if errors.Is(err, services.ErrInvalidUserId) {
+            return services.ErrUserNotFound
+        }
 
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+        return err
     }
 
-    return c.NoContent(http.StatusOK)
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
 }
 
-func handleChangeEmail(c echo.Context, db *ent.Client) error {
+func handleGrantUserRole(c echo.Context, db *ent.Client) error {
     ctx := c.Request().Context()
     authUUID, _ := middleware.IDFromAccessContext(ctx)
 
-    // TODO: add ability for an application API key to change any user's information
+    // check permissions
+    if err := requirePermission(ctx, db, "grant_role", authUUID); err != nil {
+        return err
+    }
 
-    bytes := make([]byte, c.Request().ContentLength)
-    _, err := c.Request().Body.Read(bytes)
+    userId, err := parseUUIDParam(c, "id")
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
+        return err
     }
 
-    req := new(protoapi.UserChangeEmailRequest)
-    err = protojson.Unmarshal(bytes, req)
-    if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": err.Error(),
-        })
+    req := new(protoapi.UserRoleGrantRequest)
+    if err = decodeRequest(c, req); err != nil {
+        return err
+    }
+    req.UserId = proto.UUIDToProtoUUID(userId)
+    if err = services.ValidateRequest(req); err != nil {
+        return err
     }
-    req.UserId = proto.UUIDToProtoUUID(authUUID)
 
-    _, err = services.ChangeUserEmail(ctx, db, req)
+    resp, err := services.GrantUserRole(ctx, db, authUUID, req)
     if err != nil {
-        if services.IsValidationError(err) {
-            return c.JSON(http.StatusBadRequest, echo.Map{
-                "message": err.Error(),
-            })
-        }
+        return err
+    }
 
-        log.Errorf("uncaught error changing email: %v", err)
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusCreated, resp)
+}
 
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+func handleRevokeUserRole(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+    authUUID, _ := middleware.IDFromAccessContext(ctx)
+
+    // check permissions
+    if err := requirePermission(ctx, db, "revoke_role", authUUID); err != nil {
+        return err
+    }
+
+    userId, err := parseUUIDParam(c, "id")
+    if err != nil {
+        return err
+    }
+    grantId, err := parseUUIDParam(c, "grantId")
+    if err != nil {
+        return err
+    }
+
+    _, err = services.RevokeUserRole(ctx, db, &protoapi.UserRoleRevokeRequest{
+        UserId:  proto.UUIDToProtoUUID(userId),
+        GrantId: proto.UUIDToProtoUUID(grantId),
+    })
+    if err != nil {
+        return err
     }
 
     return c.NoContent(http.StatusOK)
 }
 
-func handleChangeUsername(c echo.Context, db *ent.Client) error {
-    ctx := c.Request().Context()
+func handleChangePassword(c echo.Context, db *ent.Client) error {
+    ctx := ifMatchContext(c)
     authUUID, _ := middleware.IDFromAccessContext(ctx)
 
     // TODO: add ability for an application API key to change any user's information
 
-    bytes := make([]byte, c.Request().ContentLength)
-    _, err := c.Request().Body.Read(bytes)
-    if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": "bad request",
-        })
+    req := new(protoapi.UserChangePasswordRequest)
+    if err := decodeRequest(c, req); err != nil {
+        return err
+    }
+    req.UserId = proto.UUIDToProtoUUID(authUUID)
+    if err := services.ValidateRequest(req); err != nil {
+        return err
     }
 
-    req := new(protoapi.UserChangeUsernameRequest)
-    err = protojson.Unmarshal(bytes, req)
+    _, err := services.ChangeUserPassword(ctx, db, req)
     if err != nil {
-        return c.JSON(http.StatusBadRequest, echo.Map{
-            "message": err.Error(),
-        })
+        return err
+    }
+
+    return c.NoContent(http.StatusOK)
+}
+
+func handleChangeEmail(c echo.Context, db *ent.Client) error {
+    ctx := ifMatchContext(c)
+    authUUID, _ := middleware.IDFromAccessContext(ctx)
+
+    // TODO: add ability for an application API key to change any user's information
+
+    req := new(protoapi.UserChangeEmailRequest)
+    if err := decodeRequest(c, req); err != nil {
+        return err
     }
     req.UserId = proto.UUIDToProtoUUID(authUUID)
+    if err := services.ValidateRequest(req); err != nil {
+        return err
+    }
 
-    _, err = services.ChangeUsername(ctx, db, req)
+    _, err := services.ChangeUserEmail(ctx, db, req)
     if err != nil {
-        if services.IsValidationError(err) {
-            return c.JSON(http.StatusBadRequest, echo.Map{
-                "message": err.Error(),
-            })
-        }
+        return err
+    }
 
-        log.Errorf("uncaught error changing username: %v", err)
+    return c.NoContent(http.StatusOK)
+}
 
-        return c.JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+func handleChangeUsername(c echo.Context, db *ent.Client) error {
+    ctx := ifMatchContext(c)
+    authUUID, _ := middleware.IDFromAccessContext(ctx)
+
+    // TODO: add ability for an application API key to change any user's information
+
+    req := new(protoapi.UserChangeUsernameRequest)
+    if err := decodeRequest(c, req); err != nil {
+        return err
+    }
+    req.UserId = proto.UUIDToProtoUUID(authUUID)
+    if err := services.ValidateRequest(req); err != nil {
+        return err
+    }
+
+    _, err := services.ChangeUsername(ctx, db, req)
+    if err != nil {
+        return err
     }
 
     return c.NoContent(http.StatusOK)
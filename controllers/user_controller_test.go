@@ -0,0 +1,33 @@
+package controllers
+
+import (
+    "github.com/Encedeus/panel/ent"
+    "net/http"
+    "testing"
+)
+
+func TestFindUserRoleGrantsRequiresSelfOrPermission(t *testing.T) {
+    srv := newTestServer(t)
+
+    owner := createTestUser(t, srv.DB, "owner")
+    other := createTestUser(t, srv.DB, "other")
+    manager := createTestUser(t, srv.DB, "manager", "grant_role")
+
+    tests := []struct {
+        name string
+        as   *ent.User
+        want int
+    }{
+        {"self", owner, http.StatusOK},
+        {"other user", other, http.StatusForbidden},
+        {"grant_role", manager, http.StatusOK},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            rec := serveAs(t, srv, tt.as.ID, http.MethodGet, "/api/v1/user/"+owner.ID.String()+"/roles", "")
+            if rec.Code != tt.want {
+                t.Errorf("got %d, want %d: %s", rec.Code, tt.want, rec.Body)
+            }
+        })
+    }
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/openapi"
+    "github.com/labstack/echo/v4"
+    "net/http"
+    "strings"
+    "time"
+)
+
+// APIController registers routes served under every API version, on the group of the version
+type APIController interface {
+    registerAPIRoutes(srv *Server, api *echo.Group)
+}
+
+// apiVersion is served under /api/<name>
+type apiVersion struct {
+    name string
+    // controllers register their routes on top of the previous version's,
+    // replacing the handlers of the routes they register again
+    controllers []APIController
+    // operations documents the routes the controllers add or change
+    operations map[string]openapi.Operation
+}
+
+// apiVersions are served in order, each sharing the routes of the one before it it doesn't override
+var apiVersions = []apiVersion{
+    {
+        name: "v1",
+        controllers: []APIController{
+            AuthController{},
+            RoleController{},
+            UserController{},
+            APIKeyController{},
+            WebhookController{},
+            SetupController{},
+        },
+        operations: apiOperations,
+    },
+    {
+        name: "v2",
+        controllers: []APIController{
+            UserControllerV2{},
+            RoleControllerV2{},
+        },
+        operations: apiOperationsV2,
+    },
+}
+
+// legacyVersion is the version the unversioned paths of the first releases are aliases of
+const legacyVersion = "v1"
+
+// legacyDeprecatedAt is when the unversioned paths were deprecated in favor of /api/v1
+var legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+func apiPrefix(version string) string {
+    return "/api/" + version
+}
+
+// registerAPIVersions registers the routes of every API version and keeps serving the unversioned paths
+// of the legacy version's routes as deprecated aliases
+func registerAPIVersions(srv *Server) {
+    var controllers []APIController
+    for _, v := range apiVersions {
+        controllers = append(controllers, v.controllers...)
+
+        api := srv.Group(apiPrefix(v.name))
+        for _, c := range controllers {
+            c.registerAPIRoutes(srv, api)
+        }
+    }
+
+    srv.Pre(legacyPathMiddleware(legacyPrefixes(srv)))
+}
+
+// legacyPrefixes returns the first segments of the legacy version's paths, like /user
+func legacyPrefixes(srv *Server) map[string]bool {
+    prefix := apiPrefix(legacyVersion)
+
+    prefixes := map[string]bool{}
+    for _, r := range srv.Routes() {
+        if path, ok := strings.CutPrefix(r.Path, prefix+"/"); ok {
+            prefixes["/"+strings.SplitN(path, "/", 2)[0]] = true
+        }
+    }
+
+    return prefixes
+}
+
+// legacyPathMiddleware rewrites requests to the unversioned paths to the legacy version before routing,
+// marking the responses as deprecated and pointing to the versioned path
+func legacyPathMiddleware(prefixes map[string]bool) echo.MiddlewareFunc {
+    deprecation := fmt.Sprintf("@%d", legacyDeprecatedAt.Unix())
+
+    return func(next echo.HandlerFunc) echo.HandlerFunc {
+        return func(c echo.Context) error {
+            u := c.Request().URL
+            first := "/" + strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)[0]
+            if !prefixes[first] {
+                return next(c)
+            }
+
+            u.Path = apiPrefix(legacyVersion) + u.Path
+            if u.RawPath != "" {
+                u.RawPath = apiPrefix(legacyVersion) + u.RawPath
+            }
+
+            header := c.Response().Header()
+            header.Set("Deprecation", deprecation)
+            if sunset, err := config.Current().Server.LegacyAPISunsetTime(); err == nil && !sunset.IsZero() {
+                header.Set("Sunset", sunset.Format(http.TimeFormat))
+            }
+            header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, u.EscapedPath()))
+
+            return next(c)
+        }
+    }
+}
+
+// versionedOperations documents the routes of every API version, a version's operations replace
+// the ones of the previous version with the same key
+func versionedOperations() map[string]openapi.Operation {
+    ops := map[string]openapi.Operation{}
+    for key, op := range rootOperations {
+        ops[key] = op
+    }
+
+    inherited := map[string]openapi.Operation{}
+    for _, v := range apiVersions {
+        for key, op := range v.operations {
+            inherited[key] = op
+        }
+
+        for key, op := range inherited {
+            method, path, _ := strings.Cut(key, " ")
+            // tagged by resource instead of the /api prefix
+            if op.Tag == "" {
+                op.Tag = strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+            }
+            ops[method+" "+apiPrefix(v.name)+path] = op
+        }
+    }
+
+    return ops
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/testdb"
+    "net/http"
+    "testing"
+)
+
+func TestLegacyPathsAreDeprecatedAliases(t *testing.T) {
+    srv := newTestServer(t)
+    admin := createTestUser(t, srv.DB, "admin", "*")
+    config.Config.Server.LegacyAPISunset = "2027-04-01"
+
+    role := testdb.CreateRole(t, srv.DB, "member", []string{})
+
+    legacy := serveAs(t, srv, admin.ID, http.MethodGet, "/role/"+role.ID.String(), "")
+    if legacy.Code != http.StatusOK {
+        t.Fatalf("legacy path got %d: %s", legacy.Code, legacy.Body)
+    }
+    header := legacy.Header()
+    if got, want := header.Get("Deprecation"), fmt.Sprintf("@%d", legacyDeprecatedAt.Unix()); got != want {
+        t.Errorf("Deprecation is %q, want %q", got, want)
+    }
+    if got, want := header.Get("Sunset"), "Thu, 01 Apr 2027 00:00:00 GMT"; got != want {
+        t.Errorf("Sunset is %q, want %q", got, want)
+    }
+    if got, want := header.Get("Link"), `</api/v1/role/`+role.ID.String()+`>; rel="successor-version"`; got != want {
+        t.Errorf("Link is %q, want %q", got, want)
+    }
+
+    versioned := serveAs(t, srv, admin.ID, http.MethodGet, "/api/v1/role/"+role.ID.String(), "")
+    if versioned.Code != http.StatusOK || versioned.Header().Get("Deprecation") != "" {
+        t.Errorf("versioned path got %d with Deprecation %q", versioned.Code, versioned.Header().Get("Deprecation"))
+    }
+    if versioned.Body.String() != legacy.Body.String() {
+        t.Errorf("legacy path answered %s, want the same as %s", legacy.Body, versioned.Body)
+    }
+}
+
+func TestVersionsOverrideAndInheritRoutes(t *testing.T) {
+    srv := newTestServer(t)
+    admin := createTestUser(t, srv.DB, "admin", "*")
+
+    tests := []struct {
+        version string
+        want    int
+    }{
+        {"v1", http.StatusOK},
+        {"v2", http.StatusCreated},
+    }
+    for _, tt := range tests {
+        t.Run(tt.version, func(t *testing.T) {
+            rec := serveAs(t, srv, admin.ID, http.MethodPost, "/api/"+tt.version+"/role", `{"name":"role-`+tt.version+`","permissions":["read"]}`)
+            if rec.Code != tt.want {
+                t.Errorf("creating a role got %d, want %d: %s", rec.Code, tt.want, rec.Body)
+            }
+        })
+    }
+
+    // v2 doesn't register the API key routes again, it serves v1's
+    if rec := serveAs(t, srv, admin.ID, http.MethodGet, "/api/v2/key/account/"+admin.ID.String(), ""); rec.Code != http.StatusOK {
+        t.Errorf("inherited route got %d: %s", rec.Code, rec.Body)
+    }
+}
@@ -0,0 +1,208 @@
+package controllers
+
+import (
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/middleware"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "github.com/labstack/echo/v4"
+    "net/http"
+)
+
+// manageWebhooksPermission is required by every webhook route, webhooks receive data about every user and role
+const manageWebhooksPermission = "manage_webhooks"
+
+type WebhookController struct {
+    APIController
+}
+
+func (wc WebhookController) registerAPIRoutes(srv *Server, api *echo.Group) {
+    webhookEndpoint := api.Group("/webhook")
+    {
+        webhookEndpoint.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+            return middleware.AccessJWTAuth(srv.DB, next)
+        })
+        webhookEndpoint.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+            return func(c echo.Context) error {
+                ctx := c.Request().Context()
+                userId, _ := middleware.IDFromAccessContext(ctx)
+                if err := requirePermission(ctx, srv.DB, manageWebhooksPermission, userId); err != nil {
+                    return err
+                }
+
+                return next(c)
+            }
+        })
+
+        webhookEndpoint.GET("", func(c echo.Context) error {
+            return wc.handleFindAllWebhooks(c, srv.DB)
+        })
+        webhookEndpoint.GET("/:id", func(c echo.Context) error {
+            return wc.handleFindWebhook(c, srv.DB)
+        })
+        webhookEndpoint.POST("", func(c echo.Context) error {
+            return wc.handleCreateWebhook(c, srv.DB)
+        })
+        webhookEndpoint.PATCH("/:id", func(c echo.Context) error {
+            return wc.handleUpdateWebhook(c, srv.DB)
+        })
+        webhookEndpoint.DELETE("/:id", func(c echo.Context) error {
+            return wc.handleDeleteWebhook(c, srv.DB)
+        })
+        webhookEndpoint.POST("/:id/test", func(c echo.Context) error {
+            return wc.handleTestWebhook(c, srv.DB)
+        })
+        webhookEndpoint.GET("/:id/deliveries", func(c echo.Context) error {
+            return wc.handleFindWebhookDeliveries(c, srv.DB)
+        })
+        webhookEndpoint.POST("/:id/deliveries/:deliveryId/redeliver", func(c echo.Context) error {
+            return wc.handleRedeliverWebhookDelivery(c, srv.DB)
+        })
+    }
+}
+
+func (WebhookController) handleFindAllWebhooks(c echo.Context, db *ent.Client) error {
+    resp, err := services.FindAllWebhooks(c.Request().Context(), db)
+    if err != nil {
+        return err
+    }
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+}
+
+func (WebhookController) handleFindWebhook(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+
+    id, err := parseUUIDParam(c, "id")
+    if err != nil {
+        return err
+    }
+
+    resp, err := services.FindWebhook(ctx, db, &protoapi.WebhookFindOneRequest{
+        Id: proto.UUIDToProtoUUID(id),
+    })
+    if err != nil {
+        return err
+    }
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+}
+
+func (WebhookController) handleCreateWebhook(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+
+    createReq := new(protoapi.WebhookCreateRequest)
+    if err := bindRequest(c, createReq); err != nil {
+        return err
+    }
+
+    resp, err := services.CreateWebhook(ctx, db, createReq)
+    if err != nil {
+        return err
+    }
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusCreated, resp)
+}
+
+func (WebhookController) handleUpdateWebhook(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+
+    id, err := parseUUIDParam(c, "id")
+    if err != nil {
+        return err
+    }
+
+    updateReq := new(protoapi.WebhookUpdateRequest)
+    if err = decodeRequest(c, updateReq); err != nil {
+        return err
+    }
+    updateReq.Id = proto.UUIDToProtoUUID(id)
+    if err = services.ValidateRequest(updateReq); err != nil {
+        return err
+    }
+
+    resp, err := services.UpdateWebhook(ctx, db, updateReq)
+    if err != nil {
+        return err
+    }
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+}
+
+func (WebhookController) handleDeleteWebhook(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+
+    id, err := parseUUIDParam(c, "id")
+    if err != nil {
+        return err
+    }
+
+    _, err = services.DeleteWebhook(ctx, db, &protoapi.WebhookDeleteRequest{
+        Id: proto.UUIDToProtoUUID(id),
+    })
+    if err != nil {
+        return err
+    }
+
+    return c.NoContent(http.StatusOK)
+}
+
+func (WebhookController) handleTestWebhook(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+
+    id, err := parseUUIDParam(c, "id")
+    if err != nil {
+        return err
+    }
+
+    resp, err := services.TestWebhook(ctx, db, &protoapi.WebhookTestRequest{
+        Id: proto.UUIDToProtoUUID(id),
+    })
+    if err != nil {
+        return err
+    }
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+}
+
+func (WebhookController) handleFindWebhookDeliveries(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+
+    id, err := parseUUIDParam(c, "id")
+    if err != nil {
+        return err
+    }
+
+    resp, err := services.FindWebhookDeliveries(ctx, db, &protoapi.WebhookDeliveryFindManyRequest{
+        WebhookId: proto.UUIDToProtoUUID(id),
+    })
+    if err != nil {
+        return err
+    }
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusOK, resp)
+}
+
+func (WebhookController) handleRedeliverWebhookDelivery(c echo.Context, db *ent.Client) error {
+    ctx := c.Request().Context()
+
+    id, err := parseUUIDParam(c, "id")
+    if err != nil {
+        return err
+    }
+    deliveryId, err := parseUUIDParam(c, "deliveryId")
+    if err != nil {
+        return err
+    }
+
+    resp, err := services.RedeliverWebhookDelivery(ctx, db, &protoapi.WebhookRedeliverRequest{
+        WebhookId:  proto.UUIDToProtoUUID(id),
+        DeliveryId: proto.UUIDToProtoUUID(deliveryId),
+    })
+    if err != nil {
+        return err
+    }
+
+    return proto.MarshalControllerProtoResponseToJSON(&c, http.StatusCreated, resp)
+}
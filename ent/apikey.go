@@ -20,6 +20,8 @@ type ApiKey struct {
 	config `json:"-"`
 	// ID of the ent.
 	ID uuid.UUID `json:"id,omitempty"`
+	// Version holds the value of the "version" field.
+	Version int64 `json:"version,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// UpdatedAt holds the value of the "updated_at" field.
@@ -67,6 +69,8 @@ func (*ApiKey) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case apikey.FieldIPAddresses:
 			values[i] = new([]byte)
+		case apikey.FieldVersion:
+			values[i] = new(sql.NullInt64)
 		case apikey.FieldDescription, apikey.FieldKey:
 			values[i] = new(sql.NullString)
 		case apikey.FieldCreatedAt, apikey.FieldUpdatedAt:
@@ -94,6 +98,12 @@ func (ak *ApiKey) assignValues(columns []string, values []any) error {
 			} else if value != nil {
 				ak.ID = *value
 			}
+		case apikey.FieldVersion:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field version", values[i])
+			} else if value.Valid {
+				ak.Version = value.Int64
+			}
 		case apikey.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
@@ -173,6 +183,9 @@ func (ak *ApiKey) String() string {
 	var builder strings.Builder
 	builder.WriteString("ApiKey(")
 	builder.WriteString(fmt.Sprintf("id=%v, ", ak.ID))
+	builder.WriteString("version=")
+	builder.WriteString(fmt.Sprintf("%v", ak.Version))
+	builder.WriteString(", ")
 	builder.WriteString("created_at=")
 	builder.WriteString(ak.CreatedAt.Format(time.ANSIC))
 	builder.WriteString(", ")
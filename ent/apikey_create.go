@@ -22,6 +22,20 @@ type ApiKeyCreate struct {
 	hooks    []Hook
 }
 
+// SetVersion sets the "version" field.
+func (akc *ApiKeyCreate) SetVersion(i int64) *ApiKeyCreate {
+	akc.mutation.SetVersion(i)
+	return akc
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (akc *ApiKeyCreate) SetNillableVersion(i *int64) *ApiKeyCreate {
+	if i != nil {
+		akc.SetVersion(*i)
+	}
+	return akc
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (akc *ApiKeyCreate) SetCreatedAt(t time.Time) *ApiKeyCreate {
 	akc.mutation.SetCreatedAt(t)
@@ -108,7 +122,9 @@ func (akc *ApiKeyCreate) Mutation() *ApiKeyMutation {
 
 // Save creates the ApiKey in the database.
 func (akc *ApiKeyCreate) Save(ctx context.Context) (*ApiKey, error) {
-	akc.defaults()
+	if err := akc.defaults(); err != nil {
+		return nil, err
+	}
 	return withHooks(ctx, akc.sqlSave, akc.mutation, akc.hooks)
 }
 
@@ -135,23 +151,40 @@ func (akc *ApiKeyCreate) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (akc *ApiKeyCreate) defaults() {
+func (akc *ApiKeyCreate) defaults() error {
+	if _, ok := akc.mutation.Version(); !ok {
+		v := apikey.DefaultVersion
+		akc.mutation.SetVersion(v)
+	}
 	if _, ok := akc.mutation.CreatedAt(); !ok {
+		if apikey.DefaultCreatedAt == nil {
+			return fmt.Errorf("ent: uninitialized apikey.DefaultCreatedAt (forgotten import ent/runtime?)")
+		}
 		v := apikey.DefaultCreatedAt()
 		akc.mutation.SetCreatedAt(v)
 	}
 	if _, ok := akc.mutation.UpdatedAt(); !ok {
+		if apikey.DefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized apikey.DefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
 		v := apikey.DefaultUpdatedAt()
 		akc.mutation.SetUpdatedAt(v)
 	}
 	if _, ok := akc.mutation.ID(); !ok {
+		if apikey.DefaultID == nil {
+			return fmt.Errorf("ent: uninitialized apikey.DefaultID (forgotten import ent/runtime?)")
+		}
 		v := apikey.DefaultID()
 		akc.mutation.SetID(v)
 	}
+	return nil
 }
 
 // check runs all checks and user-defined validators on the builder.
 func (akc *ApiKeyCreate) check() error {
+	if _, ok := akc.mutation.Version(); !ok {
+		return &ValidationError{Name: "version", err: errors.New(`ent: missing required field "ApiKey.version"`)}
+	}
 	if _, ok := akc.mutation.CreatedAt(); !ok {
 		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "ApiKey.created_at"`)}
 	}
@@ -207,6 +240,10 @@ func (akc *ApiKeyCreate) createSpec() (*ApiKey, *sqlgraph.CreateSpec) {
 		_node.ID = id
 		_spec.ID.Value = &id
 	}
+	if value, ok := akc.mutation.Version(); ok {
+		_spec.SetField(apikey.FieldVersion, field.TypeInt64, value)
+		_node.Version = value
+	}
 	if value, ok := akc.mutation.CreatedAt(); ok {
 		_spec.SetField(apikey.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
@@ -298,12 +298,12 @@ func (akq *ApiKeyQuery) WithUser(opts ...func(*UserQuery)) *ApiKeyQuery {
 // Example:
 //
 //	var v []struct {
-//		CreatedAt time.Time `json:"created_at,omitempty"`
+//		Version int64 `json:"version,omitempty"`
 //		Count int `json:"count,omitempty"`
 //	}
 //
 //	client.ApiKey.Query().
-//		GroupBy(apikey.FieldCreatedAt).
+//		GroupBy(apikey.FieldVersion).
 //		Aggregate(ent.Count()).
 //		Scan(ctx, &v)
 func (akq *ApiKeyQuery) GroupBy(field string, fields ...string) *ApiKeyGroupBy {
@@ -321,11 +321,11 @@ func (akq *ApiKeyQuery) GroupBy(field string, fields ...string) *ApiKeyGroupBy {
 // Example:
 //
 //	var v []struct {
-//		CreatedAt time.Time `json:"created_at,omitempty"`
+//		Version int64 `json:"version,omitempty"`
 //	}
 //
 //	client.ApiKey.Query().
-//		Select(apikey.FieldCreatedAt).
+//		Select(apikey.FieldVersion).
 //		Scan(ctx, &v)
 func (akq *ApiKeyQuery) Select(fields ...string) *ApiKeySelect {
 	akq.ctx.Fields = append(akq.ctx.Fields, fields...)
@@ -31,6 +31,27 @@ func (aku *ApiKeyUpdate) Where(ps ...predicate.ApiKey) *ApiKeyUpdate {
 	return aku
 }
 
+// SetVersion sets the "version" field.
+func (aku *ApiKeyUpdate) SetVersion(i int64) *ApiKeyUpdate {
+	aku.mutation.ResetVersion()
+	aku.mutation.SetVersion(i)
+	return aku
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (aku *ApiKeyUpdate) SetNillableVersion(i *int64) *ApiKeyUpdate {
+	if i != nil {
+		aku.SetVersion(*i)
+	}
+	return aku
+}
+
+// AddVersion adds i to the "version" field.
+func (aku *ApiKeyUpdate) AddVersion(i int64) *ApiKeyUpdate {
+	aku.mutation.AddVersion(i)
+	return aku
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (aku *ApiKeyUpdate) SetCreatedAt(t time.Time) *ApiKeyUpdate {
 	aku.mutation.SetCreatedAt(t)
@@ -119,7 +140,9 @@ func (aku *ApiKeyUpdate) ClearUser() *ApiKeyUpdate {
 
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (aku *ApiKeyUpdate) Save(ctx context.Context) (int, error) {
-	aku.defaults()
+	if err := aku.defaults(); err != nil {
+		return 0, err
+	}
 	return withHooks(ctx, aku.sqlSave, aku.mutation, aku.hooks)
 }
 
@@ -146,11 +169,15 @@ func (aku *ApiKeyUpdate) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (aku *ApiKeyUpdate) defaults() {
+func (aku *ApiKeyUpdate) defaults() error {
 	if _, ok := aku.mutation.UpdatedAt(); !ok {
+		if apikey.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized apikey.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
 		v := apikey.UpdateDefaultUpdatedAt()
 		aku.mutation.SetUpdatedAt(v)
 	}
+	return nil
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -178,6 +205,12 @@ func (aku *ApiKeyUpdate) sqlSave(ctx context.Context) (n int, err error) {
 			}
 		}
 	}
+	if value, ok := aku.mutation.Version(); ok {
+		_spec.SetField(apikey.FieldVersion, field.TypeInt64, value)
+	}
+	if value, ok := aku.mutation.AddedVersion(); ok {
+		_spec.AddField(apikey.FieldVersion, field.TypeInt64, value)
+	}
 	if value, ok := aku.mutation.CreatedAt(); ok {
 		_spec.SetField(apikey.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -253,6 +286,27 @@ type ApiKeyUpdateOne struct {
 	mutation *ApiKeyMutation
 }
 
+// SetVersion sets the "version" field.
+func (akuo *ApiKeyUpdateOne) SetVersion(i int64) *ApiKeyUpdateOne {
+	akuo.mutation.ResetVersion()
+	akuo.mutation.SetVersion(i)
+	return akuo
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (akuo *ApiKeyUpdateOne) SetNillableVersion(i *int64) *ApiKeyUpdateOne {
+	if i != nil {
+		akuo.SetVersion(*i)
+	}
+	return akuo
+}
+
+// AddVersion adds i to the "version" field.
+func (akuo *ApiKeyUpdateOne) AddVersion(i int64) *ApiKeyUpdateOne {
+	akuo.mutation.AddVersion(i)
+	return akuo
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (akuo *ApiKeyUpdateOne) SetCreatedAt(t time.Time) *ApiKeyUpdateOne {
 	akuo.mutation.SetCreatedAt(t)
@@ -354,7 +408,9 @@ func (akuo *ApiKeyUpdateOne) Select(field string, fields ...string) *ApiKeyUpdat
 
 // Save executes the query and returns the updated ApiKey entity.
 func (akuo *ApiKeyUpdateOne) Save(ctx context.Context) (*ApiKey, error) {
-	akuo.defaults()
+	if err := akuo.defaults(); err != nil {
+		return nil, err
+	}
 	return withHooks(ctx, akuo.sqlSave, akuo.mutation, akuo.hooks)
 }
 
@@ -381,11 +437,15 @@ func (akuo *ApiKeyUpdateOne) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (akuo *ApiKeyUpdateOne) defaults() {
+func (akuo *ApiKeyUpdateOne) defaults() error {
 	if _, ok := akuo.mutation.UpdatedAt(); !ok {
+		if apikey.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized apikey.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
 		v := apikey.UpdateDefaultUpdatedAt()
 		akuo.mutation.SetUpdatedAt(v)
 	}
+	return nil
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -430,6 +490,12 @@ func (akuo *ApiKeyUpdateOne) sqlSave(ctx context.Context) (_node *ApiKey, err er
 			}
 		}
 	}
+	if value, ok := akuo.mutation.Version(); ok {
+		_spec.SetField(apikey.FieldVersion, field.TypeInt64, value)
+	}
+	if value, ok := akuo.mutation.AddedVersion(); ok {
+		_spec.AddField(apikey.FieldVersion, field.TypeInt64, value)
+	}
 	if value, ok := akuo.mutation.CreatedAt(); ok {
 		_spec.SetField(apikey.FieldCreatedAt, field.TypeTime, value)
 	}
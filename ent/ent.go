@@ -13,8 +13,12 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/Encedeus/panel/ent/apikey"
+	"github.com/Encedeus/panel/ent/outboxevent"
 	"github.com/Encedeus/panel/ent/role"
+	"github.com/Encedeus/panel/ent/rolegrant"
 	"github.com/Encedeus/panel/ent/user"
+	"github.com/Encedeus/panel/ent/webhook"
+	"github.com/Encedeus/panel/ent/webhookdelivery"
 )
 
 // ent aliases to avoid import conflicts in user's code.
@@ -75,9 +79,13 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			apikey.Table: apikey.ValidColumn,
-			role.Table:   role.ValidColumn,
-			user.Table:   user.ValidColumn,
+			apikey.Table:          apikey.ValidColumn,
+			outboxevent.Table:     outboxevent.ValidColumn,
+			role.Table:            role.ValidColumn,
+			rolegrant.Table:       rolegrant.ValidColumn,
+			user.Table:            user.ValidColumn,
+			webhook.Table:         webhook.ValidColumn,
+			webhookdelivery.Table: webhookdelivery.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
@@ -0,0 +1,63 @@
+//go:build ignore
+
+package main
+
+import (
+    "ariga.io/atlas/sql/sqltool"
+    "context"
+    "database/sql"
+    "entgo.io/ent/dialect"
+    entsql "entgo.io/ent/dialect/sql"
+    "entgo.io/ent/dialect/sql/schema"
+    "github.com/Encedeus/panel/ent/migrate"
+    _ "github.com/lib/pq"
+    "log"
+    "os"
+    _ "modernc.org/sqlite"
+)
+
+// Generates a new migration for every supported database from the difference between the ent schema
+// and the existing migrations. The postgres dev database is only used to replay the migrations and must be empty,
+// sqlite replays them in memory:
+//
+//	go run -mod=mod ent/migrate/main.go <name> "host=localhost user=postgres password=root dbname=migrate_dev sslmode=disable"
+func main() {
+    if len(os.Args) != 3 {
+        log.Fatalln("usage: go run -mod=mod ent/migrate/main.go <name> <postgres dev database dsn>")
+    }
+    name, postgresDSN := os.Args[1], os.Args[2]
+
+    diff(dialect.Postgres, "postgres", postgresDSN, name)
+    diff(dialect.SQLite, "sqlite", "file:dev?mode=memory&_pragma=foreign_keys(1)", name)
+}
+
+func diff(entDialect string, driver string, dsn string, name string) {
+    db, err := sql.Open(driver, dsn)
+    if err != nil {
+        log.Fatalf("failed opening %s dev database: %v", entDialect, err)
+    }
+    defer db.Close()
+    // an in-memory sqlite database only lives as long as its connection
+    db.SetMaxOpenConns(1)
+
+    dir, err := sqltool.NewGolangMigrateDir("ent/migrate/migrations/" + driver)
+    if err != nil {
+        log.Fatalf("failed opening %s migration directory: %v", entDialect, err)
+    }
+
+    m, err := schema.NewMigrate(entsql.OpenDB(entDialect, db),
+        schema.WithDir(dir),
+        schema.WithMigrationMode(schema.ModeReplay),
+        schema.WithDialect(entDialect),
+        schema.WithFormatter(sqltool.GolangMigrateFormatter),
+        schema.WithDropColumn(true),
+        schema.WithDropIndex(true),
+    )
+    if err != nil {
+        log.Fatalf("failed creating %s migration: %v", entDialect, err)
+    }
+
+    if err := m.NamedDiff(context.Background(), name, migrate.Tables...); err != nil {
+        log.Fatalf("failed generating %s migration: %v", entDialect, err)
+    }
+}
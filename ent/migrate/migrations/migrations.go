@@ -0,0 +1,10 @@
+// Package migrations holds the versioned SQL migrations of every supported database,
+// generated from the ent schema by ent/migrate/main.go
+package migrations
+
+import "embed"
+
+// FS holds a directory of migrations per database driver
+//
+//go:embed postgres/*.sql sqlite/*.sql
+var FS embed.FS
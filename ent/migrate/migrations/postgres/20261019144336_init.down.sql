@@ -0,0 +1,10 @@
+-- reverse: create "api_keys" table
+DROP TABLE "api_keys";
+-- reverse: create index "users_name_key" to table: "users"
+DROP INDEX "users_name_key";
+-- reverse: create "users" table
+DROP TABLE "users";
+-- reverse: create index "roles_name_key" to table: "roles"
+DROP INDEX "roles_name_key";
+-- reverse: create "roles" table
+DROP TABLE "roles";
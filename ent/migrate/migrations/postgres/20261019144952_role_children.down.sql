@@ -0,0 +1,2 @@
+-- reverse: create "role_children" table
+DROP TABLE "role_children";
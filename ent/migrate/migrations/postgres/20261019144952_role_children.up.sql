@@ -0,0 +1,2 @@
+-- create "role_children" table
+CREATE TABLE "role_children" ("role_id" uuid NOT NULL, "parent_id" uuid NOT NULL, PRIMARY KEY ("role_id", "parent_id"), CONSTRAINT "role_children_role_id" FOREIGN KEY ("role_id") REFERENCES "roles" ("id") ON DELETE CASCADE, CONSTRAINT "role_children_parent_id" FOREIGN KEY ("parent_id") REFERENCES "roles" ("id") ON DELETE CASCADE);
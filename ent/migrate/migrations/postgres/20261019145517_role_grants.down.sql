@@ -0,0 +1,6 @@
+-- reverse: create index "rolegrant_expires_at" to table: "role_grants"
+DROP INDEX "rolegrant_expires_at";
+-- reverse: create index "rolegrant_user_id" to table: "role_grants"
+DROP INDEX "rolegrant_user_id";
+-- reverse: create "role_grants" table
+DROP TABLE "role_grants";
@@ -0,0 +1,6 @@
+-- create "role_grants" table
+CREATE TABLE "role_grants" ("id" uuid NOT NULL, "created_at" timestamptz NOT NULL, "expires_at" timestamptz NULL, "expired_at" timestamptz NULL, "granted_by" uuid NULL, "user_id" uuid NOT NULL, "role_id" uuid NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "role_grants_users_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE NO ACTION, CONSTRAINT "role_grants_roles_role" FOREIGN KEY ("role_id") REFERENCES "roles" ("id") ON DELETE NO ACTION);
+-- create index "rolegrant_user_id" to table: "role_grants"
+CREATE INDEX "rolegrant_user_id" ON "role_grants" ("user_id");
+-- create index "rolegrant_expires_at" to table: "role_grants"
+CREATE INDEX "rolegrant_expires_at" ON "role_grants" ("expires_at");
@@ -0,0 +1,8 @@
+-- reverse: create index "webhookdelivery_status_next_attempt_at" to table: "webhook_deliveries"
+DROP INDEX "webhookdelivery_status_next_attempt_at";
+-- reverse: create index "webhookdelivery_webhook_id_created_at" to table: "webhook_deliveries"
+DROP INDEX "webhookdelivery_webhook_id_created_at";
+-- reverse: create "webhook_deliveries" table
+DROP TABLE "webhook_deliveries";
+-- reverse: create "webhooks" table
+DROP TABLE "webhooks";
@@ -0,0 +1,4 @@
+-- create "outbox_events" table
+CREATE TABLE "outbox_events" ("id" uuid NOT NULL, "created_at" timestamptz NOT NULL, "type" character varying NOT NULL, "payload" text NOT NULL, "claimed_until" timestamptz NOT NULL, "claims" bigint NOT NULL DEFAULT 0, PRIMARY KEY ("id"));
+-- create index "outboxevent_claimed_until" to table: "outbox_events"
+CREATE INDEX "outboxevent_claimed_until" ON "outbox_events" ("claimed_until");
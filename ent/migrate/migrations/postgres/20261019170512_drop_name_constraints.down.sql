@@ -0,0 +1,8 @@
+-- reverse: create index "role_name" to table: "roles"
+DROP INDEX "role_name";
+-- reverse: create index "user_name" to table: "users"
+DROP INDEX "user_name";
+-- reverse: drop index "roles_name_key" from table: "roles", fails while a soft-deleted role shares its name with another
+CREATE UNIQUE INDEX "roles_name_key" ON "roles" ("name");
+-- reverse: drop index "users_name_key" from table: "users", fails while a soft-deleted user shares its name with another
+CREATE UNIQUE INDEX "users_name_key" ON "users" ("name");
@@ -0,0 +1,12 @@
+-- db.Schema.Create made the names unique for soft-deleted users and roles too, as an index or, on databases
+-- older than that, as a constraint. The partial indexes replacing them only cover users and roles that aren't deleted
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_name_key";
+ALTER TABLE "roles" DROP CONSTRAINT IF EXISTS "roles_name_key";
+-- drop index "users_name_key" from table: "users"
+DROP INDEX IF EXISTS "users_name_key";
+-- drop index "roles_name_key" from table: "roles"
+DROP INDEX IF EXISTS "roles_name_key";
+-- create index "user_name" to table: "users"
+CREATE UNIQUE INDEX IF NOT EXISTS "user_name" ON "users" ("name") WHERE deleted_at IS NULL;
+-- create index "role_name" to table: "roles"
+CREATE UNIQUE INDEX IF NOT EXISTS "role_name" ON "roles" ("name") WHERE deleted_at IS NULL;
@@ -0,0 +1,10 @@
+-- create "roles" table
+CREATE TABLE `roles` (`id` uuid NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, `deleted_at` datetime NULL, `name` text NOT NULL, `permissions` json NULL, PRIMARY KEY (`id`));
+-- create index "roles_name_key" to table: "roles"
+CREATE UNIQUE INDEX `roles_name_key` ON `roles` (`name`);
+-- create "users" table
+CREATE TABLE `users` (`id` uuid NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, `deleted_at` datetime NULL, `email` text NOT NULL, `password` text NOT NULL, `name` text NOT NULL, `role_id` uuid NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `users_roles_role` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE NO ACTION);
+-- create index "users_name_key" to table: "users"
+CREATE UNIQUE INDEX `users_name_key` ON `users` (`name`);
+-- create "api_keys" table
+CREATE TABLE `api_keys` (`id` uuid NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, `description` text NULL, `ip_addresses` json NULL, `key` text NOT NULL, `user_id` uuid NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `api_keys_users_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION);
@@ -0,0 +1,4 @@
+-- reverse: create index "outboxevent_claimed_until" to table: "outbox_events"
+DROP INDEX `outboxevent_claimed_until`;
+-- reverse: create "outbox_events" table
+DROP TABLE `outbox_events`;
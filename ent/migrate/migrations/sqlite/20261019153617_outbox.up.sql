@@ -0,0 +1,4 @@
+-- create "outbox_events" table
+CREATE TABLE `outbox_events` (`id` uuid NOT NULL, `created_at` datetime NOT NULL, `type` text NOT NULL, `payload` text NOT NULL, `claimed_until` datetime NOT NULL, `claims` integer NOT NULL DEFAULT 0, PRIMARY KEY (`id`));
+-- create index "outboxevent_claimed_until" to table: "outbox_events"
+CREATE INDEX `outboxevent_claimed_until` ON `outbox_events` (`claimed_until`);
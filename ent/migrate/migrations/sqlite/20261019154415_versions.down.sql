@@ -0,0 +1,6 @@
+-- reverse: modify "users" table
+ALTER TABLE `users` DROP COLUMN `version`;
+-- reverse: modify "roles" table
+ALTER TABLE `roles` DROP COLUMN `version`;
+-- reverse: modify "api_keys" table
+ALTER TABLE `api_keys` DROP COLUMN `version`;
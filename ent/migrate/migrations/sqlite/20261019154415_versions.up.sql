@@ -0,0 +1,6 @@
+-- modify "api_keys" table
+ALTER TABLE `api_keys` ADD COLUMN `version` integer NOT NULL DEFAULT 1;
+-- modify "roles" table
+ALTER TABLE `roles` ADD COLUMN `version` integer NOT NULL DEFAULT 1;
+-- modify "users" table
+ALTER TABLE `users` ADD COLUMN `version` integer NOT NULL DEFAULT 1;
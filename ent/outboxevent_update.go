@@ -0,0 +1,318 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/Encedeus/panel/ent/outboxevent"
+	"github.com/Encedeus/panel/ent/predicate"
+)
+
+// OutboxEventUpdate is the builder for updating OutboxEvent entities.
+type OutboxEventUpdate struct {
+	config
+	hooks    []Hook
+	mutation *OutboxEventMutation
+}
+
+// Where appends a list predicates to the OutboxEventUpdate builder.
+func (oeu *OutboxEventUpdate) Where(ps ...predicate.OutboxEvent) *OutboxEventUpdate {
+	oeu.mutation.Where(ps...)
+	return oeu
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (oeu *OutboxEventUpdate) SetCreatedAt(t time.Time) *OutboxEventUpdate {
+	oeu.mutation.SetCreatedAt(t)
+	return oeu
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (oeu *OutboxEventUpdate) SetNillableCreatedAt(t *time.Time) *OutboxEventUpdate {
+	if t != nil {
+		oeu.SetCreatedAt(*t)
+	}
+	return oeu
+}
+
+// SetType sets the "type" field.
+func (oeu *OutboxEventUpdate) SetType(s string) *OutboxEventUpdate {
+	oeu.mutation.SetType(s)
+	return oeu
+}
+
+// SetPayload sets the "payload" field.
+func (oeu *OutboxEventUpdate) SetPayload(s string) *OutboxEventUpdate {
+	oeu.mutation.SetPayload(s)
+	return oeu
+}
+
+// SetClaimedUntil sets the "claimed_until" field.
+func (oeu *OutboxEventUpdate) SetClaimedUntil(t time.Time) *OutboxEventUpdate {
+	oeu.mutation.SetClaimedUntil(t)
+	return oeu
+}
+
+// SetClaims sets the "claims" field.
+func (oeu *OutboxEventUpdate) SetClaims(i int) *OutboxEventUpdate {
+	oeu.mutation.ResetClaims()
+	oeu.mutation.SetClaims(i)
+	return oeu
+}
+
+// SetNillableClaims sets the "claims" field if the given value is not nil.
+func (oeu *OutboxEventUpdate) SetNillableClaims(i *int) *OutboxEventUpdate {
+	if i != nil {
+		oeu.SetClaims(*i)
+	}
+	return oeu
+}
+
+// AddClaims adds i to the "claims" field.
+func (oeu *OutboxEventUpdate) AddClaims(i int) *OutboxEventUpdate {
+	oeu.mutation.AddClaims(i)
+	return oeu
+}
+
+// Mutation returns the OutboxEventMutation object of the builder.
+func (oeu *OutboxEventUpdate) Mutation() *OutboxEventMutation {
+	return oeu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (oeu *OutboxEventUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, oeu.sqlSave, oeu.mutation, oeu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (oeu *OutboxEventUpdate) SaveX(ctx context.Context) int {
+	affected, err := oeu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (oeu *OutboxEventUpdate) Exec(ctx context.Context) error {
+	_, err := oeu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (oeu *OutboxEventUpdate) ExecX(ctx context.Context) {
+	if err := oeu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (oeu *OutboxEventUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(outboxevent.Table, outboxevent.Columns, sqlgraph.NewFieldSpec(outboxevent.FieldID, field.TypeUUID))
+	if ps := oeu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := oeu.mutation.CreatedAt(); ok {
+		_spec.SetField(outboxevent.FieldCreatedAt, field.TypeTime, value)
+	}
+	if value, ok := oeu.mutation.GetType(); ok {
+		_spec.SetField(outboxevent.FieldType, field.TypeString, value)
+	}
+	if value, ok := oeu.mutation.Payload(); ok {
+		_spec.SetField(outboxevent.FieldPayload, field.TypeString, value)
+	}
+	if value, ok := oeu.mutation.ClaimedUntil(); ok {
+		_spec.SetField(outboxevent.FieldClaimedUntil, field.TypeTime, value)
+	}
+	if value, ok := oeu.mutation.Claims(); ok {
+		_spec.SetField(outboxevent.FieldClaims, field.TypeInt, value)
+	}
+	if value, ok := oeu.mutation.AddedClaims(); ok {
+		_spec.AddField(outboxevent.FieldClaims, field.TypeInt, value)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, oeu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{outboxevent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	oeu.mutation.done = true
+	return n, nil
+}
+
+// OutboxEventUpdateOne is the builder for updating a single OutboxEvent entity.
+type OutboxEventUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *OutboxEventMutation
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (oeuo *OutboxEventUpdateOne) SetCreatedAt(t time.Time) *OutboxEventUpdateOne {
+	oeuo.mutation.SetCreatedAt(t)
+	return oeuo
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (oeuo *OutboxEventUpdateOne) SetNillableCreatedAt(t *time.Time) *OutboxEventUpdateOne {
+	if t != nil {
+		oeuo.SetCreatedAt(*t)
+	}
+	return oeuo
+}
+
+// SetType sets the "type" field.
+func (oeuo *OutboxEventUpdateOne) SetType(s string) *OutboxEventUpdateOne {
+	oeuo.mutation.SetType(s)
+	return oeuo
+}
+
+// SetPayload sets the "payload" field.
+func (oeuo *OutboxEventUpdateOne) SetPayload(s string) *OutboxEventUpdateOne {
+	oeuo.mutation.SetPayload(s)
+	return oeuo
+}
+
+// SetClaimedUntil sets the "claimed_until" field.
+func (oeuo *OutboxEventUpdateOne) SetClaimedUntil(t time.Time) *OutboxEventUpdateOne {
+	oeuo.mutation.SetClaimedUntil(t)
+	return oeuo
+}
+
+// SetClaims sets the "claims" field.
+func (oeuo *OutboxEventUpdateOne) SetClaims(i int) *OutboxEventUpdateOne {
+	oeuo.mutation.ResetClaims()
+	oeuo.mutation.SetClaims(i)
+	return oeuo
+}
+
+// SetNillableClaims sets the "claims" field if the given value is not nil.
+func (oeuo *OutboxEventUpdateOne) SetNillableClaims(i *int) *OutboxEventUpdateOne {
+	if i != nil {
+		oeuo.SetClaims(*i)
+	}
+	return oeuo
+}
+
+// AddClaims adds i to the "claims" field.
+func (oeuo *OutboxEventUpdateOne) AddClaims(i int) *OutboxEventUpdateOne {
+	oeuo.mutation.AddClaims(i)
+	return oeuo
+}
+
+// Mutation returns the OutboxEventMutation object of the builder.
+func (oeuo *OutboxEventUpdateOne) Mutation() *OutboxEventMutation {
+	return oeuo.mutation
+}
+
+// Where appends a list predicates to the OutboxEventUpdate builder.
+func (oeuo *OutboxEventUpdateOne) Where(ps ...predicate.OutboxEvent) *OutboxEventUpdateOne {
+	oeuo.mutation.Where(ps...)
+	return oeuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (oeuo *OutboxEventUpdateOne) Select(field string, fields ...string) *OutboxEventUpdateOne {
+	oeuo.fields = append([]string{field}, fields...)
+	return oeuo
+}
+
+// Save executes the query and returns the updated OutboxEvent entity.
+func (oeuo *OutboxEventUpdateOne) Save(ctx context.Context) (*OutboxEvent, error) {
+	return withHooks(ctx, oeuo.sqlSave, oeuo.mutation, oeuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (oeuo *OutboxEventUpdateOne) SaveX(ctx context.Context) *OutboxEvent {
+	node, err := oeuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (oeuo *OutboxEventUpdateOne) Exec(ctx context.Context) error {
+	_, err := oeuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (oeuo *OutboxEventUpdateOne) ExecX(ctx context.Context) {
+	if err := oeuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (oeuo *OutboxEventUpdateOne) sqlSave(ctx context.Context) (_node *OutboxEvent, err error) {
+	_spec := sqlgraph.NewUpdateSpec(outboxevent.Table, outboxevent.Columns, sqlgraph.NewFieldSpec(outboxevent.FieldID, field.TypeUUID))
+	id, ok := oeuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "OutboxEvent.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := oeuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, outboxevent.FieldID)
+		for _, f := range fields {
+			if !outboxevent.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != outboxevent.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := oeuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := oeuo.mutation.CreatedAt(); ok {
+		_spec.SetField(outboxevent.FieldCreatedAt, field.TypeTime, value)
+	}
+	if value, ok := oeuo.mutation.GetType(); ok {
+		_spec.SetField(outboxevent.FieldType, field.TypeString, value)
+	}
+	if value, ok := oeuo.mutation.Payload(); ok {
+		_spec.SetField(outboxevent.FieldPayload, field.TypeString, value)
+	}
+	if value, ok := oeuo.mutation.ClaimedUntil(); ok {
+		_spec.SetField(outboxevent.FieldClaimedUntil, field.TypeTime, value)
+	}
+	if value, ok := oeuo.mutation.Claims(); ok {
+		_spec.SetField(outboxevent.FieldClaims, field.TypeInt, value)
+	}
+	if value, ok := oeuo.mutation.AddedClaims(); ok {
+		_spec.AddField(outboxevent.FieldClaims, field.TypeInt, value)
+	}
+	_node = &OutboxEvent{config: oeuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, oeuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{outboxevent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	oeuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,223 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/Encedeus/panel/ent/role"
+	"github.com/Encedeus/panel/ent/rolegrant"
+	"github.com/Encedeus/panel/ent/user"
+	"github.com/google/uuid"
+)
+
+// RoleGrant is the model entity for the RoleGrant schema.
+type RoleGrant struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID uuid.UUID `json:"id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// ExpiresAt holds the value of the "expires_at" field.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// ExpiredAt holds the value of the "expired_at" field.
+	ExpiredAt *time.Time `json:"expired_at,omitempty"`
+	// GrantedBy holds the value of the "granted_by" field.
+	GrantedBy *uuid.UUID `json:"granted_by,omitempty"`
+	// UserID holds the value of the "user_id" field.
+	UserID uuid.UUID `json:"user_id,omitempty"`
+	// RoleID holds the value of the "role_id" field.
+	RoleID uuid.UUID `json:"role_id,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the RoleGrantQuery when eager-loading is set.
+	Edges        RoleGrantEdges `json:"edges"`
+	selectValues sql.SelectValues
+}
+
+// RoleGrantEdges holds the relations/edges for other nodes in the graph.
+type RoleGrantEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// Role holds the value of the role edge.
+	Role *Role `json:"role,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [2]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e RoleGrantEdges) UserOrErr() (*User, error) {
+	if e.loadedTypes[0] {
+		if e.User == nil {
+			// Edge was loaded but was not found.
+			return nil, &NotFoundError{label: user.Label}
+		}
+		return e.User, nil
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// RoleOrErr returns the Role value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e RoleGrantEdges) RoleOrErr() (*Role, error) {
+	if e.loadedTypes[1] {
+		if e.Role == nil {
+			// Edge was loaded but was not found.
+			return nil, &NotFoundError{label: role.Label}
+		}
+		return e.Role, nil
+	}
+	return nil, &NotLoadedError{edge: "role"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*RoleGrant) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case rolegrant.FieldGrantedBy:
+			values[i] = &sql.NullScanner{S: new(uuid.UUID)}
+		case rolegrant.FieldCreatedAt, rolegrant.FieldExpiresAt, rolegrant.FieldExpiredAt:
+			values[i] = new(sql.NullTime)
+		case rolegrant.FieldID, rolegrant.FieldUserID, rolegrant.FieldRoleID:
+			values[i] = new(uuid.UUID)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the RoleGrant fields.
+func (rg *RoleGrant) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case rolegrant.FieldID:
+			if value, ok := values[i].(*uuid.UUID); !ok {
+				return fmt.Errorf("unexpected type %T for field id", values[i])
+			} else if value != nil {
+				rg.ID = *value
+			}
+		case rolegrant.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				rg.CreatedAt = value.Time
+			}
+		case rolegrant.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				rg.ExpiresAt = new(time.Time)
+				*rg.ExpiresAt = value.Time
+			}
+		case rolegrant.FieldExpiredAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expired_at", values[i])
+			} else if value.Valid {
+				rg.ExpiredAt = new(time.Time)
+				*rg.ExpiredAt = value.Time
+			}
+		case rolegrant.FieldGrantedBy:
+			if value, ok := values[i].(*sql.NullScanner); !ok {
+				return fmt.Errorf("unexpected type %T for field granted_by", values[i])
+			} else if value.Valid {
+				rg.GrantedBy = new(uuid.UUID)
+				*rg.GrantedBy = *value.S.(*uuid.UUID)
+			}
+		case rolegrant.FieldUserID:
+			if value, ok := values[i].(*uuid.UUID); !ok {
+				return fmt.Errorf("unexpected type %T for field user_id", values[i])
+			} else if value != nil {
+				rg.UserID = *value
+			}
+		case rolegrant.FieldRoleID:
+			if value, ok := values[i].(*uuid.UUID); !ok {
+				return fmt.Errorf("unexpected type %T for field role_id", values[i])
+			} else if value != nil {
+				rg.RoleID = *value
+			}
+		default:
+			rg.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the RoleGrant.
+// This includes values selected through modifiers, order, etc.
+func (rg *RoleGrant) Value(name string) (ent.Value, error) {
+	return rg.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the RoleGrant entity.
+func (rg *RoleGrant) QueryUser() *UserQuery {
+	return NewRoleGrantClient(rg.config).QueryUser(rg)
+}
+
+// QueryRole queries the "role" edge of the RoleGrant entity.
+func (rg *RoleGrant) QueryRole() *RoleQuery {
+	return NewRoleGrantClient(rg.config).QueryRole(rg)
+}
+
+// Update returns a builder for updating this RoleGrant.
+// Note that you need to call RoleGrant.Unwrap() before calling this method if this RoleGrant
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (rg *RoleGrant) Update() *RoleGrantUpdateOne {
+	return NewRoleGrantClient(rg.config).UpdateOne(rg)
+}
+
+// Unwrap unwraps the RoleGrant entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (rg *RoleGrant) Unwrap() *RoleGrant {
+	_tx, ok := rg.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: RoleGrant is not a transactional entity")
+	}
+	rg.config.driver = _tx.drv
+	return rg
+}
+
+// String implements the fmt.Stringer.
+func (rg *RoleGrant) String() string {
+	var builder strings.Builder
+	builder.WriteString("RoleGrant(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", rg.ID))
+	builder.WriteString("created_at=")
+	builder.WriteString(rg.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := rg.ExpiresAt; v != nil {
+		builder.WriteString("expires_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := rg.ExpiredAt; v != nil {
+		builder.WriteString("expired_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := rg.GrantedBy; v != nil {
+		builder.WriteString("granted_by=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("user_id=")
+	builder.WriteString(fmt.Sprintf("%v", rg.UserID))
+	builder.WriteString(", ")
+	builder.WriteString("role_id=")
+	builder.WriteString(fmt.Sprintf("%v", rg.RoleID))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// RoleGrants is a parsable slice of RoleGrant.
+type RoleGrants []*RoleGrant
@@ -0,0 +1,346 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/Encedeus/panel/ent/role"
+	"github.com/Encedeus/panel/ent/rolegrant"
+	"github.com/Encedeus/panel/ent/user"
+	"github.com/google/uuid"
+)
+
+// RoleGrantCreate is the builder for creating a RoleGrant entity.
+type RoleGrantCreate struct {
+	config
+	mutation *RoleGrantMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (rgc *RoleGrantCreate) SetCreatedAt(t time.Time) *RoleGrantCreate {
+	rgc.mutation.SetCreatedAt(t)
+	return rgc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (rgc *RoleGrantCreate) SetNillableCreatedAt(t *time.Time) *RoleGrantCreate {
+	if t != nil {
+		rgc.SetCreatedAt(*t)
+	}
+	return rgc
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (rgc *RoleGrantCreate) SetExpiresAt(t time.Time) *RoleGrantCreate {
+	rgc.mutation.SetExpiresAt(t)
+	return rgc
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (rgc *RoleGrantCreate) SetNillableExpiresAt(t *time.Time) *RoleGrantCreate {
+	if t != nil {
+		rgc.SetExpiresAt(*t)
+	}
+	return rgc
+}
+
+// SetExpiredAt sets the "expired_at" field.
+func (rgc *RoleGrantCreate) SetExpiredAt(t time.Time) *RoleGrantCreate {
+	rgc.mutation.SetExpiredAt(t)
+	return rgc
+}
+
+// SetNillableExpiredAt sets the "expired_at" field if the given value is not nil.
+func (rgc *RoleGrantCreate) SetNillableExpiredAt(t *time.Time) *RoleGrantCreate {
+	if t != nil {
+		rgc.SetExpiredAt(*t)
+	}
+	return rgc
+}
+
+// SetGrantedBy sets the "granted_by" field.
+func (rgc *RoleGrantCreate) SetGrantedBy(u uuid.UUID) *RoleGrantCreate {
+	rgc.mutation.SetGrantedBy(u)
+	return rgc
+}
+
+// SetNillableGrantedBy sets the "granted_by" field if the given value is not nil.
+func (rgc *RoleGrantCreate) SetNillableGrantedBy(u *uuid.UUID) *RoleGrantCreate {
+	if u != nil {
+		rgc.SetGrantedBy(*u)
+	}
+	return rgc
+}
+
+// SetUserID sets the "user_id" field.
+func (rgc *RoleGrantCreate) SetUserID(u uuid.UUID) *RoleGrantCreate {
+	rgc.mutation.SetUserID(u)
+	return rgc
+}
+
+// SetRoleID sets the "role_id" field.
+func (rgc *RoleGrantCreate) SetRoleID(u uuid.UUID) *RoleGrantCreate {
+	rgc.mutation.SetRoleID(u)
+	return rgc
+}
+
+// SetID sets the "id" field.
+func (rgc *RoleGrantCreate) SetID(u uuid.UUID) *RoleGrantCreate {
+	rgc.mutation.SetID(u)
+	return rgc
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (rgc *RoleGrantCreate) SetNillableID(u *uuid.UUID) *RoleGrantCreate {
+	if u != nil {
+		rgc.SetID(*u)
+	}
+	return rgc
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (rgc *RoleGrantCreate) SetUser(u *User) *RoleGrantCreate {
+	return rgc.SetUserID(u.ID)
+}
+
+// SetRole sets the "role" edge to the Role entity.
+func (rgc *RoleGrantCreate) SetRole(r *Role) *RoleGrantCreate {
+	return rgc.SetRoleID(r.ID)
+}
+
+// Mutation returns the RoleGrantMutation object of the builder.
+func (rgc *RoleGrantCreate) Mutation() *RoleGrantMutation {
+	return rgc.mutation
+}
+
+// Save creates the RoleGrant in the database.
+func (rgc *RoleGrantCreate) Save(ctx context.Context) (*RoleGrant, error) {
+	rgc.defaults()
+	return withHooks(ctx, rgc.sqlSave, rgc.mutation, rgc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (rgc *RoleGrantCreate) SaveX(ctx context.Context) *RoleGrant {
+	v, err := rgc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (rgc *RoleGrantCreate) Exec(ctx context.Context) error {
+	_, err := rgc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rgc *RoleGrantCreate) ExecX(ctx context.Context) {
+	if err := rgc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (rgc *RoleGrantCreate) defaults() {
+	if _, ok := rgc.mutation.CreatedAt(); !ok {
+		v := rolegrant.DefaultCreatedAt()
+		rgc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := rgc.mutation.ID(); !ok {
+		v := rolegrant.DefaultID()
+		rgc.mutation.SetID(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rgc *RoleGrantCreate) check() error {
+	if _, ok := rgc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "RoleGrant.created_at"`)}
+	}
+	if _, ok := rgc.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "RoleGrant.user_id"`)}
+	}
+	if _, ok := rgc.mutation.RoleID(); !ok {
+		return &ValidationError{Name: "role_id", err: errors.New(`ent: missing required field "RoleGrant.role_id"`)}
+	}
+	if _, ok := rgc.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "RoleGrant.user"`)}
+	}
+	if _, ok := rgc.mutation.RoleID(); !ok {
+		return &ValidationError{Name: "role", err: errors.New(`ent: missing required edge "RoleGrant.role"`)}
+	}
+	return nil
+}
+
+func (rgc *RoleGrantCreate) sqlSave(ctx context.Context) (*RoleGrant, error) {
+	if err := rgc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := rgc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, rgc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(*uuid.UUID); ok {
+			_node.ID = *id
+		} else if err := _node.ID.Scan(_spec.ID.Value); err != nil {
+			return nil, err
+		}
+	}
+	rgc.mutation.id = &_node.ID
+	rgc.mutation.done = true
+	return _node, nil
+}
+
+func (rgc *RoleGrantCreate) createSpec() (*RoleGrant, *sqlgraph.CreateSpec) {
+	var (
+		_node = &RoleGrant{config: rgc.config}
+		_spec = sqlgraph.NewCreateSpec(rolegrant.Table, sqlgraph.NewFieldSpec(rolegrant.FieldID, field.TypeUUID))
+	)
+	if id, ok := rgc.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = &id
+	}
+	if value, ok := rgc.mutation.CreatedAt(); ok {
+		_spec.SetField(rolegrant.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := rgc.mutation.ExpiresAt(); ok {
+		_spec.SetField(rolegrant.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = &value
+	}
+	if value, ok := rgc.mutation.ExpiredAt(); ok {
+		_spec.SetField(rolegrant.FieldExpiredAt, field.TypeTime, value)
+		_node.ExpiredAt = &value
+	}
+	if value, ok := rgc.mutation.GrantedBy(); ok {
+		_spec.SetField(rolegrant.FieldGrantedBy, field.TypeUUID, value)
+		_node.GrantedBy = &value
+	}
+	if nodes := rgc.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   rolegrant.UserTable,
+			Columns: []string{rolegrant.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := rgc.mutation.RoleIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   rolegrant.RoleTable,
+			Columns: []string{rolegrant.RoleColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.RoleID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// RoleGrantCreateBulk is the builder for creating many RoleGrant entities in bulk.
+type RoleGrantCreateBulk struct {
+	config
+	builders []*RoleGrantCreate
+}
+
+// Save creates the RoleGrant entities in the database.
+func (rgcb *RoleGrantCreateBulk) Save(ctx context.Context) ([]*RoleGrant, error) {
+	specs := make([]*sqlgraph.CreateSpec, len(rgcb.builders))
+	nodes := make([]*RoleGrant, len(rgcb.builders))
+	mutators := make([]Mutator, len(rgcb.builders))
+	for i := range rgcb.builders {
+		func(i int, root context.Context) {
+			builder := rgcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*RoleGrantMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, rgcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, rgcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, rgcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rgcb *RoleGrantCreateBulk) SaveX(ctx context.Context) []*RoleGrant {
+	v, err := rgcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (rgcb *RoleGrantCreateBulk) Exec(ctx context.Context) error {
+	_, err := rgcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rgcb *RoleGrantCreateBulk) ExecX(ctx context.Context) {
+	if err := rgcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
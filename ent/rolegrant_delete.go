@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/Encedeus/panel/ent/predicate"
+	"github.com/Encedeus/panel/ent/rolegrant"
+)
+
+// RoleGrantDelete is the builder for deleting a RoleGrant entity.
+type RoleGrantDelete struct {
+	config
+	hooks    []Hook
+	mutation *RoleGrantMutation
+}
+
+// Where appends a list predicates to the RoleGrantDelete builder.
+func (rgd *RoleGrantDelete) Where(ps ...predicate.RoleGrant) *RoleGrantDelete {
+	rgd.mutation.Where(ps...)
+	return rgd
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (rgd *RoleGrantDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, rgd.sqlExec, rgd.mutation, rgd.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rgd *RoleGrantDelete) ExecX(ctx context.Context) int {
+	n, err := rgd.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (rgd *RoleGrantDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(rolegrant.Table, sqlgraph.NewFieldSpec(rolegrant.FieldID, field.TypeUUID))
+	if ps := rgd.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, rgd.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	rgd.mutation.done = true
+	return affected, err
+}
+
+// RoleGrantDeleteOne is the builder for deleting a single RoleGrant entity.
+type RoleGrantDeleteOne struct {
+	rgd *RoleGrantDelete
+}
+
+// Where appends a list predicates to the RoleGrantDelete builder.
+func (rgdo *RoleGrantDeleteOne) Where(ps ...predicate.RoleGrant) *RoleGrantDeleteOne {
+	rgdo.rgd.mutation.Where(ps...)
+	return rgdo
+}
+
+// Exec executes the deletion query.
+func (rgdo *RoleGrantDeleteOne) Exec(ctx context.Context) error {
+	n, err := rgdo.rgd.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{rolegrant.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rgdo *RoleGrantDeleteOne) ExecX(ctx context.Context) {
+	if err := rgdo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,581 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/Encedeus/panel/ent/predicate"
+	"github.com/Encedeus/panel/ent/role"
+	"github.com/Encedeus/panel/ent/rolegrant"
+	"github.com/Encedeus/panel/ent/user"
+	"github.com/google/uuid"
+)
+
+// RoleGrantUpdate is the builder for updating RoleGrant entities.
+type RoleGrantUpdate struct {
+	config
+	hooks    []Hook
+	mutation *RoleGrantMutation
+}
+
+// Where appends a list predicates to the RoleGrantUpdate builder.
+func (rgu *RoleGrantUpdate) Where(ps ...predicate.RoleGrant) *RoleGrantUpdate {
+	rgu.mutation.Where(ps...)
+	return rgu
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (rgu *RoleGrantUpdate) SetCreatedAt(t time.Time) *RoleGrantUpdate {
+	rgu.mutation.SetCreatedAt(t)
+	return rgu
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (rgu *RoleGrantUpdate) SetNillableCreatedAt(t *time.Time) *RoleGrantUpdate {
+	if t != nil {
+		rgu.SetCreatedAt(*t)
+	}
+	return rgu
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (rgu *RoleGrantUpdate) SetExpiresAt(t time.Time) *RoleGrantUpdate {
+	rgu.mutation.SetExpiresAt(t)
+	return rgu
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (rgu *RoleGrantUpdate) SetNillableExpiresAt(t *time.Time) *RoleGrantUpdate {
+	if t != nil {
+		rgu.SetExpiresAt(*t)
+	}
+	return rgu
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (rgu *RoleGrantUpdate) ClearExpiresAt() *RoleGrantUpdate {
+	rgu.mutation.ClearExpiresAt()
+	return rgu
+}
+
+// SetExpiredAt sets the "expired_at" field.
+func (rgu *RoleGrantUpdate) SetExpiredAt(t time.Time) *RoleGrantUpdate {
+	rgu.mutation.SetExpiredAt(t)
+	return rgu
+}
+
+// SetNillableExpiredAt sets the "expired_at" field if the given value is not nil.
+func (rgu *RoleGrantUpdate) SetNillableExpiredAt(t *time.Time) *RoleGrantUpdate {
+	if t != nil {
+		rgu.SetExpiredAt(*t)
+	}
+	return rgu
+}
+
+// ClearExpiredAt clears the value of the "expired_at" field.
+func (rgu *RoleGrantUpdate) ClearExpiredAt() *RoleGrantUpdate {
+	rgu.mutation.ClearExpiredAt()
+	return rgu
+}
+
+// SetGrantedBy sets the "granted_by" field.
+func (rgu *RoleGrantUpdate) SetGrantedBy(u uuid.UUID) *RoleGrantUpdate {
+	rgu.mutation.SetGrantedBy(u)
+	return rgu
+}
+
+// SetNillableGrantedBy sets the "granted_by" field if the given value is not nil.
+func (rgu *RoleGrantUpdate) SetNillableGrantedBy(u *uuid.UUID) *RoleGrantUpdate {
+	if u != nil {
+		rgu.SetGrantedBy(*u)
+	}
+	return rgu
+}
+
+// ClearGrantedBy clears the value of the "granted_by" field.
+func (rgu *RoleGrantUpdate) ClearGrantedBy() *RoleGrantUpdate {
+	rgu.mutation.ClearGrantedBy()
+	return rgu
+}
+
+// SetUserID sets the "user_id" field.
+func (rgu *RoleGrantUpdate) SetUserID(u uuid.UUID) *RoleGrantUpdate {
+	rgu.mutation.SetUserID(u)
+	return rgu
+}
+
+// SetRoleID sets the "role_id" field.
+func (rgu *RoleGrantUpdate) SetRoleID(u uuid.UUID) *RoleGrantUpdate {
+	rgu.mutation.SetRoleID(u)
+	return rgu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (rgu *RoleGrantUpdate) SetUser(u *User) *RoleGrantUpdate {
+	return rgu.SetUserID(u.ID)
+}
+
+// SetRole sets the "role" edge to the Role entity.
+func (rgu *RoleGrantUpdate) SetRole(r *Role) *RoleGrantUpdate {
+	return rgu.SetRoleID(r.ID)
+}
+
+// Mutation returns the RoleGrantMutation object of the builder.
+func (rgu *RoleGrantUpdate) Mutation() *RoleGrantMutation {
+	return rgu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (rgu *RoleGrantUpdate) ClearUser() *RoleGrantUpdate {
+	rgu.mutation.ClearUser()
+	return rgu
+}
+
+// ClearRole clears the "role" edge to the Role entity.
+func (rgu *RoleGrantUpdate) ClearRole() *RoleGrantUpdate {
+	rgu.mutation.ClearRole()
+	return rgu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (rgu *RoleGrantUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, rgu.sqlSave, rgu.mutation, rgu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rgu *RoleGrantUpdate) SaveX(ctx context.Context) int {
+	affected, err := rgu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (rgu *RoleGrantUpdate) Exec(ctx context.Context) error {
+	_, err := rgu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rgu *RoleGrantUpdate) ExecX(ctx context.Context) {
+	if err := rgu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rgu *RoleGrantUpdate) check() error {
+	if _, ok := rgu.mutation.UserID(); rgu.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "RoleGrant.user"`)
+	}
+	if _, ok := rgu.mutation.RoleID(); rgu.mutation.RoleCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "RoleGrant.role"`)
+	}
+	return nil
+}
+
+func (rgu *RoleGrantUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := rgu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(rolegrant.Table, rolegrant.Columns, sqlgraph.NewFieldSpec(rolegrant.FieldID, field.TypeUUID))
+	if ps := rgu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := rgu.mutation.CreatedAt(); ok {
+		_spec.SetField(rolegrant.FieldCreatedAt, field.TypeTime, value)
+	}
+	if value, ok := rgu.mutation.ExpiresAt(); ok {
+		_spec.SetField(rolegrant.FieldExpiresAt, field.TypeTime, value)
+	}
+	if rgu.mutation.ExpiresAtCleared() {
+		_spec.ClearField(rolegrant.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := rgu.mutation.ExpiredAt(); ok {
+		_spec.SetField(rolegrant.FieldExpiredAt, field.TypeTime, value)
+	}
+	if rgu.mutation.ExpiredAtCleared() {
+		_spec.ClearField(rolegrant.FieldExpiredAt, field.TypeTime)
+	}
+	if value, ok := rgu.mutation.GrantedBy(); ok {
+		_spec.SetField(rolegrant.FieldGrantedBy, field.TypeUUID, value)
+	}
+	if rgu.mutation.GrantedByCleared() {
+		_spec.ClearField(rolegrant.FieldGrantedBy, field.TypeUUID)
+	}
+	if rgu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   rolegrant.UserTable,
+			Columns: []string{rolegrant.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rgu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   rolegrant.UserTable,
+			Columns: []string{rolegrant.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if rgu.mutation.RoleCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   rolegrant.RoleTable,
+			Columns: []string{rolegrant.RoleColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rgu.mutation.RoleIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   rolegrant.RoleTable,
+			Columns: []string{rolegrant.RoleColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, rgu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{rolegrant.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	rgu.mutation.done = true
+	return n, nil
+}
+
+// RoleGrantUpdateOne is the builder for updating a single RoleGrant entity.
+type RoleGrantUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *RoleGrantMutation
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (rguo *RoleGrantUpdateOne) SetCreatedAt(t time.Time) *RoleGrantUpdateOne {
+	rguo.mutation.SetCreatedAt(t)
+	return rguo
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (rguo *RoleGrantUpdateOne) SetNillableCreatedAt(t *time.Time) *RoleGrantUpdateOne {
+	if t != nil {
+		rguo.SetCreatedAt(*t)
+	}
+	return rguo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (rguo *RoleGrantUpdateOne) SetExpiresAt(t time.Time) *RoleGrantUpdateOne {
+	rguo.mutation.SetExpiresAt(t)
+	return rguo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (rguo *RoleGrantUpdateOne) SetNillableExpiresAt(t *time.Time) *RoleGrantUpdateOne {
+	if t != nil {
+		rguo.SetExpiresAt(*t)
+	}
+	return rguo
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (rguo *RoleGrantUpdateOne) ClearExpiresAt() *RoleGrantUpdateOne {
+	rguo.mutation.ClearExpiresAt()
+	return rguo
+}
+
+// SetExpiredAt sets the "expired_at" field.
+func (rguo *RoleGrantUpdateOne) SetExpiredAt(t time.Time) *RoleGrantUpdateOne {
+	rguo.mutation.SetExpiredAt(t)
+	return rguo
+}
+
+// SetNillableExpiredAt sets the "expired_at" field if the given value is not nil.
+func (rguo *RoleGrantUpdateOne) SetNillableExpiredAt(t *time.Time) *RoleGrantUpdateOne {
+	if t != nil {
+		rguo.SetExpiredAt(*t)
+	}
+	return rguo
+}
+
+// ClearExpiredAt clears the value of the "expired_at" field.
+func (rguo *RoleGrantUpdateOne) ClearExpiredAt() *RoleGrantUpdateOne {
+	rguo.mutation.ClearExpiredAt()
+	return rguo
+}
+
+// SetGrantedBy sets the "granted_by" field.
+func (rguo *RoleGrantUpdateOne) SetGrantedBy(u uuid.UUID) *RoleGrantUpdateOne {
+	rguo.mutation.SetGrantedBy(u)
+	return rguo
+}
+
+// SetNillableGrantedBy sets the "granted_by" field if the given value is not nil.
+func (rguo *RoleGrantUpdateOne) SetNillableGrantedBy(u *uuid.UUID) *RoleGrantUpdateOne {
+	if u != nil {
+		rguo.SetGrantedBy(*u)
+	}
+	return rguo
+}
+
+// ClearGrantedBy clears the value of the "granted_by" field.
+func (rguo *RoleGrantUpdateOne) ClearGrantedBy() *RoleGrantUpdateOne {
+	rguo.mutation.ClearGrantedBy()
+	return rguo
+}
+
+// SetUserID sets the "user_id" field.
+func (rguo *RoleGrantUpdateOne) SetUserID(u uuid.UUID) *RoleGrantUpdateOne {
+	rguo.mutation.SetUserID(u)
+	return rguo
+}
+
+// SetRoleID sets the "role_id" field.
+func (rguo *RoleGrantUpdateOne) SetRoleID(u uuid.UUID) *RoleGrantUpdateOne {
+	rguo.mutation.SetRoleID(u)
+	return rguo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (rguo *RoleGrantUpdateOne) SetUser(u *User) *RoleGrantUpdateOne {
+	return rguo.SetUserID(u.ID)
+}
+
+// SetRole sets the "role" edge to the Role entity.
+func (rguo *RoleGrantUpdateOne) SetRole(r *Role) *RoleGrantUpdateOne {
+	return rguo.SetRoleID(r.ID)
+}
+
+// Mutation returns the RoleGrantMutation object of the builder.
+func (rguo *RoleGrantUpdateOne) Mutation() *RoleGrantMutation {
+	return rguo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (rguo *RoleGrantUpdateOne) ClearUser() *RoleGrantUpdateOne {
+	rguo.mutation.ClearUser()
+	return rguo
+}
+
+// ClearRole clears the "role" edge to the Role entity.
+func (rguo *RoleGrantUpdateOne) ClearRole() *RoleGrantUpdateOne {
+	rguo.mutation.ClearRole()
+	return rguo
+}
+
+// Where appends a list predicates to the RoleGrantUpdate builder.
+func (rguo *RoleGrantUpdateOne) Where(ps ...predicate.RoleGrant) *RoleGrantUpdateOne {
+	rguo.mutation.Where(ps...)
+	return rguo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (rguo *RoleGrantUpdateOne) Select(field string, fields ...string) *RoleGrantUpdateOne {
+	rguo.fields = append([]string{field}, fields...)
+	return rguo
+}
+
+// Save executes the query and returns the updated RoleGrant entity.
+func (rguo *RoleGrantUpdateOne) Save(ctx context.Context) (*RoleGrant, error) {
+	return withHooks(ctx, rguo.sqlSave, rguo.mutation, rguo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rguo *RoleGrantUpdateOne) SaveX(ctx context.Context) *RoleGrant {
+	node, err := rguo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (rguo *RoleGrantUpdateOne) Exec(ctx context.Context) error {
+	_, err := rguo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rguo *RoleGrantUpdateOne) ExecX(ctx context.Context) {
+	if err := rguo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rguo *RoleGrantUpdateOne) check() error {
+	if _, ok := rguo.mutation.UserID(); rguo.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "RoleGrant.user"`)
+	}
+	if _, ok := rguo.mutation.RoleID(); rguo.mutation.RoleCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "RoleGrant.role"`)
+	}
+	return nil
+}
+
+func (rguo *RoleGrantUpdateOne) sqlSave(ctx context.Context) (_node *RoleGrant, err error) {
+	if err := rguo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(rolegrant.Table, rolegrant.Columns, sqlgraph.NewFieldSpec(rolegrant.FieldID, field.TypeUUID))
+	id, ok := rguo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "RoleGrant.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := rguo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, rolegrant.FieldID)
+		for _, f := range fields {
+			if !rolegrant.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != rolegrant.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := rguo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := rguo.mutation.CreatedAt(); ok {
+		_spec.SetField(rolegrant.FieldCreatedAt, field.TypeTime, value)
+	}
+	if value, ok := rguo.mutation.ExpiresAt(); ok {
+		_spec.SetField(rolegrant.FieldExpiresAt, field.TypeTime, value)
+	}
+	if rguo.mutation.ExpiresAtCleared() {
+		_spec.ClearField(rolegrant.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := rguo.mutation.ExpiredAt(); ok {
+		_spec.SetField(rolegrant.FieldExpiredAt, field.TypeTime, value)
+	}
+	if rguo.mutation.ExpiredAtCleared() {
+		_spec.ClearField(rolegrant.FieldExpiredAt, field.TypeTime)
+	}
+	if value, ok := rguo.mutation.GrantedBy(); ok {
+		_spec.SetField(rolegrant.FieldGrantedBy, field.TypeUUID, value)
+	}
+	if rguo.mutation.GrantedByCleared() {
+		_spec.ClearField(rolegrant.FieldGrantedBy, field.TypeUUID)
+	}
+	if rguo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   rolegrant.UserTable,
+			Columns: []string{rolegrant.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rguo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   rolegrant.UserTable,
+			Columns: []string{rolegrant.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if rguo.mutation.RoleCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   rolegrant.RoleTable,
+			Columns: []string{rolegrant.RoleColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rguo.mutation.RoleIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   rolegrant.RoleTable,
+			Columns: []string{rolegrant.RoleColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &RoleGrant{config: rguo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, rguo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{rolegrant.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	rguo.mutation.done = true
+	return _node, nil
+}
@@ -13,6 +13,13 @@ type ApiKey struct {
     ent.Schema
 }
 
+// Mixin of the ApiKey.
+func (ApiKey) Mixin() []ent.Mixin {
+    return []ent.Mixin{
+        VersionMixin{},
+    }
+}
+
 // Fields of the ApiKey.
 func (ApiKey) Fields() []ent.Field {
     return []ent.Field{
@@ -0,0 +1,39 @@
+package schema
+
+import (
+    "entgo.io/ent"
+    "entgo.io/ent/schema/field"
+    "entgo.io/ent/schema/index"
+    "github.com/google/uuid"
+    "time"
+)
+
+// OutboxEvent holds the schema definition for the OutboxEvent entity.
+// Events are written to the outbox in the transaction publishing them and deleted once every subscriber got them,
+// so the events of a panel that stopped before dispatching them are dispatched by the relay.
+type OutboxEvent struct {
+    ent.Schema
+}
+
+// Fields of the OutboxEvent.
+func (OutboxEvent) Fields() []ent.Field {
+    return []ent.Field{
+        // the id of the event
+        field.UUID("id", uuid.UUID{}),
+        field.Time("created_at").Default(time.Now),
+        field.String("type"),
+        // the event's data encoded with protojson
+        field.Text("payload"),
+        // the relay leaves the event alone until then, the panel publishing it dispatches it in the meantime
+        field.Time("claimed_until"),
+        // how many times the event was claimed, claims only succeed if it didn't change in between
+        field.Int("claims").Default(0),
+    }
+}
+
+// Indexes of the OutboxEvent.
+func (OutboxEvent) Indexes() []ent.Index {
+    return []ent.Index{
+        index.Fields("claimed_until"),
+    }
+}
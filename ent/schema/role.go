@@ -2,7 +2,10 @@ package schema
 
 import (
     "entgo.io/ent"
+    "entgo.io/ent/dialect/entsql"
+    "entgo.io/ent/schema/edge"
     "entgo.io/ent/schema/field"
+    "entgo.io/ent/schema/index"
     "github.com/google/uuid"
     "time"
 )
@@ -12,19 +15,40 @@ type Role struct {
     ent.Schema
 }
 
+// Mixin of the Role.
+func (Role) Mixin() []ent.Mixin {
+    return []ent.Mixin{
+        SoftDeleteMixin{},
+        VersionMixin{},
+    }
+}
+
 // Fields of the Role.
 func (Role) Fields() []ent.Field {
     return []ent.Field{
         field.UUID("id", uuid.UUID{}).Default(uuid.New),
         field.Time("created_at").Default(time.Now),
         field.Time("updated_at").UpdateDefault(time.Now).Default(time.Now),
-        field.Time("deleted_at").Optional(),
-        field.String("name").MaxLen(24).Unique(),
+        field.String("name").MaxLen(24),
         field.Strings("permissions").Optional(),
     }
 }
 
 // Edges of the Role.
 func (Role) Edges() []ent.Edge {
-    return nil
+    return []ent.Edge{
+        // a role inherits the permissions of all of its ancestors
+        edge.To("children", Role.Type).
+            From("parents"),
+    }
+}
+
+// Indexes of the Role.
+func (Role) Indexes() []ent.Index {
+    return []ent.Index{
+        // role names only have to be unique among roles that aren't soft-deleted
+        index.Fields("name").
+            Unique().
+            Annotations(entsql.IndexWhere("deleted_at IS NULL")),
+    }
 }
@@ -0,0 +1,46 @@
+package schema
+
+import (
+    "entgo.io/ent"
+    "entgo.io/ent/schema/edge"
+    "entgo.io/ent/schema/field"
+    "entgo.io/ent/schema/index"
+    "github.com/google/uuid"
+    "time"
+)
+
+// RoleGrant holds the schema definition for the RoleGrant entity.
+// A grant gives a user an additional role on top of its primary one, optionally until it expires.
+type RoleGrant struct {
+    ent.Schema
+}
+
+// Fields of the RoleGrant.
+func (RoleGrant) Fields() []ent.Field {
+    return []ent.Field{
+        field.UUID("id", uuid.UUID{}).Default(uuid.New),
+        field.Time("created_at").Default(time.Now),
+        field.Time("expires_at").Optional().Nillable(),
+        // set by the expiry job once the grant has expired
+        field.Time("expired_at").Optional().Nillable(),
+        field.UUID("granted_by", uuid.UUID{}).Optional().Nillable(),
+        field.UUID("user_id", uuid.UUID{}),
+        field.UUID("role_id", uuid.UUID{}),
+    }
+}
+
+// Edges of the RoleGrant.
+func (RoleGrant) Edges() []ent.Edge {
+    return []ent.Edge{
+        edge.To("user", User.Type).Field("user_id").Required().Unique(),
+        edge.To("role", Role.Type).Field("role_id").Required().Unique(),
+    }
+}
+
+// Indexes of the RoleGrant.
+func (RoleGrant) Indexes() []ent.Index {
+    return []ent.Index{
+        index.Fields("user_id"),
+        index.Fields("expires_at"),
+    }
+}
@@ -0,0 +1,93 @@
+package schema
+
+import (
+    "context"
+    "entgo.io/ent"
+    "entgo.io/ent/dialect/sql"
+    "entgo.io/ent/schema/field"
+    "entgo.io/ent/schema/mixin"
+    "fmt"
+    gen "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/ent/hook"
+    "github.com/Encedeus/panel/ent/intercept"
+    "time"
+)
+
+// SoftDeleteMixin adds a deleted_at field, filters soft-deleted rows out of every query
+// and turns deletes into updates of deleted_at.
+type SoftDeleteMixin struct {
+    mixin.Schema
+}
+
+// Fields of the SoftDeleteMixin.
+func (SoftDeleteMixin) Fields() []ent.Field {
+    return []ent.Field{
+        field.Time("deleted_at").Optional(),
+    }
+}
+
+type softDeleteKey struct{}
+
+// SkipSoftDelete returns a new context that skips the soft-delete interceptor and hook,
+// so soft-deleted rows can be queried, restored or purged.
+func SkipSoftDelete(parent context.Context) context.Context {
+    return context.WithValue(parent, softDeleteKey{}, true)
+}
+
+func isSoftDeleteSkipped(ctx context.Context) bool {
+    skip, _ := ctx.Value(softDeleteKey{}).(bool)
+    return skip
+}
+
+// Interceptors of the SoftDeleteMixin.
+func (d SoftDeleteMixin) Interceptors() []ent.Interceptor {
+    return []ent.Interceptor{
+        intercept.TraverseFunc(func(ctx context.Context, q intercept.Query) error {
+            if isSoftDeleteSkipped(ctx) {
+                return nil
+            }
+            d.P(q)
+
+            return nil
+        }),
+    }
+}
+
+// Hooks of the SoftDeleteMixin.
+func (d SoftDeleteMixin) Hooks() []ent.Hook {
+    return []ent.Hook{
+        hook.On(
+            func(next ent.Mutator) ent.Mutator {
+                return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+                    if isSoftDeleteSkipped(ctx) {
+                        return next.Mutate(ctx, m)
+                    }
+
+                    mx, ok := m.(interface {
+                        SetOp(ent.Op)
+                        Client() *gen.Client
+                        SetDeletedAt(time.Time)
+                        WhereP(...func(*sql.Selector))
+                    })
+                    if !ok {
+                        return nil, fmt.Errorf("unexpected mutation type %T", m)
+                    }
+
+                    d.P(mx)
+                    mx.SetOp(ent.OpUpdate)
+                    mx.SetDeletedAt(time.Now())
+
+                    return mx.Client().Mutate(ctx, m)
+                })
+            },
+            ent.OpDeleteOne|ent.OpDelete,
+        ),
+    }
+}
+
+// P adds a storage-level predicate to the queries and mutations.
+func (d SoftDeleteMixin) P(w interface{ WhereP(...func(*sql.Selector)) }) {
+    w.WhereP(
+        sql.FieldIsNull(d.Fields()[0].Descriptor().Name),
+    )
+}
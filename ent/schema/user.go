@@ -2,8 +2,10 @@ package schema
 
 import (
     "entgo.io/ent"
+    "entgo.io/ent/dialect/entsql"
     "entgo.io/ent/schema/edge"
     "entgo.io/ent/schema/field"
+    "entgo.io/ent/schema/index"
     "github.com/google/uuid"
     "time"
 )
@@ -13,16 +15,23 @@ type User struct {
     ent.Schema
 }
 
+// Mixin of the User.
+func (User) Mixin() []ent.Mixin {
+    return []ent.Mixin{
+        SoftDeleteMixin{},
+        VersionMixin{},
+    }
+}
+
 // Fields of the User.
 func (User) Fields() []ent.Field {
     return []ent.Field{
         field.UUID("id", uuid.UUID{}).Default(uuid.New),
         field.Time("created_at").Default(time.Now),
         field.Time("updated_at").UpdateDefault(time.Now).Default(time.Now),
-        field.Time("deleted_at").Optional(),
         field.String("email").MaxLen(32),
         field.String("password"),
-        field.String("name").MaxLen(32).Unique(),
+        field.String("name").MaxLen(32),
         field.UUID("role_id", uuid.UUID{}),
     }
 }
@@ -32,3 +41,13 @@ func (User) Edges() []ent.Edge {
         edge.To("role", Role.Type).Field("role_id").Unique().Required(),
     }
 }
+
+// Indexes of the User.
+func (User) Indexes() []ent.Index {
+    return []ent.Index{
+        // usernames only have to be unique among users that aren't soft-deleted
+        index.Fields("name").
+            Unique().
+            Annotations(entsql.IndexWhere("deleted_at IS NULL")),
+    }
+}
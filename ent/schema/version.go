@@ -0,0 +1,45 @@
+package schema
+
+import (
+    "context"
+    "entgo.io/ent"
+    "entgo.io/ent/schema/field"
+    "entgo.io/ent/schema/mixin"
+    "fmt"
+    "github.com/Encedeus/panel/ent/hook"
+)
+
+// VersionMixin adds a version field that's incremented by every update, clients send it back in If-Match
+// so they don't overwrite changes they haven't seen.
+type VersionMixin struct {
+    mixin.Schema
+}
+
+// Fields of the VersionMixin.
+func (VersionMixin) Fields() []ent.Field {
+    return []ent.Field{
+        field.Int64("version").Default(1),
+    }
+}
+
+// Hooks of the VersionMixin.
+func (VersionMixin) Hooks() []ent.Hook {
+    return []ent.Hook{
+        hook.On(
+            func(next ent.Mutator) ent.Mutator {
+                return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+                    mx, ok := m.(interface {
+                        AddVersion(int64)
+                    })
+                    if !ok {
+                        return nil, fmt.Errorf("unexpected mutation type %T", m)
+                    }
+                    mx.AddVersion(1)
+
+                    return next.Mutate(ctx, m)
+                })
+            },
+            ent.OpUpdate|ent.OpUpdateOne,
+        ),
+    }
+}
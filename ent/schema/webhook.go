@@ -0,0 +1,39 @@
+package schema
+
+import (
+    "entgo.io/ent"
+    "entgo.io/ent/dialect/entsql"
+    "entgo.io/ent/schema/edge"
+    "entgo.io/ent/schema/field"
+    "github.com/google/uuid"
+    "time"
+)
+
+// Webhook holds the schema definition for the Webhook entity.
+// A webhook receives a signed POST request for every event of the types it's subscribed to.
+type Webhook struct {
+    ent.Schema
+}
+
+// Fields of the Webhook.
+func (Webhook) Fields() []ent.Field {
+    return []ent.Field{
+        field.UUID("id", uuid.UUID{}).Default(uuid.New),
+        field.Time("created_at").Default(time.Now),
+        field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
+        field.String("url").NotEmpty(),
+        // signs the deliveries, it has to be kept as is to compute the signatures
+        field.String("secret").NotEmpty().Sensitive(),
+        field.Strings("events"),
+        field.String("description").Optional(),
+        field.Bool("enabled").Default(true),
+    }
+}
+
+// Edges of the Webhook.
+func (Webhook) Edges() []ent.Edge {
+    return []ent.Edge{
+        edge.To("deliveries", WebhookDelivery.Type).
+            Annotations(entsql.OnDelete(entsql.Cascade)),
+    }
+}
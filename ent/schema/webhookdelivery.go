@@ -0,0 +1,52 @@
+package schema
+
+import (
+    "entgo.io/ent"
+    "entgo.io/ent/schema/edge"
+    "entgo.io/ent/schema/field"
+    "entgo.io/ent/schema/index"
+    "github.com/google/uuid"
+    "time"
+)
+
+// WebhookDelivery holds the schema definition for the WebhookDelivery entity.
+// A delivery is one event sent to one webhook, it's retried until it succeeds or runs out of attempts.
+type WebhookDelivery struct {
+    ent.Schema
+}
+
+// Fields of the WebhookDelivery.
+func (WebhookDelivery) Fields() []ent.Field {
+    return []ent.Field{
+        field.UUID("id", uuid.UUID{}).Default(uuid.New),
+        field.Time("created_at").Default(time.Now),
+        field.UUID("webhook_id", uuid.UUID{}),
+        // shared by the deliveries of the same event, including redeliveries
+        field.UUID("event_id", uuid.UUID{}),
+        field.String("event"),
+        // the request body, kept so the exact same event can be redelivered
+        field.Text("payload"),
+        field.Enum("status").Values("pending", "succeeded", "failed").Default("pending"),
+        field.Int("attempts").Default(0),
+        // when the next attempt is due, cleared once the delivery succeeded or failed
+        field.Time("next_attempt_at").Optional().Nillable(),
+        field.Int("response_status").Optional(),
+        field.String("error").Optional(),
+        field.Time("delivered_at").Optional().Nillable(),
+    }
+}
+
+// Edges of the WebhookDelivery.
+func (WebhookDelivery) Edges() []ent.Edge {
+    return []ent.Edge{
+        edge.From("webhook", Webhook.Type).Ref("deliveries").Field("webhook_id").Required().Unique(),
+    }
+}
+
+// Indexes of the WebhookDelivery.
+func (WebhookDelivery) Indexes() []ent.Index {
+    return []ent.Index{
+        index.Fields("webhook_id", "created_at"),
+        index.Fields("status", "next_attempt_at"),
+    }
+}
@@ -0,0 +1,251 @@
+// Package events is the panel's internal event bus, services publish what happened to the panel's resources
+// and subscribers, like webhooks, react to it.
+//
+// Events are dispatched once the transaction publishing them commits and go through an outbox table,
+// so subscribers get them at least once even if the panel stops in between.
+package events
+
+import (
+    "context"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/google/uuid"
+    "google.golang.org/protobuf/proto"
+    "log/slog"
+    "slices"
+    "sync"
+    "time"
+)
+
+var (
+    UserCreated  = NewTopic[*protoapi.User]("user.created")
+    UserUpdated  = NewTopic[*protoapi.User]("user.updated")
+    UserDeleted  = NewTopic[*protoapi.User]("user.deleted")
+    UserRestored = NewTopic[*protoapi.User]("user.restored")
+
+    RoleCreated  = NewTopic[*protoapi.Role]("role.created")
+    RoleUpdated  = NewTopic[*protoapi.Role]("role.updated")
+    RoleDeleted  = NewTopic[*protoapi.Role]("role.deleted")
+    RoleRestored = NewTopic[*protoapi.Role]("role.restored")
+
+    RoleGranted = NewTopic[*protoapi.RoleGrant]("role.granted")
+    RoleRevoked = NewTopic[*protoapi.RoleGrant]("role.revoked")
+
+    // nothing publishes the server events yet, the panel doesn't start or stop servers itself.
+    // They're registered so webhooks can already subscribe to them.
+    ServerStarted = NewTopic[*protoapi.Server]("server.started")
+    ServerStopped = NewTopic[*protoapi.Server]("server.stopped")
+)
+
+// Event is something that happened to one of the panel's resources
+type Event[T proto.Message] struct {
+    ID         uuid.UUID
+    Type       string
+    OccurredAt time.Time
+    // Data is the resource the event is about, as returned by the API
+    Data T
+}
+
+// AnyEvent is an event of any topic, as received by SubscribeAll
+type AnyEvent = Event[proto.Message]
+
+// Handler reacts to an event. If it returns an error the event is kept in the outbox and dispatched again,
+// to every subscriber, once its lease runs out.
+type Handler[T proto.Message] func(ctx context.Context, event Event[T]) error
+
+// Topic is an event type along with the type of its data
+type Topic[T proto.Message] struct {
+    name string
+}
+
+// NewTopic registers an event type, the name has to be unique
+func NewTopic[T proto.Message](name string) Topic[T] {
+    topicsMu.Lock()
+    defer topicsMu.Unlock()
+
+    if _, ok := topics[name]; ok {
+        panic("events: topic " + name + " registered twice")
+    }
+    topics[name] = func() proto.Message {
+        var data T
+        return data.ProtoReflect().New().Interface()
+    }
+    topicNames = append(topicNames, name)
+
+    return Topic[T]{name: name}
+}
+
+// Name is the type of the topic's events, like user.created
+func (t Topic[T]) Name() string {
+    return t.name
+}
+
+// Subscribe calls h with every event of the topic published from now on until the returned function is called
+func (t Topic[T]) Subscribe(h Handler[T], opts ...SubscribeOption) (unsubscribe func()) {
+    return subscribe(t.name, func(ctx context.Context, event Event[proto.Message]) error {
+        return h(ctx, Event[T]{
+            ID:         event.ID,
+            Type:       event.Type,
+            OccurredAt: event.OccurredAt,
+            Data:       event.Data.(T),
+        })
+    }, opts)
+}
+
+// SubscribeAll calls h with every event published from now on, whatever its topic
+func SubscribeAll(h Handler[proto.Message], opts ...SubscribeOption) (unsubscribe func()) {
+    return subscribe("", h, opts)
+}
+
+// Types lists the names of every topic in the order they were registered
+func Types() []string {
+    topicsMu.RLock()
+    defer topicsMu.RUnlock()
+
+    return append([]string{}, topicNames...)
+}
+
+// IsType checks if t is the name of a topic
+func IsType(t string) bool {
+    topicsMu.RLock()
+    defer topicsMu.RUnlock()
+
+    _, ok := topics[t]
+
+    return ok
+}
+
+var (
+    topicsMu sync.RWMutex
+    // topics holds a constructor of the data of each topic's events, used to decode them from the outbox
+    topics     = map[string]func() proto.Message{}
+    topicNames []string
+)
+
+// SubscribeOption changes how a subscriber is called
+type SubscribeOption func(s *subscriber)
+
+// Async calls the subscriber on its own goroutine instead of the one dispatching the event,
+// for anything slow that the publisher shouldn't wait for
+func Async() SubscribeOption {
+    return func(s *subscriber) {
+        s.async = true
+    }
+}
+
+type subscriber struct {
+    id int
+    // topic is empty for subscribers of every topic
+    topic  string
+    async  bool
+    handle Handler[proto.Message]
+}
+
+var (
+    subscribersMu sync.RWMutex
+    // subscribers are called in the order they subscribed
+    subscribers []*subscriber
+    nextId      int
+    // inFlight counts the events still being handled by async subscribers
+    inFlight sync.WaitGroup
+)
+
+func subscribe(topic string, h Handler[proto.Message], opts []SubscribeOption) func() {
+    s := &subscriber{
+        topic:  topic,
+        handle: h,
+    }
+    for _, opt := range opts {
+        opt(s)
+    }
+
+    subscribersMu.Lock()
+    defer subscribersMu.Unlock()
+
+    s.id = nextId
+    nextId++
+    subscribers = append(subscribers, s)
+
+    return func() {
+        subscribersMu.Lock()
+        defer subscribersMu.Unlock()
+
+        subscribers = slices.DeleteFunc(subscribers, func(other *subscriber) bool {
+            return other.id == s.id
+        })
+    }
+}
+
+// dispatch calls the synchronous subscribers of the event right away and the async ones on a new goroutine,
+// calling done once all of them were called with whether every one of them handled it
+func dispatch(ctx context.Context, event Event[proto.Message], done func(handled bool)) {
+    var inline, async []*subscriber
+    subscribersMu.RLock()
+    for _, s := range subscribers {
+        if s.topic != "" && s.topic != event.Type {
+            continue
+        }
+
+        if s.async {
+            async = append(async, s)
+        } else {
+            inline = append(inline, s)
+        }
+    }
+    subscribersMu.RUnlock()
+
+    handled := true
+    for _, s := range inline {
+        handled = handle(ctx, s, event) && handled
+    }
+
+    if len(async) == 0 {
+        done(handled)
+        return
+    }
+
+    inFlight.Add(1)
+    go func() {
+        defer inFlight.Done()
+
+        // the publisher's request may be over by now
+        ctx := context.WithoutCancel(ctx)
+        for _, s := range async {
+            handled = handle(ctx, s, event) && handled
+        }
+        done(handled)
+    }()
+}
+
+// handle calls the subscriber with the event, returning false if it failed or panicked
+func handle(ctx context.Context, s *subscriber, event Event[proto.Message]) (handled bool) {
+    defer func() {
+        if r := recover(); r != nil {
+            slog.ErrorContext(ctx, "event subscriber panicked", "event", event.Type, "id", event.ID, "panic", r)
+            handled = false
+        }
+    }()
+
+    if err := s.handle(ctx, event); err != nil {
+        slog.ErrorContext(ctx, "error handling event", "event", event.Type, "id", event.ID, "error", err)
+        return false
+    }
+
+    return true
+}
+
+// Drain waits until the async subscribers handled the events dispatched so far or ctx is done,
+// events they didn't get to are dispatched again by the relay
+func Drain(ctx context.Context) error {
+    drained := make(chan struct{})
+    go func() {
+        inFlight.Wait()
+        close(drained)
+    }()
+
+    select {
+    case <-drained:
+        return nil
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
@@ -0,0 +1,221 @@
+package events
+
+import (
+    "context"
+    "fmt"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/ent/outboxevent"
+    "github.com/Encedeus/panel/tracing"
+    "github.com/google/uuid"
+    "google.golang.org/protobuf/encoding/protojson"
+    "google.golang.org/protobuf/proto"
+    "log/slog"
+    "sync"
+    "time"
+)
+
+const (
+    // outboxLease is how long an event is left to the panel dispatching it before the relay dispatches it again
+    outboxLease = 5 * time.Minute
+    // relayBatchSize is the most events dispatched on each run of the relay
+    relayBatchSize = 100
+)
+
+// DefaultRelayInterval is how often the relay looks for events that weren't dispatched
+const DefaultRelayInterval = time.Minute
+
+// Publish writes an event of the topic about data to the outbox and dispatches it.
+// If ctx holds a transaction, see ent.NewTxContext, the event is written in it and only dispatched once it commits,
+// nothing is dispatched if it's rolled back. db must not be bound to the transaction, it removes the event
+// from the outbox after it was dispatched.
+func (t Topic[T]) Publish(ctx context.Context, db *ent.Client, data T) error {
+    return publish(ctx, db, Event[proto.Message]{
+        ID:         uuid.New(),
+        Type:       t.name,
+        OccurredAt: time.Now(),
+        Data:       data,
+    })
+}
+
+func publish(ctx context.Context, db *ent.Client, event Event[proto.Message]) error {
+    payload, err := protojson.Marshal(event.Data)
+    if err != nil {
+        return err
+    }
+
+    tx := ent.TxFromContext(ctx)
+    client := db
+    if tx != nil {
+        client = tx.Client()
+    }
+
+    err = client.OutboxEvent.Create().
+        SetID(event.ID).
+        SetCreatedAt(event.OccurredAt).
+        SetType(event.Type).
+        SetPayload(string(payload)).
+        SetClaimedUntil(time.Now().Add(outboxLease)).
+        Exec(ctx)
+    if err != nil {
+        return err
+    }
+
+    if tx == nil {
+        dispatchOutboxEvent(ctx, db, event)
+        return nil
+    }
+
+    afterCommit(tx, func() {
+        dispatchOutboxEvent(ctx, db, event)
+    })
+
+    return nil
+}
+
+var (
+    pendingMu sync.Mutex
+    // pending holds the dispatches waiting for each transaction to commit, in the order they were published
+    pending = map[*ent.Tx][]func(){}
+)
+
+// afterCommit calls fn once tx committed, the functions of the same transaction are called in order
+func afterCommit(tx *ent.Tx, fn func()) {
+    pendingMu.Lock()
+    defer pendingMu.Unlock()
+
+    fns, ok := pending[tx]
+    pending[tx] = append(fns, fn)
+    if ok {
+        return
+    }
+
+    // the hooks are only registered with the first event of the transaction
+    tx.OnCommit(func(next ent.Committer) ent.Committer {
+        return ent.CommitFunc(func(ctx context.Context, tx *ent.Tx) error {
+            err := next.Commit(ctx, tx)
+            for _, fn := range takePending(tx) {
+                if err == nil {
+                    fn()
+                }
+            }
+
+            return err
+        })
+    })
+    tx.OnRollback(func(next ent.Rollbacker) ent.Rollbacker {
+        return ent.RollbackFunc(func(ctx context.Context, tx *ent.Tx) error {
+            takePending(tx)
+            return next.Rollback(ctx, tx)
+        })
+    })
+}
+
+func takePending(tx *ent.Tx) []func() {
+    pendingMu.Lock()
+    defer pendingMu.Unlock()
+
+    fns := pending[tx]
+    delete(pending, tx)
+
+    return fns
+}
+
+// dispatchOutboxEvent removes the event from the outbox once every subscriber handled it. If one of them failed
+// it's left for the relay, which dispatches it again once its lease runs out.
+func dispatchOutboxEvent(ctx context.Context, db *ent.Client, event Event[proto.Message]) {
+    dispatch(ctx, event, func(handled bool) {
+        if !handled {
+            return
+        }
+
+        ctx := context.WithoutCancel(ctx)
+        if err := db.OutboxEvent.DeleteOneID(event.ID).Exec(ctx); err != nil && !ent.IsNotFound(err) {
+            slog.ErrorContext(ctx, "error removing event from the outbox", "event", event.Type, "id", event.ID, "error", err)
+        }
+    })
+}
+
+// RelayOutbox dispatches the events whose lease ran out, they were left behind by a panel that stopped
+// before its subscribers handled them
+func RelayOutbox(ctx context.Context, db *ent.Client, now time.Time) (int, error) {
+    rows, err := db.OutboxEvent.Query().
+        Where(outboxevent.ClaimedUntilLTE(now)).
+        Order(outboxevent.ByCreatedAt()).
+        Limit(relayBatchSize).
+        All(ctx)
+    if err != nil {
+        return 0, err
+    }
+
+    relayed := 0
+    for _, row := range rows {
+        // claimed so only one panel relays it when several share the database
+        claimed, err := db.OutboxEvent.Update().
+            Where(outboxevent.IDEQ(row.ID), outboxevent.ClaimsEQ(row.Claims)).
+            AddClaims(1).
+            SetClaimedUntil(now.Add(outboxLease)).
+            Save(ctx)
+        if err != nil {
+            return relayed, err
+        }
+        if claimed == 0 {
+            continue
+        }
+
+        event, err := decodeOutboxEvent(row)
+        if err != nil {
+            // kept for inspection, it's claimed again once the lease runs out
+            slog.ErrorContext(ctx, "error decoding event from the outbox", "event", row.Type, "id", row.ID, "error", err)
+            continue
+        }
+
+        dispatchOutboxEvent(ctx, db, event)
+        relayed++
+    }
+
+    return relayed, nil
+}
+
+func decodeOutboxEvent(row *ent.OutboxEvent) (Event[proto.Message], error) {
+    topicsMu.RLock()
+    newData, ok := topics[row.Type]
+    topicsMu.RUnlock()
+    if !ok {
+        return Event[proto.Message]{}, fmt.Errorf("unknown topic %s", row.Type)
+    }
+
+    data := newData()
+    if err := protojson.Unmarshal([]byte(row.Payload), data); err != nil {
+        return Event[proto.Message]{}, err
+    }
+
+    return Event[proto.Message]{
+        ID:         row.ID,
+        Type:       row.Type,
+        OccurredAt: row.CreatedAt,
+        Data:       data,
+    }, nil
+}
+
+// RunOutboxRelay relays the events left in the outbox every interval until ctx is done
+func RunOutboxRelay(ctx context.Context, db *ent.Client, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        runCtx, span := tracing.Tracer().Start(ctx, "relay outbox events")
+        relayed, err := RelayOutbox(runCtx, db, time.Now())
+        if err != nil {
+            slog.ErrorContext(runCtx, "error relaying outbox events", "error", err)
+        } else if relayed != 0 {
+            slog.InfoContext(runCtx, "relayed outbox events", "count", relayed)
+        }
+        span.End()
+
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
@@ -0,0 +1,188 @@
+package events
+
+import (
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/ent"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/testdb"
+    "github.com/google/uuid"
+    "sync"
+    "testing"
+    "time"
+)
+
+// collect subscribes to topic until the test ends, returning the names of the users of the events received so far
+func collect(t *testing.T, topic Topic[*protoapi.User], opts ...SubscribeOption) func() []string {
+    t.Helper()
+
+    var mu sync.Mutex
+    var names []string
+    t.Cleanup(topic.Subscribe(func(ctx context.Context, event Event[*protoapi.User]) error {
+        mu.Lock()
+        defer mu.Unlock()
+
+        names = append(names, event.Data.Name)
+        return nil
+    }, opts...))
+
+    return func() []string {
+        mu.Lock()
+        defer mu.Unlock()
+
+        return append([]string{}, names...)
+    }
+}
+
+func outboxCount(t *testing.T, db *ent.Client) int {
+    t.Helper()
+
+    n, err := db.OutboxEvent.Query().Count(context.Background())
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return n
+}
+
+func TestPublishDispatchesAfterCommit(t *testing.T) {
+    db := testdb.NewClient(t)
+    received := collect(t, UserCreated)
+
+    tests := []struct {
+        name   string
+        commit bool
+        want   int
+    }{
+        {"committed", true, 1},
+        {"rolled back", false, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            before := len(received())
+
+            tx, err := db.Tx(context.Background())
+            if err != nil {
+                t.Fatal(err)
+            }
+            ctx := ent.NewTxContext(context.Background(), tx)
+            if err = UserCreated.Publish(ctx, db, &protoapi.User{Name: tt.name}); err != nil {
+                t.Fatal(err)
+            }
+            if len(received()) != before {
+                t.Fatal("event was dispatched before the transaction ended")
+            }
+
+            if tt.commit {
+                err = tx.Commit()
+            } else {
+                err = tx.Rollback()
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            if got := len(received()) - before; got != tt.want {
+                t.Errorf("dispatched %d events, want %d", got, tt.want)
+            }
+            if n := outboxCount(t, db); n != 0 {
+                t.Errorf("%d events left in the outbox", n)
+            }
+        })
+    }
+}
+
+func TestAsyncSubscribersKeepEventsInOutboxUntilHandled(t *testing.T) {
+    db := testdb.NewClient(t)
+
+    release := make(chan struct{})
+    t.Cleanup(UserUpdated.Subscribe(func(ctx context.Context, event Event[*protoapi.User]) error {
+        <-release
+        return nil
+    }, Async()))
+
+    if err := UserUpdated.Publish(context.Background(), db, &protoapi.User{Name: "slow"}); err != nil {
+        t.Fatal(err)
+    }
+    if n := outboxCount(t, db); n != 1 {
+        t.Errorf("%d events in the outbox while the subscriber is busy, want 1", n)
+    }
+
+    close(release)
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    if err := Drain(ctx); err != nil {
+        t.Fatal(err)
+    }
+    if n := outboxCount(t, db); n != 0 {
+        t.Errorf("%d events left in the outbox after draining", n)
+    }
+}
+
+func TestRelayOutbox(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+    received := collect(t, UserDeleted)
+
+    // left behind by a panel that stopped before dispatching it
+    now := time.Now()
+    err := db.OutboxEvent.Create().
+        SetID(uuid.New()).
+        SetCreatedAt(now.Add(-time.Hour)).
+        SetType(UserDeleted.Name()).
+        SetPayload(`{"name":"left behind"}`).
+        SetClaimedUntil(now.Add(time.Minute)).
+        Exec(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if relayed, err := RelayOutbox(ctx, db, now); err != nil || relayed != 0 {
+        t.Errorf("relayed %d events (%v) before the lease ran out, want 0", relayed, err)
+    }
+
+    relayed, err := RelayOutbox(ctx, db, now.Add(2*time.Minute))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := received(); relayed != 1 || len(got) != 1 || got[0] != "left behind" {
+        t.Errorf("relayed %d events, received %v", relayed, got)
+    }
+    if n := outboxCount(t, db); n != 0 {
+        t.Errorf("%d events left in the outbox after relaying", n)
+    }
+}
+
+func TestFailedSubscriberKeepsEventInOutbox(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+    received := collect(t, UserRestored)
+
+    failures := 1
+    t.Cleanup(UserRestored.Subscribe(func(ctx context.Context, event Event[*protoapi.User]) error {
+        if failures == 0 {
+            return nil
+        }
+        failures--
+        return errors.New("webhook is down")
+    }))
+
+    if err := UserRestored.Publish(ctx, db, &protoapi.User{Name: "retried"}); err != nil {
+        t.Fatal(err)
+    }
+    if n := outboxCount(t, db); n != 1 {
+        t.Fatalf("%d events in the outbox after a subscriber failed, want 1", n)
+    }
+
+    relayed, err := RelayOutbox(ctx, db, time.Now().Add(outboxLease))
+    if err != nil {
+        t.Fatal(err)
+    }
+    // the subscriber that handled it the first time gets it again too
+    if got := received(); relayed != 1 || len(got) != 2 {
+        t.Errorf("relayed %d events, received %v", relayed, got)
+    }
+    if n := outboxCount(t, db); n != 0 {
+        t.Errorf("%d events left in the outbox once every subscriber handled it", n)
+    }
+}
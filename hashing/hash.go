@@ -19,3 +19,10 @@ func VerifyHash(unhashed string, hash string) bool {
 
 	return err == nil
 }
+
+// IsHash checks if hash is a bcrypt hash, like the ones HashPassword returns
+func IsHash(hash string) bool {
+	_, err := bcrypt.Cost([]byte(hash))
+
+	return err == nil
+}
@@ -0,0 +1,108 @@
+// Package health runs the checks deciding whether the panel is ready to serve requests
+package health
+
+import (
+    "context"
+    "errors"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "sync"
+    "time"
+)
+
+// ErrDisabled is returned by checks of features that aren't enabled, they don't make the panel unready
+var ErrDisabled = errors.New("disabled")
+
+type disabledError string
+
+func (e disabledError) Error() string {
+    return string(e)
+}
+
+func (e disabledError) Is(target error) bool {
+    return target == ErrDisabled
+}
+
+// Disabled returns an error matching ErrDisabled with reason as its message
+func Disabled(reason string) error {
+    return disabledError(reason)
+}
+
+const (
+    StatusOK          = "ok"
+    StatusFailed      = "failed"
+    StatusDisabled    = "disabled"
+    StatusUnavailable = "unavailable"
+)
+
+// checkTimeout bounds every check so a hanging dependency can't stall the probe
+const checkTimeout = 2 * time.Second
+
+type check struct {
+    name string
+    run  func(ctx context.Context) error
+}
+
+// Checker runs readiness checks, the panel is ready if none of them fails
+type Checker struct {
+    checks []check
+}
+
+func NewChecker() *Checker {
+    return &Checker{}
+}
+
+// Add registers a check, run returns nil if the dependency is usable, or an error wrapping ErrDisabled if it's turned off
+func (c *Checker) Add(name string, run func(ctx context.Context) error) {
+    c.checks = append(c.checks, check{name, run})
+}
+
+// Run runs every check at once and reports their results in the order they were added
+func (c *Checker) Run(ctx context.Context) *protoapi.ReadinessResponse {
+    results := make([]*protoapi.ReadinessCheck, len(c.checks))
+
+    var wg sync.WaitGroup
+    for i, chk := range c.checks {
+        wg.Add(1)
+        go func(i int, chk check) {
+            defer wg.Done()
+            results[i] = runCheck(ctx, chk)
+        }(i, chk)
+    }
+    wg.Wait()
+
+    resp := &protoapi.ReadinessResponse{
+        Status: StatusOK,
+        Checks: results,
+    }
+    for _, result := range results {
+        if result.Status == StatusFailed {
+            resp.Status = StatusUnavailable
+        }
+    }
+
+    return resp
+}
+
+func runCheck(ctx context.Context, chk check) *protoapi.ReadinessCheck {
+    ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+    defer cancel()
+
+    start := time.Now()
+    err := chk.run(ctx)
+    result := &protoapi.ReadinessCheck{
+        Name:       chk.name,
+        Status:     StatusOK,
+        DurationMs: time.Since(start).Milliseconds(),
+    }
+
+    switch {
+    case errors.Is(err, ErrDisabled):
+        result.Status = StatusDisabled
+        result.Message = err.Error()
+    case err != nil:
+        result.Status = StatusFailed
+        result.Message = err.Error()
+    }
+
+    return result
+}
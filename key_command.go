@@ -0,0 +1,37 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "github.com/google/uuid"
+    "log"
+)
+
+// runKey handles `panel key revoke`
+func runKey(args []string) {
+    if len(args) != 2 || args[0] != "revoke" {
+        log.Fatalln("usage: panel key revoke <key id>")
+    }
+    if _, err := uuid.Parse(args[1]); err != nil {
+        log.Fatalf("%q is not a valid key id", args[1])
+    }
+
+    db := config.InitDB()
+    defer db.Close()
+
+    _, err := services.DeleteAccountAPIKey(context.Background(), db, &protoapi.AccountAPIKeyDeleteRequest{
+        Id: &protoapi.UUID{Value: args[1]},
+    })
+    if ent.IsNotFound(err) {
+        log.Fatalf("API key %s not found", args[1])
+    }
+    if err != nil {
+        log.Fatalf("failed revoking API key: %v", err)
+    }
+
+    fmt.Printf("revoked API key %s\n", args[1])
+}
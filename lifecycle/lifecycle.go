@@ -0,0 +1,153 @@
+// Package lifecycle starts the panel's servers and background workers and stops them in order on shutdown
+package lifecycle
+
+import (
+    "context"
+    "errors"
+    "log/slog"
+    "os"
+    "os/signal"
+    "sync"
+    "syscall"
+    "time"
+)
+
+type server struct {
+    name     string
+    start    func() error
+    shutdown func(ctx context.Context) error
+}
+
+type worker struct {
+    name string
+    run  func(ctx context.Context)
+}
+
+type closer struct {
+    name  string
+    close func() error
+}
+
+// Manager runs servers and workers until SIGINT, SIGTERM or a server failing, then drains them
+// for at most the drain timeout and closes the registered resources in reverse order
+type Manager struct {
+    drainTimeout time.Duration
+    servers      []server
+    workers      []worker
+    closers      []closer
+}
+
+func New(drainTimeout time.Duration) *Manager {
+    return &Manager{
+        drainTimeout: drainTimeout,
+    }
+}
+
+// Serve registers a server, start must block until the server stops and shutdown must stop it gracefully
+func (m *Manager) Serve(name string, start func() error, shutdown func(ctx context.Context) error) {
+    m.servers = append(m.servers, server{name, start, shutdown})
+}
+
+// Go registers a background worker, run must return once its context is done
+func (m *Manager) Go(name string, run func(ctx context.Context)) {
+    m.workers = append(m.workers, worker{name, run})
+}
+
+// OnClose registers a resource closed after every server and worker stopped,
+// resources are closed in the reverse order they were registered in
+func (m *Manager) OnClose(name string, close func() error) {
+    m.closers = append(m.closers, closer{name, close})
+}
+
+// Run starts everything and blocks until shutdown is complete, returning the first server error if any
+func (m *Manager) Run(ctx context.Context) error {
+    ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+    defer stop()
+
+    workerCtx, cancelWorkers := context.WithCancel(context.Background())
+    defer cancelWorkers()
+
+    var workers sync.WaitGroup
+    for _, w := range m.workers {
+        workers.Add(1)
+        go func(w worker) {
+            defer workers.Done()
+            w.run(workerCtx)
+        }(w)
+    }
+
+    serverErrs := make(chan error, len(m.servers))
+    for _, s := range m.servers {
+        go func(s server) {
+            err := s.start()
+            if err != nil {
+                slog.Error("server failed", "server", s.name, "error", err)
+            }
+            serverErrs <- err
+        }(s)
+    }
+
+    var err error
+    select {
+    case <-ctx.Done():
+        slog.Info("received shutdown signal, draining", "timeout", m.drainTimeout)
+    case err = <-serverErrs:
+        slog.Info("server stopped, shutting down")
+    }
+    // a second signal kills the process right away
+    stop()
+
+    drainCtx, cancelDrain := context.WithTimeout(context.Background(), m.drainTimeout)
+    defer cancelDrain()
+
+    m.shutdownServers(drainCtx)
+
+    cancelWorkers()
+    if !waitFor(drainCtx, &workers) {
+        slog.Warn("background workers didn't stop within the drain timeout", "timeout", m.drainTimeout)
+    }
+
+    m.closeResources()
+
+    return err
+}
+
+// shutdownServers stops every server at once, waiting for their in-flight requests until ctx is done
+func (m *Manager) shutdownServers(ctx context.Context) {
+    var wg sync.WaitGroup
+    for _, s := range m.servers {
+        wg.Add(1)
+        go func(s server) {
+            defer wg.Done()
+            if err := s.shutdown(ctx); err != nil && !errors.Is(err, context.Canceled) {
+                slog.Error("failed shutting down server gracefully", "server", s.name, "error", err)
+            }
+        }(s)
+    }
+    wg.Wait()
+}
+
+func (m *Manager) closeResources() {
+    for i := len(m.closers) - 1; i >= 0; i-- {
+        c := m.closers[i]
+        if err := c.close(); err != nil {
+            slog.Error("failed closing resource", "resource", c.name, "error", err)
+        }
+    }
+}
+
+// waitFor waits for wg until ctx is done, returning whether everything finished
+func waitFor(ctx context.Context, wg *sync.WaitGroup) bool {
+    done := make(chan struct{})
+    go func() {
+        wg.Wait()
+        close(done)
+    }()
+
+    select {
+    case <-done:
+        return true
+    case <-ctx.Done():
+        return false
+    }
+}
@@ -0,0 +1,117 @@
+package lifecycle
+
+import (
+    "context"
+    "errors"
+    "net"
+    "net/http"
+    "reflect"
+    "testing"
+    "time"
+)
+
+func TestRunDrainsInFlightRequests(t *testing.T) {
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    started := make(chan struct{})
+    srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        close(started)
+        time.Sleep(100 * time.Millisecond)
+        w.WriteHeader(http.StatusOK)
+    })}
+
+    var order []string
+    m := New(5 * time.Second)
+    m.Serve("http", func() error {
+        if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+            return err
+        }
+        return nil
+    }, srv.Shutdown)
+    m.Go("worker", func(ctx context.Context) {
+        <-ctx.Done()
+        order = append(order, "worker")
+    })
+    m.OnClose("db", func() error {
+        order = append(order, "db")
+        return nil
+    })
+    m.OnClose("cache", func() error {
+        order = append(order, "cache")
+        return nil
+    })
+
+    ctx, cancel := context.WithCancel(context.Background())
+    ran := make(chan error, 1)
+    go func() {
+        ran <- m.Run(ctx)
+    }()
+
+    status := make(chan int, 1)
+    go func() {
+        resp, err := http.Get("http://" + listener.Addr().String())
+        if err != nil {
+            status <- 0
+            return
+        }
+        _ = resp.Body.Close()
+        status <- resp.StatusCode
+    }()
+
+    <-started
+    cancel()
+
+    if got := <-status; got != http.StatusOK {
+        t.Errorf("in-flight request got %d, want it drained with %d", got, http.StatusOK)
+    }
+    if err = <-ran; err != nil {
+        t.Errorf("run: %v", err)
+    }
+    if want := []string{"worker", "cache", "db"}; !reflect.DeepEqual(order, want) {
+        t.Errorf("stopped in order %v, want %v", order, want)
+    }
+}
+
+func TestRunReturnsServerError(t *testing.T) {
+    failed := errors.New("address already in use")
+    closed := false
+
+    m := New(time.Second)
+    m.Serve("http", func() error {
+        return failed
+    }, func(ctx context.Context) error {
+        return nil
+    })
+    m.OnClose("db", func() error {
+        closed = true
+        return nil
+    })
+
+    if err := m.Run(context.Background()); !errors.Is(err, failed) {
+        t.Errorf("got %v, want %v", err, failed)
+    }
+    if !closed {
+        t.Error("resources weren't closed after the server failed")
+    }
+}
+
+func TestRunGivesUpOnWorkersAfterDrainTimeout(t *testing.T) {
+    m := New(50 * time.Millisecond)
+    m.Go("stuck", func(ctx context.Context) {
+        time.Sleep(time.Second)
+    })
+
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+
+    start := time.Now()
+    if err := m.Run(ctx); err != nil {
+        t.Fatal(err)
+    }
+    if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+        t.Errorf("waited %s for a stuck worker, want about the drain timeout", elapsed)
+    }
+}
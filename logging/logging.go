@@ -0,0 +1,138 @@
+// Package logging configures the panel's slog logger and carries request scoped attributes,
+// like the request ID, through contexts so every line logged with one includes them
+package logging
+
+import (
+    "context"
+    "fmt"
+    "go.opentelemetry.io/otel/trace"
+    "io"
+    "log/slog"
+    "os"
+)
+
+// LevelOff is above every level used, so setting it silences the logger
+const LevelOff = slog.Level(1 << 30)
+
+var level slog.LevelVar
+
+type contextKey int
+
+const (
+    attrsKey contextKey = iota
+    requestIDKey
+)
+
+// Setup makes slog's default logger, and with it the standard library's, write to output in the given format,
+// output is stdout, stderr or a file appended to. The returned function closes the output.
+func Setup(lvl string, format string, output string) (func() error, error) {
+    w, closeOutput, err := openOutput(output)
+    if err != nil {
+        return nil, err
+    }
+
+    SetLevel(lvl)
+    opts := &slog.HandlerOptions{
+        Level: &level,
+    }
+
+    var handler slog.Handler
+    if format == "json" {
+        handler = slog.NewJSONHandler(w, opts)
+    } else {
+        handler = slog.NewTextHandler(w, opts)
+    }
+    slog.SetDefault(slog.New(contextHandler{handler}))
+
+    return closeOutput, nil
+}
+
+func openOutput(output string) (io.Writer, func() error, error) {
+    noop := func() error { return nil }
+
+    switch output {
+    case "", "stdout":
+        return os.Stdout, noop, nil
+    case "stderr":
+        return os.Stderr, noop, nil
+    }
+
+    f, err := os.OpenFile(output, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+    if err != nil {
+        return nil, nil, fmt.Errorf("failed opening log file: %w", err)
+    }
+
+    return f, f.Close, nil
+}
+
+// SetLevel changes the level of the logger set up by Setup, it can be called at any time
+func SetLevel(lvl string) {
+    level.Set(ParseLevel(lvl))
+}
+
+// ParseLevel converts debug, info, warn, error or off into a slog level, anything else is info
+func ParseLevel(lvl string) slog.Level {
+    switch lvl {
+    case "debug":
+        return slog.LevelDebug
+    case "warn":
+        return slog.LevelWarn
+    case "error":
+        return slog.LevelError
+    case "off":
+        return LevelOff
+    default:
+        return slog.LevelInfo
+    }
+}
+
+// With returns a copy of ctx whose log lines include attrs
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+    prev, _ := ctx.Value(attrsKey).([]slog.Attr)
+
+    merged := make([]slog.Attr, 0, len(prev)+len(attrs))
+    merged = append(merged, prev...)
+    merged = append(merged, attrs...)
+
+    return context.WithValue(ctx, attrsKey, merged)
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID, which is included in its log lines
+func WithRequestID(ctx context.Context, id string) context.Context {
+    ctx = context.WithValue(ctx, requestIDKey, id)
+
+    return With(ctx, slog.String("request_id", id))
+}
+
+// RequestID returns the request ID stored in ctx, it's empty outside of requests
+func RequestID(ctx context.Context) string {
+    id, _ := ctx.Value(requestIDKey).(string)
+
+    return id
+}
+
+// contextHandler adds the attributes stored in the context, and the ID of the trace it's part of, to every record
+type contextHandler struct {
+    slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+    if ctx != nil {
+        if attrs, ok := ctx.Value(attrsKey).([]slog.Attr); ok {
+            r.AddAttrs(attrs...)
+        }
+        if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+            r.AddAttrs(slog.String("trace_id", span.TraceID().String()))
+        }
+    }
+
+    return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+    return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+    "bufio"
+    "context"
+    "encoding/json"
+    "log/slog"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+// setupTestLog makes the default logger write JSON to a file until the test ends, returning a function reading its lines
+func setupTestLog(t *testing.T, lvl string) func() []map[string]any {
+    t.Helper()
+
+    previous := slog.Default()
+    path := filepath.Join(t.TempDir(), "panel.log")
+    closeOutput, err := Setup(lvl, "json", path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() {
+        slog.SetDefault(previous)
+        _ = closeOutput()
+    })
+
+    return func() []map[string]any {
+        f, err := os.Open(path)
+        if err != nil {
+            t.Fatal(err)
+        }
+        defer f.Close()
+
+        var lines []map[string]any
+        scanner := bufio.NewScanner(f)
+        for scanner.Scan() {
+            var line map[string]any
+            if err = json.Unmarshal(scanner.Bytes(), &line); err != nil {
+                t.Fatalf("log line isn't JSON: %s", scanner.Text())
+            }
+            lines = append(lines, line)
+        }
+
+        return lines
+    }
+}
+
+func TestContextAttributes(t *testing.T) {
+    lines := setupTestLog(t, "info")
+
+    ctx := WithRequestID(context.Background(), "req-1")
+    ctx = With(ctx, slog.String("user_id", "someone"))
+    slog.InfoContext(ctx, "handled")
+    slog.Info("outside of a request")
+
+    got := lines()
+    if len(got) != 2 {
+        t.Fatalf("logged %d lines, want 2", len(got))
+    }
+    if got[0]["request_id"] != "req-1" || got[0]["user_id"] != "someone" {
+        t.Errorf("line logged with the context is missing its attributes: %v", got[0])
+    }
+    if _, ok := got[1]["request_id"]; ok {
+        t.Errorf("line logged without a context has a request ID: %v", got[1])
+    }
+    if id := RequestID(ctx); id != "req-1" {
+        t.Errorf("request ID is %q, want req-1", id)
+    }
+}
+
+func TestSetLevel(t *testing.T) {
+    lines := setupTestLog(t, "warn")
+
+    slog.Info("hidden")
+    slog.Warn("shown")
+    SetLevel("off")
+    slog.Error("silenced")
+    SetLevel("debug")
+    slog.Debug("shown after lowering the level")
+
+    got := lines()
+    if len(got) != 2 || got[0]["msg"] != "shown" || got[1]["msg"] != "shown after lowering the level" {
+        t.Errorf("got %v", got)
+    }
+}
@@ -1,13 +1,206 @@
 package main
 
 import (
+    "context"
+    "database/sql"
+    "flag"
+    "fmt"
     "github.com/Encedeus/panel/config"
     "github.com/Encedeus/panel/controllers"
+    _ "github.com/Encedeus/panel/ent/runtime"
+    "github.com/Encedeus/panel/events"
+    "github.com/Encedeus/panel/health"
+    "github.com/Encedeus/panel/lifecycle"
+    "github.com/Encedeus/panel/logging"
+    "github.com/Encedeus/panel/module"
+    "github.com/Encedeus/panel/services"
+    "github.com/Encedeus/panel/tracing"
+    "log"
+    "os"
+    "time"
 )
 
+const usage = `usage: panel [--config path] <command> [arguments]
+
+commands:
+  serve                                   start the panel, the default command
+  migrate up|down [steps]|status          apply, roll back or list database migrations
+  migrate up --baseline                   adopt a database created before the panel had migrations, then migrate it
+  user create <name> <email> <role>       create a user, the password is read from stdin unless --password is set
+  user reset-password <user>              set a new password, read from stdin unless --password is set
+  user list                               list every user
+  user import <file>                      create users from a JSON or CSV file, see --dry-run and --best-effort
+  user export [file]                      write every user with their password hash as JSON or CSV
+  role grant <user> <role> [--expires d]  give a user an additional role, optionally for a duration
+  role import <file>                      create roles from a JSON or CSV file, see --dry-run and --best-effort
+  role export [file]                      write every role as JSON or CSV
+  key revoke <key id>                     delete an API key
+  config check                            validate the config file and report every problem
+  setup token                             generate a new token for the first-run setup at POST /setup
+
+users and roles can be given by name or id
+`
+
 func main() {
-    config.InitConfig()
-    db := config.InitDB()
-    // go module.Init()
-    controllers.StartDefaultServer(db)
+    configPath := flag.String("config", "", "path to the config file, defaults to $"+config.EnvConfigPath+" or ./config.hcl")
+    flag.Usage = func() {
+        _, _ = fmt.Fprint(flag.CommandLine.Output(), usage)
+        flag.PrintDefaults()
+    }
+    flag.Parse()
+
+    path := config.ResolvePath(*configPath)
+    command, args := "serve", []string{}
+    if flag.NArg() > 0 {
+        command, args = flag.Arg(0), flag.Args()[1:]
+    }
+
+    // config check reports problems instead of exiting on the first invalid config
+    if command == "config" {
+        runConfig(path, args)
+        return
+    }
+
+    config.InitConfig(path)
+
+    switch command {
+    case "serve":
+        serve(path)
+    case "migrate":
+        runMigrate(args)
+    case "user":
+        runUser(args)
+    case "role":
+        runRole(args)
+    case "key":
+        runKey(args)
+    case "setup":
+        runSetup(args)
+    default:
+        flag.Usage()
+        os.Exit(2)
+    }
+}
+
+// serve starts the panel along with its background jobs and stops them gracefully on SIGINT or SIGTERM
+func serve(path string) {
+    app := lifecycle.New(config.Config.Server.DrainTimeoutPeriod())
+
+    logCfg := config.Config.Log
+    closeLog, err := logging.Setup(logCfg.Level, logCfg.Format, logCfg.Output)
+    if err != nil {
+        log.Fatalf("failed setting up logging: %v", err)
+    }
+    // registered first so it's closed last, after everything else logged its shutdown
+    app.OnClose("log output", closeLog)
+    config.OnReload(func(cfg *config.Configuration) {
+        logging.SetLevel(cfg.Log.Level)
+    })
+
+    tracingCfg := config.Config.Tracing
+    shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+        ServiceName: tracingCfg.ServiceName,
+        Exporter:    tracingCfg.Exporter,
+        Endpoint:    tracingCfg.Endpoint,
+        Protocol:    tracingCfg.Protocol,
+        Insecure:    tracingCfg.Insecure,
+        SampleRatio: tracingCfg.SampleRatio,
+    })
+    if err != nil {
+        log.Fatalf("failed setting up tracing: %v", err)
+    }
+    app.OnClose("tracing", func() error {
+        // flushes the spans of the last requests
+        ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+
+        return shutdownTracing(ctx)
+    })
+
+    db, sqlDB := config.InitDBWithPool()
+    app.OnClose("database", db.Close)
+    services.SubscribeWebhooks(db)
+    // closed before the database, the async subscribers may still write to it
+    app.OnClose("event subscribers", func() error {
+        ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+        defer cancel()
+
+        return events.Drain(ctx)
+    })
+    logSetupToken(db)
+
+    var plugins *module.Host
+    if dir := config.Config.Plugins.Directory; dir != "" {
+        plugins = module.NewHost(dir)
+    }
+
+    srv := controllers.NewDefaultServer(db)
+    srv.Readiness = newReadinessChecker(sqlDB, plugins)
+    if err := controllers.ConfigureTLS(srv); err != nil {
+        log.Fatalf("failed configuring TLS: %v", err)
+    }
+    app.Serve("http", func() error {
+        return controllers.StartServer(srv)
+    }, srv.Shutdown)
+    if redirect := controllers.NewRedirectServer(srv); redirect != nil {
+        app.Serve("http redirect", func() error {
+            return controllers.StartHTTPServer(redirect)
+        }, redirect.Shutdown)
+    }
+    if metricsSrv := controllers.NewMetricsServer(); metricsSrv != nil {
+        app.Serve("metrics", func() error {
+            return controllers.StartHTTPServer(metricsSrv)
+        }, metricsSrv.Shutdown)
+    }
+
+    app.Go("config watcher", func(ctx context.Context) {
+        config.WatchConfig(ctx, path, 5*time.Second)
+    })
+    app.Go("soft delete purger", func(ctx context.Context) {
+        services.RunSoftDeletePurger(ctx, db, config.Config.DB.PurgeIntervalPeriod(), func() time.Duration {
+            return config.Current().DB.SoftDeleteRetentionPeriod()
+        })
+    })
+    app.Go("role grant expirer", func(ctx context.Context) {
+        services.RunRoleGrantExpirer(ctx, db, config.Config.Auth.RoleGrantExpiryIntervalPeriod())
+    })
+    app.Go("webhook deliverer", func(ctx context.Context) {
+        services.RunWebhookDeliverer(ctx, db, config.Config.Webhooks.DeliveryIntervalPeriod())
+    })
+    app.Go("outbox relay", func(ctx context.Context) {
+        events.RunOutboxRelay(ctx, db, events.DefaultRelayInterval)
+    })
+    if plugins != nil {
+        app.Go("plugins", plugins.Run)
+    }
+
+    if err := app.Run(context.Background()); err != nil {
+        log.Fatalf("panel stopped: %v", err)
+    }
+}
+
+// newReadinessChecker checks everything the panel needs to serve requests, plugins is nil if the plugin host is disabled
+func newReadinessChecker(sqlDB *sql.DB, plugins *module.Host) *health.Checker {
+    checker := health.NewChecker()
+    checker.Add("database", sqlDB.PingContext)
+
+    migrator, err := config.NewMigrator(sqlDB, config.Config.DB.Driver)
+    if err != nil {
+        log.Fatalf("failed reading migrations: %v", err)
+    }
+    // the schema can fall behind if a newer panel migrated the database while this one keeps running
+    checker.Add("migrations", migrator.Check)
+
+    if plugins != nil {
+        checker.Add("plugins", plugins.Check)
+    } else {
+        checker.Add("plugins", func(context.Context) error {
+            return health.Disabled("no plugin directory is configured")
+        })
+    }
+    checker.Add("nodes", func(context.Context) error {
+        return health.Disabled("no nodes are configured")
+    })
+
+    return checker
 }
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+    "context"
+    "database/sql"
+    "entgo.io/ent/dialect"
+    "fmt"
+    "time"
+)
+
+// Driver is an ent driver recording the duration of every statement run through it, transactions included
+type Driver struct {
+    dialect.Driver
+}
+
+// NewDriver wraps drv so its statements are timed
+func NewDriver(drv dialect.Driver) *Driver {
+    return &Driver{drv}
+}
+
+func (d *Driver) Exec(ctx context.Context, query string, args, v any) error {
+    return observe("exec", func() error {
+        return d.Driver.Exec(ctx, query, args, v)
+    })
+}
+
+func (d *Driver) Query(ctx context.Context, query string, args, v any) error {
+    return observe("query", func() error {
+        return d.Driver.Query(ctx, query, args, v)
+    })
+}
+
+func (d *Driver) Tx(ctx context.Context) (dialect.Tx, error) {
+    var tx dialect.Tx
+    err := observe("begin", func() (err error) {
+        tx, err = d.Driver.Tx(ctx)
+        return err
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &Tx{tx}, nil
+}
+
+// BeginTx starts a transaction with options, it's required by ent's Client.BeginTx
+func (d *Driver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+    drv, ok := d.Driver.(interface {
+        BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+    })
+    if !ok {
+        return nil, fmt.Errorf("driver.BeginTx is not supported")
+    }
+
+    var tx dialect.Tx
+    err := observe("begin", func() (err error) {
+        tx, err = drv.BeginTx(ctx, opts)
+        return err
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &Tx{tx}, nil
+}
+
+// Tx is a transaction started by Driver, its statements are timed too
+type Tx struct {
+    dialect.Tx
+}
+
+func (t *Tx) Exec(ctx context.Context, query string, args, v any) error {
+    return observe("exec", func() error {
+        return t.Tx.Exec(ctx, query, args, v)
+    })
+}
+
+func (t *Tx) Query(ctx context.Context, query string, args, v any) error {
+    return observe("query", func() error {
+        return t.Tx.Query(ctx, query, args, v)
+    })
+}
+
+func (t *Tx) Commit() error {
+    return observe("commit", t.Tx.Commit)
+}
+
+func (t *Tx) Rollback() error {
+    return observe("rollback", t.Tx.Rollback)
+}
+
+func observe(operation string, fn func() error) error {
+    start := time.Now()
+    err := fn()
+    DBQueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+    if err != nil {
+        DBQueryErrors.WithLabelValues(operation).Inc()
+    }
+
+    return err
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+    "context"
+    "database/sql"
+    "entgo.io/ent/dialect"
+    entsql "entgo.io/ent/dialect/sql"
+    _ "modernc.org/sqlite"
+    "testing"
+)
+
+// sampleCount returns the number of observations of the histogram name labeled with operation
+func sampleCount(t *testing.T, name string, operation string) uint64 {
+    t.Helper()
+
+    families, err := Registry.Gather()
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, family := range families {
+        if family.GetName() != name {
+            continue
+        }
+        for _, metric := range family.GetMetric() {
+            for _, label := range metric.GetLabel() {
+                if label.GetName() == "operation" && label.GetValue() == operation {
+                    return metric.GetHistogram().GetSampleCount()
+                }
+            }
+        }
+    }
+
+    return 0
+}
+
+func TestDriverTimesStatements(t *testing.T) {
+    ctx := context.Background()
+
+    db, err := sql.Open("sqlite", "file:metrics?mode=memory")
+    if err != nil {
+        t.Fatal(err)
+    }
+    drv := NewDriver(entsql.OpenDB(dialect.SQLite, db))
+    t.Cleanup(func() {
+        _ = drv.Close()
+    })
+
+    before := map[string]uint64{}
+    for _, operation := range []string{"exec", "query", "begin", "commit"} {
+        before[operation] = sampleCount(t, "panel_db_query_duration_seconds", operation)
+    }
+
+    var res sql.Result
+    if err = drv.Exec(ctx, "CREATE TABLE t (id INTEGER)", []any{}, &res); err != nil {
+        t.Fatal(err)
+    }
+    tx, err := drv.Tx(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err = tx.Exec(ctx, "INSERT INTO t (id) VALUES (1)", []any{}, &res); err != nil {
+        t.Fatal(err)
+    }
+    var rows entsql.Rows
+    if err = tx.Query(ctx, "SELECT id FROM t", []any{}, &rows); err != nil {
+        t.Fatal(err)
+    }
+    _ = rows.Close()
+    if err = tx.Commit(); err != nil {
+        t.Fatal(err)
+    }
+
+    want := map[string]uint64{"exec": 2, "query": 1, "begin": 1, "commit": 1}
+    for operation, n := range want {
+        if got := sampleCount(t, "panel_db_query_duration_seconds", operation) - before[operation]; got != n {
+            t.Errorf("timed %d %s statements, want %d", got, operation, n)
+        }
+    }
+}
@@ -0,0 +1,136 @@
+// Package metrics holds the panel's Prometheus metrics and the handler exposing them
+package metrics
+
+import (
+    "crypto/subtle"
+    "encoding/json"
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/collectors"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+    "net/http"
+    "strings"
+    "sync"
+)
+
+const namespace = "panel"
+
+// Registry holds every metric of the panel along with the Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+    HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "http_requests_total",
+        Help:      "HTTP requests handled, by route and status code.",
+    }, []string{"method", "route", "status"})
+
+    HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Name:      "http_request_duration_seconds",
+        Help:      "Time taken to handle HTTP requests, by route.",
+        Buckets:   prometheus.DefBuckets,
+    }, []string{"method", "route"})
+
+    // AuthAttempts is labeled with the credential used, password, access_token, api_key or refresh_token,
+    // and the result, success or failure
+    AuthAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "auth_attempts_total",
+        Help:      "Authentication attempts, by credential and result.",
+    }, []string{"method", "result"})
+
+    // TokensIssued is labeled with the token type, access, refresh or api_key
+    TokensIssued = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "tokens_issued_total",
+        Help:      "Tokens issued, by type.",
+    }, []string{"type"})
+
+    DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Name:      "db_query_duration_seconds",
+        Help:      "Time taken by database statements, by operation.",
+        Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+    }, []string{"operation"})
+
+    DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "db_query_errors_total",
+        Help:      "Database statements that failed, by operation.",
+    }, []string{"operation"})
+
+    PluginVMsRunning = prometheus.NewGauge(prometheus.GaugeOpts{
+        Namespace: namespace,
+        Name:      "plugin_vms_running",
+        Help:      "Plugin VMs currently running.",
+    })
+
+    PluginVMsStarted = prometheus.NewCounter(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "plugin_vms_started_total",
+        Help:      "Plugin VMs started since the panel started.",
+    })
+
+    // WebhookDeliveryAttempts is labeled with the result, succeeded, retrying or failed
+    WebhookDeliveryAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "webhook_delivery_attempts_total",
+        Help:      "Attempts at delivering events to webhooks, by result.",
+    }, []string{"result"})
+)
+
+func init() {
+    Registry.MustRegister(
+        collectors.NewGoCollector(),
+        collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+        HTTPRequests,
+        HTTPRequestDuration,
+        AuthAttempts,
+        TokensIssued,
+        DBQueryDuration,
+        DBQueryErrors,
+        WebhookDeliveryAttempts,
+    )
+}
+
+var registerPluginVMs sync.Once
+
+// RegisterPluginVMs exposes PluginVMsRunning and PluginVMsStarted, it's called once the plugin host starts
+// so a panel without plugins doesn't report them
+func RegisterPluginVMs() {
+    registerPluginVMs.Do(func() {
+        Registry.MustRegister(PluginVMsRunning, PluginVMsStarted)
+    })
+}
+
+// AuthAttempt counts an authentication attempt with the given credential
+func AuthAttempt(method string, success bool) {
+    result := "failure"
+    if success {
+        result = "success"
+    }
+
+    AuthAttempts.WithLabelValues(method, result).Inc()
+}
+
+// Handler serves the metrics in the Prometheus exposition format, if token returns a non-empty token
+// requests must send it as a bearer token
+func Handler(token func() string) http.Handler {
+    metricsHandler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if expected := token(); expected != "" {
+            given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+            if subtle.ConstantTimeCompare([]byte(given), []byte(expected)) != 1 {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusUnauthorized)
+                _ = json.NewEncoder(w).Encode(map[string]string{
+                    "message": "unauthorised",
+                })
+                return
+            }
+        }
+
+        metricsHandler.ServeHTTP(w, r)
+    })
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestHandlerRequiresToken(t *testing.T) {
+    token := "scrape-token"
+    handler := Handler(func() string {
+        return token
+    })
+
+    tests := []struct {
+        name          string
+        authorization string
+        want          int
+    }{
+        {"no token", "", http.StatusUnauthorized},
+        {"wrong token", "Bearer wrong", http.StatusUnauthorized},
+        {"token", "Bearer scrape-token", http.StatusOK},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+            if tt.authorization != "" {
+                req.Header.Set("Authorization", tt.authorization)
+            }
+            rec := httptest.NewRecorder()
+            handler.ServeHTTP(rec, req)
+
+            if rec.Code != tt.want {
+                t.Errorf("got %d, want %d", rec.Code, tt.want)
+            }
+        })
+    }
+
+    // an empty token leaves the metrics open
+    token = ""
+    AuthAttempt("password", false)
+    rec := httptest.NewRecorder()
+    handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+    if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `panel_auth_attempts_total{method="password",result="failure"}`) {
+        t.Errorf("got %d without a token configured:\n%s", rec.Code, rec.Body)
+    }
+}
+
+func TestPluginVMsRegisteredWithPluginHost(t *testing.T) {
+    handler := Handler(func() string {
+        return ""
+    })
+    scrape := func() string {
+        rec := httptest.NewRecorder()
+        handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+        return rec.Body.String()
+    }
+
+    if body := scrape(); strings.Contains(body, "panel_plugin_vms_running") {
+        t.Error("plugin VMs are reported without a plugin host")
+    }
+
+    RegisterPluginVMs()
+    RegisterPluginVMs()
+    if body := scrape(); !strings.Contains(body, "panel_plugin_vms_running 0") || !strings.Contains(body, "panel_plugin_vms_started_total 0") {
+        t.Errorf("plugin VMs aren't reported once the plugin host started:\n%s", body)
+    }
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+    "github.com/Encedeus/panel/logging"
+    "github.com/labstack/echo/v4"
+    "github.com/labstack/echo/v4/middleware"
+    "log/slog"
+    "net/http"
+    "time"
+)
+
+// RequestIDMiddleware assigns every request an ID, reusing the X-Request-Id header if the client sent one.
+// The ID is returned in the same header and stored in the request context so every log line of the request includes it.
+func RequestIDMiddleware() echo.MiddlewareFunc {
+    return middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+        RequestIDHandler: func(c echo.Context, id string) {
+            c.SetRequest(c.Request().WithContext(logging.WithRequestID(c.Request().Context(), id)))
+        },
+    })
+}
+
+// AccessLogMiddleware logs every request once it's handled, along with its status, latency and authenticated user
+func AccessLogMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+    return func(c echo.Context) error {
+        start := time.Now()
+
+        // handled here so the status written by the error handler is logged
+        if err := next(c); err != nil {
+            c.Error(err)
+        }
+
+        req := c.Request()
+        res := c.Response()
+        attrs := []any{
+            slog.String("method", req.Method),
+            slog.String("route", c.Path()),
+            slog.String("uri", req.RequestURI),
+            slog.Int("status", res.Status),
+            slog.Duration("latency", time.Since(start)),
+            slog.Int64("bytes", res.Size),
+            slog.String("ip", c.RealIP()),
+        }
+
+        level := slog.LevelInfo
+        if res.Status >= http.StatusInternalServerError {
+            level = slog.LevelError
+        }
+        // the request context adds the request ID, and the user ID if the request was authenticated
+        slog.Log(req.Context(), level, "request", attrs...)
+
+        return nil
+    }
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+    "encoding/json"
+    "github.com/Encedeus/panel/logging"
+    "github.com/labstack/echo/v4"
+    "log/slog"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestAccessLogIncludesRequestID(t *testing.T) {
+    previous := slog.Default()
+    path := filepath.Join(t.TempDir(), "access.log")
+    closeOutput, err := logging.Setup("info", "json", path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() {
+        slog.SetDefault(previous)
+        _ = closeOutput()
+    })
+
+    e := echo.New()
+    e.Use(RequestIDMiddleware(), AccessLogMiddleware)
+    e.GET("/fail", func(c echo.Context) error {
+        return echo.NewHTTPError(http.StatusInternalServerError)
+    })
+
+    tests := []struct {
+        name   string
+        header string
+    }{
+        {"generated", ""},
+        {"from client", "client-chosen-id"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+            if tt.header != "" {
+                req.Header.Set(echo.HeaderXRequestID, tt.header)
+            }
+            rec := httptest.NewRecorder()
+            e.ServeHTTP(rec, req)
+
+            id := rec.Header().Get(echo.HeaderXRequestID)
+            if id == "" || (tt.header != "" && id != tt.header) {
+                t.Errorf("response request ID is %q, want %q", id, tt.header)
+            }
+
+            content, err := os.ReadFile(path)
+            if err != nil {
+                t.Fatal(err)
+            }
+            lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+            var line struct {
+                Level     string `json:"level"`
+                Route     string `json:"route"`
+                Status    int    `json:"status"`
+                RequestID string `json:"request_id"`
+            }
+            if err = json.Unmarshal([]byte(lines[len(lines)-1]), &line); err != nil {
+                t.Fatal(err)
+            }
+            if line.RequestID != id || line.Status != http.StatusInternalServerError || line.Route != "/fail" || line.Level != "ERROR" {
+                t.Errorf("got access log %+v for request %s", line, id)
+            }
+        })
+    }
+}
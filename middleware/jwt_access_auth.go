@@ -4,63 +4,108 @@ import (
     "context"
     "github.com/Encedeus/panel/ent"
     "github.com/Encedeus/panel/ent/apikey"
+    "github.com/Encedeus/panel/logging"
+    "github.com/Encedeus/panel/metrics"
     protoapi "github.com/Encedeus/panel/proto/go"
     "github.com/Encedeus/panel/services"
     "github.com/google/uuid"
     "github.com/labstack/echo/v4"
-    "net/http"
+    "log/slog"
     "slices"
     "strings"
 )
 
+// ContextWithIDFromAccess stores the ID of the authenticated user in ctx, it's also included in its log lines
 func ContextWithIDFromAccess(ctx context.Context, accessToken services.TokenClaims) context.Context {
+    ctx = logging.With(ctx, slog.String("user_id", accessToken.Token.UserId.Value))
+
     return context.WithValue(ctx, contextKey(2), accessToken.Token.UserId.Value)
 }
 
+// IDFromAccessContext returns the ID of the authenticated user, it fails if the request wasn't authenticated
 func IDFromAccessContext(ctx context.Context) (uuid.UUID, error) {
-    return uuid.Parse(ctx.Value(contextKey(2)).(string))
+    id, _ := ctx.Value(contextKey(2)).(string)
+
+    return uuid.Parse(id)
 }
 
 // AccessJWTAuth serves as a middleware for authorization via the access token
 func AccessJWTAuth(db *ent.Client, next echo.HandlerFunc) echo.HandlerFunc {
     return func(c echo.Context) error {
-        // check if the header is empty
-        if strings.TrimSpace(c.Request().Header.Get("Authorization")) == "" {
-            return c.JSON(http.StatusUnauthorized, echo.Map{
-                "message": "unauthorised",
-            })
+        ctx, err := authenticateRequest(c, db)
+        if err != nil {
+            return err
         }
 
-        ctx := c.Request().Context()
-        token := services.GetTokenFromHeader(c)
-
-        isValid, apiKey, _ := services.ValidateAccessJWT(token)
-        if isValid {
-            if apiKey.Type == protoapi.TokenType_ACCOUNT_API_KEY {
-                keyData, err := db.ApiKey.Query().Where(apikey.KeyEQ(token)).First(ctx)
-                if err != nil {
-                    return c.JSON(http.StatusUnauthorized, echo.Map{
-                        "message": "unauthorised",
-                    })
-                }
-
-                ip := strings.Split(c.Request().RemoteAddr, ":")[0]
-                if keyData.IPAddresses != nil && len(strings.TrimSpace(keyData.IPAddresses[0])) > 0 && !slices.Contains(keyData.IPAddresses, ip) {
-                    return c.JSON(http.StatusForbidden, echo.Map{
-                        "message": "access from this IP address not allowed",
-                    })
-                }
-            }
-
-            c.SetRequest(c.Request().WithContext(ContextWithIDFromAccess(ctx, services.TokenClaims{
-                Token: apiKey,
-            })))
+        c.SetRequest(c.Request().WithContext(ctx))
+
+        return next(c)
+    }
+}
 
+// OptionalAccessJWTAuth authenticates requests sending an access token or API key like AccessJWTAuth,
+// but lets every request through, unauthenticated if the credentials are missing or invalid
+func OptionalAccessJWTAuth(db *ent.Client, next echo.HandlerFunc) echo.HandlerFunc {
+    return func(c echo.Context) error {
+        if strings.TrimSpace(c.Request().Header.Get("Authorization")) == "" {
             return next(c)
         }
 
-        return c.JSON(http.StatusUnauthorized, echo.Map{
-            "message": "unauthorised",
-        })
+        if ctx, err := authenticateRequest(c, db); err == nil {
+            c.SetRequest(c.Request().WithContext(ctx))
+        }
+
+        return next(c)
     }
 }
+
+func authenticateRequest(c echo.Context, db *ent.Client) (context.Context, error) {
+    req := c.Request()
+
+    return AuthenticateAccess(req.Context(), db, req.Header.Get("Authorization"), req.RemoteAddr)
+}
+
+// AuthenticateAccess checks the access token or API key of an Authorization header, returning ctx
+// carrying the user's ID, or the error to refuse the request with. API keys limited to some IP addresses
+// are checked against remoteAddr. It's shared by the REST and RPC APIs.
+func AuthenticateAccess(ctx context.Context, db *ent.Client, authorization string, remoteAddr string) (context.Context, error) {
+    // check if the header is empty
+    if strings.TrimSpace(authorization) == "" {
+        metrics.AuthAttempt("access_token", false)
+
+        return nil, services.ErrUnauthorized
+    }
+
+    // removes "Bearer" in front of the token
+    token := strings.TrimPrefix(authorization, "Bearer ")
+
+    isValid, apiKey, _ := services.ValidateAccessJWT(token)
+    if !isValid {
+        metrics.AuthAttempt("access_token", false)
+
+        return nil, services.ErrUnauthorized
+    }
+
+    if apiKey.Type == protoapi.TokenType_ACCOUNT_API_KEY {
+        keyData, err := db.ApiKey.Query().Where(apikey.KeyEQ(token)).First(ctx)
+        if err != nil {
+            metrics.AuthAttempt("api_key", false)
+
+            return nil, services.ErrUnauthorized
+        }
+
+        ip := strings.Split(remoteAddr, ":")[0]
+        if keyData.IPAddresses != nil && len(strings.TrimSpace(keyData.IPAddresses[0])) > 0 && !slices.Contains(keyData.IPAddresses, ip) {
+            metrics.AuthAttempt("api_key", false)
+
+            return nil, services.ErrIPAddressNotAllowed
+        }
+        metrics.AuthAttempt("api_key", true)
+    } else {
+        metrics.AuthAttempt("access_token", true)
+    }
+
+    return ContextWithIDFromAccess(ctx, services.TokenClaims{
+        Token: apiKey,
+    }), nil
+}
@@ -2,19 +2,27 @@ package middleware
 
 import (
     "context"
+    "github.com/Encedeus/panel/logging"
+    "github.com/Encedeus/panel/metrics"
     "github.com/Encedeus/panel/services"
     "github.com/google/uuid"
     "github.com/labstack/echo/v4"
-    "net/http"
+    "log/slog"
     "strings"
 )
 
+// ContextWithIDFromRefresh stores the ID of the user refreshing their token in ctx, it's also included in its log lines
 func ContextWithIDFromRefresh(ctx context.Context, refreshToken services.TokenClaims) context.Context {
+    ctx = logging.With(ctx, slog.String("user_id", refreshToken.Token.UserId.Value))
+
     return context.WithValue(ctx, contextKey(1), refreshToken.Token.UserId.Value)
 }
 
+// IDFromRefreshContext returns the ID of the user refreshing their token, it fails if the request wasn't authenticated
 func IDFromRefreshContext(ctx context.Context) (uuid.UUID, error) {
-    return uuid.Parse(ctx.Value(contextKey(1)).(string))
+    id, _ := ctx.Value(contextKey(1)).(string)
+
+    return uuid.Parse(id)
 }
 
 // RefreshJWTAuth serves as a middleware for authorization via the refresh token
@@ -23,19 +31,18 @@ func RefreshJWTAuth(next echo.HandlerFunc) echo.HandlerFunc {
         // check if cookie exists
         cookie, err := c.Request().Cookie("encedeus_refreshToken")
         if err != nil || strings.TrimSpace(cookie.Value) == "" {
-            return c.JSON(http.StatusUnauthorized, echo.Map{
-                "message": "unauthorised1",
-            })
+            metrics.AuthAttempt("refresh_token", false)
+
+            return services.ErrUnauthorized
         }
 
         // extract and validate JWT
         token := cookie.Value
         isValid, refreshToken, err := services.ValidateRefreshJWT(token)
 
+        metrics.AuthAttempt("refresh_token", isValid && err == nil)
         if !isValid || err != nil {
-            return c.JSON(http.StatusUnauthorized, echo.Map{
-                "message": "unauthorised2",
-            })
+            return services.ErrUnauthorized
         }
 
         c.SetRequest(c.Request().WithContext(ContextWithIDFromRefresh(c.Request().Context(), refreshToken)))
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+    "github.com/Encedeus/panel/metrics"
+    "github.com/labstack/echo/v4"
+    "strconv"
+    "time"
+)
+
+// MetricsMiddleware counts and times requests per route, requests matching no route share one label
+func MetricsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+    return func(c echo.Context) error {
+        start := time.Now()
+
+        // handled here so the status written by the error handler is counted
+        if err := next(c); err != nil {
+            c.Error(err)
+        }
+
+        route := c.Path()
+        if route == "" {
+            route = "unmatched"
+        }
+        method := c.Request().Method
+
+        metrics.HTTPRequests.WithLabelValues(method, route, strconv.Itoa(c.Response().Status)).Inc()
+        metrics.HTTPRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+
+        return nil
+    }
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+    "github.com/Encedeus/panel/config"
+    "github.com/labstack/echo/v4"
+    "github.com/labstack/echo/v4/middleware"
+    "golang.org/x/time/rate"
+    "net/http"
+    "sync"
+)
+
+type rateLimiter struct {
+    mu    sync.Mutex
+    rate  float64
+    burst int
+    store *middleware.RateLimiterMemoryStore
+}
+
+// limiterStore returns the store for the current settings, replacing it when they were reloaded
+func (l *rateLimiter) limiterStore(cfg *config.ServerConfiguration) *middleware.RateLimiterMemoryStore {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    if l.store == nil || l.rate != cfg.RateLimit || l.burst != cfg.RateLimitBurst {
+        l.rate = cfg.RateLimit
+        l.burst = cfg.RateLimitBurst
+        l.store = middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+            Rate:  rate.Limit(cfg.RateLimit),
+            Burst: cfg.RateLimitBurst,
+        })
+    }
+
+    return l.store
+}
+
+// RateLimitMiddleware limits the requests per second of every client IP as set in the server config
+func RateLimitMiddleware() echo.MiddlewareFunc {
+    limiter := &rateLimiter{}
+
+    return func(next echo.HandlerFunc) echo.HandlerFunc {
+        return func(c echo.Context) error {
+            cfg := config.Current().Server
+            if cfg.RateLimit <= 0 {
+                return next(c)
+            }
+
+            allowed, err := limiter.limiterStore(&cfg).Allow(c.RealIP())
+            if err != nil || !allowed {
+                return echo.NewHTTPError(http.StatusTooManyRequests, "too many requests")
+            }
+
+            return next(c)
+        }
+    }
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/services"
+    "github.com/labstack/echo/v4"
+    "net/http"
+    "regexp"
+    "strings"
+    "sync/atomic"
+)
+
+// setupExemptPaths are served before setup is complete
+var setupExemptPaths = []string{"/setup", "/metrics", "/healthz", "/readyz", "/version", "/openapi.json", "/docs"}
+
+// SetupRequiredMiddleware refuses every request but the ones to /setup, metrics, health checks and API docs until the first user was created
+func SetupRequiredMiddleware(db *ent.Client) echo.MiddlewareFunc {
+    // setup can't be undone, so once it's complete the database doesn't need to be asked again
+    var complete atomic.Bool
+
+    return func(next echo.HandlerFunc) echo.HandlerFunc {
+        return func(c echo.Context) error {
+            if complete.Load() || isSetupExempt(c.Request().URL.Path) {
+                return next(c)
+            }
+
+            done, err := services.IsSetupComplete(c.Request().Context(), db)
+            if err != nil {
+                return err
+            }
+            if !done {
+                return echo.NewHTTPError(http.StatusServiceUnavailable, "setup required")
+            }
+
+            complete.Store(true)
+
+            return next(c)
+        }
+    }
+}
+
+// apiVersionPrefix is stripped before matching, so every API version's setup route is exempt
+var apiVersionPrefix = regexp.MustCompile(`^/api/v[0-9]+`)
+
+// isSetupExempt matches the exempt paths and the paths below them, but not e.g. /setupx
+func isSetupExempt(path string) bool {
+    path = apiVersionPrefix.ReplaceAllString(path, "")
+    for _, exempt := range setupExemptPaths {
+        if path == exempt || strings.HasPrefix(path, exempt+"/") {
+            return true
+        }
+    }
+
+    return false
+}
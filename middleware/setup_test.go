@@ -0,0 +1,29 @@
+package middleware
+
+import "testing"
+
+func TestIsSetupExempt(t *testing.T) {
+    tests := []struct {
+        path string
+        want bool
+    }{
+        {"/api/v1/setup", true},
+        {"/api/v2/setup", true},
+        {"/setup", true},
+        {"/healthz", true},
+        {"/docs", true},
+        {"/docs/index.html", true},
+        {"/api/v1/setupx", false},
+        {"/setup-admin", false},
+        {"/healthzz", false},
+        {"/api/v1/user", false},
+        {"/", false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.path, func(t *testing.T) {
+            if got := isSetupExempt(tt.path); got != tt.want {
+                t.Errorf("isSetupExempt(%q) = %v, want %v", tt.path, got, tt.want)
+            }
+        })
+    }
+}
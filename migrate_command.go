@@ -0,0 +1,89 @@
+package main
+
+import (
+    "context"
+    "flag"
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "log"
+    "os"
+    "text/tabwriter"
+    "time"
+)
+
+// runMigrate handles `panel migrate up [--baseline]|down [steps]|status`
+func runMigrate(args []string) {
+    if len(args) == 0 {
+        log.Fatalln("usage: panel migrate up [--baseline]|down [steps]|status")
+    }
+
+    db := config.OpenDB()
+    defer db.Close()
+
+    migrator, err := config.NewMigrator(db, config.Config.DB.Driver)
+    if err != nil {
+        log.Fatalf("failed reading migrations: %v", err)
+    }
+
+    ctx := context.Background()
+    switch args[0] {
+    case "up":
+        fs := flag.NewFlagSet("up", flag.ExitOnError)
+        baseline := fs.Bool("baseline", false, "adopt a database created before the panel had migrations")
+        _ = fs.Parse(args[1:])
+
+        if *baseline {
+            m, err := migrator.Baseline(ctx)
+            if err != nil {
+                log.Fatalln(err)
+            }
+            fmt.Printf("recorded %d_%s as applied\n", m.Version, m.Name)
+        }
+
+        applied, err := migrator.Up(ctx)
+        for _, m := range applied {
+            fmt.Printf("applied %d_%s\n", m.Version, m.Name)
+        }
+        if err != nil {
+            log.Fatalln(err)
+        }
+        if len(applied) == 0 {
+            fmt.Println("database is up to date")
+        }
+    case "down":
+        fs := flag.NewFlagSet("down", flag.ExitOnError)
+        _ = fs.Parse(args[1:])
+        steps := 1
+        if fs.NArg() > 0 {
+            if _, err := fmt.Sscan(fs.Arg(0), &steps); err != nil || steps < 1 {
+                log.Fatalf("invalid number of steps %q", fs.Arg(0))
+            }
+        }
+
+        rolledBack, err := migrator.Down(ctx, steps)
+        for _, m := range rolledBack {
+            fmt.Printf("rolled back %d_%s\n", m.Version, m.Name)
+        }
+        if err != nil {
+            log.Fatalln(err)
+        }
+    case "status":
+        status, err := migrator.Status(ctx)
+        if err != nil {
+            log.Fatalln(err)
+        }
+
+        w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+        _, _ = fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+        for _, m := range status {
+            appliedAt := "pending"
+            if m.AppliedAt != nil {
+                appliedAt = m.AppliedAt.Format(time.RFC3339)
+            }
+            _, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", m.Version, m.Name, appliedAt)
+        }
+        _ = w.Flush()
+    default:
+        log.Fatalf("unknown migrate command %q, expected up, down or status", args[0])
+    }
+}
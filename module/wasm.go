@@ -1,120 +1,137 @@
 package module
 
 import (
+    "context"
+    "errors"
+    "fmt"
+    "log/slog"
     "os"
+    "path/filepath"
+    "strings"
     "sync"
 
+    "github.com/Encedeus/panel/metrics"
     "github.com/second-state/WasmEdge-go/wasmedge"
 )
 
-// func InitWasmtime() {
-//     dir, err := os.MkdirTemp("", "out")
-//     check(err)
-//     defer os.RemoveAll(dir)
-//     stdoutPath := filepath.Join(dir, "stdout")
-//
-//     engine := wasmtime.NewEngine()
-//
-//     module, err := wasmtime.NewModuleFromFile(engine, "/mnt/h/Programming/Web/Workspace/Projects/Encedeus/panel/module_test/go/main.wasm")
-//     check(err)
-//
-//     linker := wasmtime.NewLinker(engine)
-//     err = linker.DefineWasi()
-//     check(err)
-//     wasiConfig := wasmtime.NewWasiConfig()
-//     wasiConfig.SetStdoutFile(stdoutPath)
-//
-//     store := wasmtime.NewStore(engine)
-//     store.SetWasi(wasiConfig)
-//
-//     // exposeFuncs(store, linker)
-//
-//     err = executeModule(store, module, linker)
-//     check(err)
-//
-//     out, err := os.ReadFile(stdoutPath)
-//     check(err)
-//     fmt.Print(string(out))
-// }
-//
-// func check(e error) {
-//     if e != nil {
-//         panic(e)
-//     }
-// }
-//
-// func executeModule(store wasmtime.Storelike, module *wasmtime.Module, linker *wasmtime.Linker) (err error) {
-//     instance, err := linker.Instantiate(store, module)
-//     if err != nil {
-//         return err
-//     }
-//
-//     run := instance.GetFunc(store, "_start")
-//     if run == nil {
-//         return errors.New("module is invalid")
-//     }
-//
-//     _, err = run.Call(store)
-//     if err != nil {
-//         return err
-//     }
-//
-//     return nil
-// }
-
-// func exposeFuncs(store wasmtime.Storelike, linker *wasmtime.Linker) {
-// }
-
-func Init() {
-    // wasmedge.SetLogErrorLevel()
-
-    // bytes, err := os.ReadFile("/mnt/h/Programming/Web/Workspace/Projects/Encedeus/wasmedge-quickjs/wasmedge_quickjs.wasm")
-    // if err != nil {
-    //     panic(err)
-    // }
-    //
-    // _, err = vm.RunWasmBuffer(bytes, "_start")
-    // if err != nil {
-    //     panic(err)
-    // }
+// Host runs the plugins in a directory, every .wasm file in it in its own VM
+type Host struct {
+    dir string
+
+    mu      sync.Mutex
+    started bool
+    // err is why the plugins couldn't be loaded
+    err error
+    // failed holds the plugins that stopped with an error
+    failed []string
+}
+
+func NewHost(dir string) *Host {
+    return &Host{dir: dir}
+}
+
+// Run starts every plugin and waits until they exited or ctx is done
+func (h *Host) Run(ctx context.Context) {
+    paths, err := h.plugins()
+    h.mu.Lock()
+    h.started, h.err = err == nil, err
+    h.mu.Unlock()
+    if err != nil {
+        slog.ErrorContext(ctx, "error loading plugins", "dir", h.dir, "error", err)
+        return
+    }
+    slog.InfoContext(ctx, "starting plugins", "dir", h.dir, "count", len(paths))
+    metrics.RegisterPluginVMs()
 
     var wg sync.WaitGroup
-    wg.Add(2)
-    go func() {
-        defer wg.Done()
-        conf := wasmedge.NewConfigure(wasmedge.REFERENCE_TYPES)
-        conf.AddConfig(wasmedge.WASI)
-        vm := wasmedge.NewVMWithConfig(conf)
-        wasi := vm.GetImportModule(wasmedge.WASI)
-        wasi.InitWasi(
-            os.Args[1:],
-            os.Environ(),
-            []string{".:."},
-        )
-
-        defer vm.Release()
-        defer conf.Release()
-
-        vm.RunWasmFile("/mnt/h/Programming/Web/Workspace/Projects/Encedeus/test/js/main.wasm", "_start")
-    }()
-
-    go func() {
-        defer wg.Done()
-        conf := wasmedge.NewConfigure(wasmedge.REFERENCE_TYPES)
-        conf.AddConfig(wasmedge.WASI)
-        vm := wasmedge.NewVMWithConfig(conf)
-        wasi := vm.GetImportModule(wasmedge.WASI)
-        wasi.InitWasi(
-            os.Args[1:],
-            os.Environ(),
-            []string{".:."},
-        )
-
-        defer vm.Release()
-        defer conf.Release()
-
-        vm.RunWasmFile("/mnt/h/Programming/Web/Workspace/Projects/Encedeus/test/go/main.wasm", "_start")
-    }()
+    for _, path := range paths {
+        wg.Add(1)
+        go func(path string) {
+            defer wg.Done()
+
+            if err := RunVM(ctx, path); err != nil {
+                slog.ErrorContext(ctx, "plugin stopped with an error", "plugin", path, "error", err)
+
+                h.mu.Lock()
+                h.failed = append(h.failed, filepath.Base(path))
+                h.mu.Unlock()
+            }
+        }(path)
+    }
 
     wg.Wait()
 }
+
+// Check reports the state of the plugin host for the readiness probe. It fails until the plugins were started, if
+// they couldn't be loaded and once one of them stopped with an error.
+func (h *Host) Check(context.Context) error {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+
+    switch {
+    case h.err != nil:
+        return fmt.Errorf("failed loading plugins from %s: %w", h.dir, h.err)
+    case !h.started:
+        return errors.New("plugins weren't started yet")
+    case len(h.failed) != 0:
+        return fmt.Errorf("plugins stopped with an error: %s", strings.Join(h.failed, ", "))
+    }
+
+    return nil
+}
+
+// plugins lists the .wasm files in the plugin directory
+func (h *Host) plugins() ([]string, error) {
+    entries, err := os.ReadDir(h.dir)
+    if err != nil {
+        return nil, err
+    }
+
+    var paths []string
+    for _, entry := range entries {
+        if !entry.IsDir() && filepath.Ext(entry.Name()) == ".wasm" {
+            paths = append(paths, filepath.Join(h.dir, entry.Name()))
+        }
+    }
+
+    return paths, nil
+}
+
+// RunVM runs the _start function of a WASI module, cancelling it once ctx is done. It returns the error the module
+// exited with, cancelling it isn't one.
+func RunVM(ctx context.Context, path string) error {
+    conf := wasmedge.NewConfigure(wasmedge.REFERENCE_TYPES)
+    conf.AddConfig(wasmedge.WASI)
+    vm := wasmedge.NewVMWithConfig(conf)
+    wasi := vm.GetImportModule(wasmedge.WASI)
+    wasi.InitWasi(
+        os.Args[1:],
+        os.Environ(),
+        []string{".:."},
+    )
+
+    defer vm.Release()
+    defer conf.Release()
+
+    async := vm.AsyncRunWasmFile(path, "_start")
+    defer async.Release()
+
+    metrics.PluginVMsStarted.Inc()
+    metrics.PluginVMsRunning.Inc()
+    defer metrics.PluginVMsRunning.Dec()
+
+    // poll so the VM can be cancelled, WaitFor returns true once it exited
+    for !async.WaitFor(100) {
+        select {
+        case <-ctx.Done():
+            async.Cancel()
+            // blocks until the VM actually stopped so it isn't released while running
+            _, _ = async.GetResult()
+            return nil
+        default:
+        }
+    }
+
+    _, err := async.GetResult()
+    return err
+}
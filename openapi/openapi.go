@@ -0,0 +1,348 @@
+// Package openapi generates the OpenAPI 3 document of the REST API from the registered routes
+// and the protobuf messages their handlers read and write
+package openapi
+
+import (
+    _ "embed"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "google.golang.org/protobuf/proto"
+    "net/http"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// Version is the OpenAPI version of the generated documents
+const Version = "3.0.3"
+
+// Auth says which credentials a route requires
+type Auth int
+
+const (
+    AuthNone Auth = iota
+    // AuthAccess requires an access token or account API key as bearer token
+    AuthAccess
+    // AuthOptionalAccess works without credentials, an access token or API key unlocks more details
+    AuthOptionalAccess
+    // AuthRefresh requires the refresh token cookie set on sign in
+    AuthRefresh
+    // AuthMetrics requires the metrics token as bearer token, if one is configured
+    AuthMetrics
+)
+
+// Operation documents a route
+type Operation struct {
+    Summary string
+    // Tag groups the operation in viewers, it defaults to the first segment of the path
+    Tag  string
+    Auth Auth
+    // Request is the message the JSON body is decoded into, nil if the route reads no JSON body
+    Request proto.Message
+    // Response is the message written on success, nil if the response has no JSON body
+    Response proto.Message
+    // Status is the status code of a successful response, 200 if unset
+    Status int
+    // ContentType is the type of a successful response that isn't JSON
+    ContentType string
+    // Conditional operations take the resource's ETag in an If-Match header and fail with 412 if it changed since
+    Conditional bool
+    // Query lists the optional query parameters the operation reads, In is always set to query
+    Query []Parameter
+}
+
+// Route is a registered method and path, paths use echo's syntax, e.g. /user/:id
+type Route struct {
+    Method string
+    Path   string
+}
+
+// Key identifies the route in the operations passed to Generate, e.g. "GET /user/:id"
+func (r Route) Key() string {
+    return r.Method + " " + normalizePath(r.Path)
+}
+
+type Document struct {
+    OpenAPI    string              `json:"openapi"`
+    Info       Info                `json:"info"`
+    Paths      map[string]PathItem `json:"paths"`
+    Components Components          `json:"components"`
+}
+
+type Info struct {
+    Title       string `json:"title"`
+    Description string `json:"description,omitempty"`
+    Version     string `json:"version"`
+}
+
+// PathItem holds the operations of a path by lowercase method
+type PathItem map[string]*PathOperation
+
+type PathOperation struct {
+    Summary     string                `json:"summary,omitempty"`
+    Tags        []string              `json:"tags,omitempty"`
+    Parameters  []Parameter           `json:"parameters,omitempty"`
+    RequestBody *RequestBody          `json:"requestBody,omitempty"`
+    Responses   map[string]Response   `json:"responses"`
+    Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+    Name     string  `json:"name"`
+    In       string  `json:"in"`
+    Required bool    `json:"required"`
+    Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+    Required bool                 `json:"required"`
+    Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+    Description string               `json:"description"`
+    Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+    Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+    Schemas         map[string]*Schema        `json:"schemas"`
+    SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+    Type         string `json:"type"`
+    Description  string `json:"description,omitempty"`
+    Scheme       string `json:"scheme,omitempty"`
+    BearerFormat string `json:"bearerFormat,omitempty"`
+    In           string `json:"in,omitempty"`
+    Name         string `json:"name,omitempty"`
+}
+
+const (
+    schemeBearer  = "bearerAuth"
+    schemeRefresh = "refreshCookie"
+    schemeMetrics = "metricsToken"
+)
+
+var standardMethods = map[string]bool{
+    http.MethodGet:     true,
+    http.MethodHead:    true,
+    http.MethodPost:    true,
+    http.MethodPut:     true,
+    http.MethodPatch:   true,
+    http.MethodDelete:  true,
+    http.MethodOptions: true,
+}
+
+// Generate documents routes, taking the details of each from ops by its key.
+// Undocumented routes are still included, Diff reports them.
+func Generate(info Info, routes []Route, ops map[string]Operation) *Document {
+    schemas := newSchemaBuilder()
+    doc := &Document{
+        OpenAPI: Version,
+        Info:    info,
+        Paths:   map[string]PathItem{},
+        Components: Components{
+            Schemas:         schemas.schemas,
+            SecuritySchemes: securitySchemes(),
+        },
+    }
+    // every error is written as an HttpResponse by the server's error handler
+    errorRef := schemas.message((&protoapi.HttpResponse{}).ProtoReflect().Descriptor())
+
+    for _, r := range documentedRoutes(routes) {
+        path, params := openAPIPath(r.Path)
+        item, ok := doc.Paths[path]
+        if !ok {
+            item = PathItem{}
+            doc.Paths[path] = item
+        }
+
+        item[strings.ToLower(r.Method)] = newPathOperation(ops[r.Key()], r.Path, params, schemas, errorRef)
+    }
+
+    return doc
+}
+
+// Diff returns the keys of routes missing from ops and of ops whose route isn't registered, both sorted
+func Diff(routes []Route, ops map[string]Operation) (undocumented []string, unrouted []string) {
+    routed := map[string]bool{}
+    for _, r := range documentedRoutes(routes) {
+        routed[r.Key()] = true
+        if _, ok := ops[r.Key()]; !ok {
+            undocumented = append(undocumented, r.Key())
+        }
+    }
+    for key := range ops {
+        if !routed[key] {
+            unrouted = append(unrouted, key)
+        }
+    }
+
+    sort.Strings(undocumented)
+    sort.Strings(unrouted)
+
+    return undocumented, unrouted
+}
+
+// documentedRoutes drops the routes echo registers internally, like the not found handlers of groups
+func documentedRoutes(routes []Route) []Route {
+    var documented []Route
+    for _, r := range routes {
+        if standardMethods[r.Method] {
+            documented = append(documented, r)
+        }
+    }
+
+    return documented
+}
+
+func newPathOperation(op Operation, rawPath string, params []string, schemas *schemaBuilder, errorRef *Schema) *PathOperation {
+    tag := op.Tag
+    if tag == "" {
+        tag = strings.Split(strings.TrimPrefix(normalizePath(rawPath), "/"), "/")[0]
+    }
+
+    pathOp := &PathOperation{
+        Summary:   op.Summary,
+        Tags:      []string{tag},
+        Responses: map[string]Response{},
+    }
+
+    for _, name := range params {
+        schema := &Schema{Type: "string"}
+        if strings.HasSuffix(strings.ToLower(name), "id") {
+            schema.Format = "uuid"
+        }
+        pathOp.Parameters = append(pathOp.Parameters, Parameter{
+            Name:     name,
+            In:       "path",
+            Required: true,
+            Schema:   schema,
+        })
+    }
+
+    for _, param := range op.Query {
+        param.In = "query"
+        pathOp.Parameters = append(pathOp.Parameters, param)
+    }
+
+    if op.Conditional {
+        pathOp.Parameters = append(pathOp.Parameters, Parameter{
+            Name:   "If-Match",
+            In:     "header",
+            Schema: &Schema{Type: "string"},
+        })
+    }
+
+    if op.Request != nil {
+        pathOp.RequestBody = &RequestBody{
+            Required: true,
+            Content:  jsonContent(schemas.message(op.Request.ProtoReflect().Descriptor())),
+        }
+    }
+
+    status := op.Status
+    if status == 0 {
+        status = http.StatusOK
+    }
+    success := Response{Description: http.StatusText(status)}
+    switch {
+    case op.Response != nil:
+        success.Content = jsonContent(schemas.message(op.Response.ProtoReflect().Descriptor()))
+    case op.ContentType != "":
+        success.Content = map[string]MediaType{op.ContentType: {Schema: &Schema{}}}
+    }
+    pathOp.Responses[strconv.Itoa(status)] = success
+    if op.Conditional {
+        pathOp.Responses[strconv.Itoa(http.StatusPreconditionFailed)] = Response{
+            Description: http.StatusText(http.StatusPreconditionFailed),
+            Content:     jsonContent(errorRef),
+        }
+    }
+    pathOp.Responses["default"] = Response{
+        Description: "Error",
+        Content:     jsonContent(errorRef),
+    }
+
+    switch op.Auth {
+    case AuthAccess:
+        pathOp.Security = []map[string][]string{{schemeBearer: {}}}
+    case AuthOptionalAccess:
+        pathOp.Security = []map[string][]string{{}, {schemeBearer: {}}}
+    case AuthRefresh:
+        pathOp.Security = []map[string][]string{{schemeRefresh: {}}}
+    case AuthMetrics:
+        pathOp.Security = []map[string][]string{{schemeMetrics: {}}}
+    }
+
+    return pathOp
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+    return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func securitySchemes() map[string]SecurityScheme {
+    return map[string]SecurityScheme{
+        schemeBearer: {
+            Type:         "http",
+            Scheme:       "bearer",
+            BearerFormat: "JWT",
+            Description:  "Access token returned on sign in, or an account API key",
+        },
+        schemeRefresh: {
+            Type:        "apiKey",
+            In:          "cookie",
+            Name:        "encedeus_refreshToken",
+            Description: "Refresh token set on sign in",
+        },
+        schemeMetrics: {
+            Type:        "http",
+            Scheme:      "bearer",
+            Description: "Token set in the metrics block of the config",
+        },
+    }
+}
+
+var paramPattern = regexp.MustCompile(`:([^/]+)`)
+
+// normalizePath adds the leading slash echo's groups leave out of their routes' paths
+func normalizePath(path string) string {
+    if !strings.HasPrefix(path, "/") {
+        return "/" + path
+    }
+
+    return path
+}
+
+// openAPIPath converts an echo path to an OpenAPI one, returning the names of its parameters,
+// a trailing wildcard becomes a path parameter
+func openAPIPath(path string) (string, []string) {
+    path = normalizePath(path)
+
+    var params []string
+    for _, match := range paramPattern.FindAllStringSubmatch(path, -1) {
+        params = append(params, match[1])
+    }
+    path = paramPattern.ReplaceAllString(path, "{$1}")
+
+    if strings.HasSuffix(path, "*") {
+        path = strings.TrimSuffix(strings.TrimSuffix(path, "*"), "/") + "/{path}"
+        params = append(params, "path")
+    }
+
+    return path, params
+}
+
+//go:embed viewer.html
+var viewer []byte
+
+// Viewer returns an HTML page rendering the document served at /openapi.json
+func Viewer() []byte {
+    return viewer
+}
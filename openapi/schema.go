@@ -0,0 +1,110 @@
+package openapi
+
+import "google.golang.org/protobuf/reflect/protoreflect"
+
+// Schema is the subset of the OpenAPI schema object needed to describe protobuf messages as protojson encodes them
+type Schema struct {
+    Ref                  string             `json:"$ref,omitempty"`
+    Type                 string             `json:"type,omitempty"`
+    Format               string             `json:"format,omitempty"`
+    Enum                 []string           `json:"enum,omitempty"`
+    Items                *Schema            `json:"items,omitempty"`
+    Properties           map[string]*Schema `json:"properties,omitempty"`
+    AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// wellKnownSchemas are the protobuf types protojson encodes specially
+var wellKnownSchemas = map[protoreflect.FullName]Schema{
+    "google.protobuf.Timestamp": {Type: "string", Format: "date-time"},
+    "google.protobuf.Duration":  {Type: "string"},
+    "google.protobuf.Struct":    {Type: "object"},
+    "google.protobuf.Empty":     {Type: "object"},
+}
+
+// schemaBuilder collects the schemas of messages and the ones they reference
+type schemaBuilder struct {
+    schemas map[string]*Schema
+}
+
+func newSchemaBuilder() *schemaBuilder {
+    return &schemaBuilder{
+        schemas: map[string]*Schema{},
+    }
+}
+
+// message returns a reference to the schema of md, adding it to the components if it's missing
+func (b *schemaBuilder) message(md protoreflect.MessageDescriptor) *Schema {
+    if s, ok := wellKnownSchemas[md.FullName()]; ok {
+        return &s
+    }
+
+    name := string(md.FullName())
+    if _, ok := b.schemas[name]; !ok {
+        s := &Schema{
+            Type:       "object",
+            Properties: map[string]*Schema{},
+        }
+        // added before the fields so recursive messages end up referencing it
+        b.schemas[name] = s
+
+        fields := md.Fields()
+        for i := 0; i < fields.Len(); i++ {
+            f := fields.Get(i)
+            s.Properties[f.JSONName()] = b.field(f)
+        }
+    }
+
+    return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (b *schemaBuilder) field(f protoreflect.FieldDescriptor) *Schema {
+    switch {
+    case f.IsMap():
+        return &Schema{
+            Type:                 "object",
+            AdditionalProperties: b.value(f.MapValue()),
+        }
+    case f.IsList():
+        return &Schema{
+            Type:  "array",
+            Items: b.value(f),
+        }
+    }
+
+    return b.value(f)
+}
+
+// value returns the schema of a single value of f, protojson encodes 64-bit integers as strings
+func (b *schemaBuilder) value(f protoreflect.FieldDescriptor) *Schema {
+    switch f.Kind() {
+    case protoreflect.BoolKind:
+        return &Schema{Type: "boolean"}
+    case protoreflect.StringKind:
+        return &Schema{Type: "string"}
+    case protoreflect.BytesKind:
+        return &Schema{Type: "string", Format: "byte"}
+    case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+        return &Schema{Type: "integer", Format: "int32"}
+    case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+        return &Schema{Type: "integer", Format: "int64"}
+    case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+        protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+        return &Schema{Type: "string", Format: "int64"}
+    case protoreflect.FloatKind:
+        return &Schema{Type: "number", Format: "float"}
+    case protoreflect.DoubleKind:
+        return &Schema{Type: "number", Format: "double"}
+    case protoreflect.EnumKind:
+        values := f.Enum().Values()
+        s := &Schema{Type: "string"}
+        for i := 0; i < values.Len(); i++ {
+            s.Enum = append(s.Enum, string(values.Get(i).Name()))
+        }
+
+        return s
+    case protoreflect.MessageKind, protoreflect.GroupKind:
+        return b.message(f.Message())
+    }
+
+    return &Schema{}
+}
@@ -0,0 +1,4 @@
+package proto
+
+//go:generate protoc --go_out=. generic.proto common.proto auth_api.proto user_api.proto role_api.proto api_key_api.proto webhook_api.proto setup_api.proto system_api.proto services.proto validate.proto
+//go:generate protoc --connect-go_out=. --connect-go_opt=module=github.com/Encedeus/panel/proto,Mservices.proto=github.com/Encedeus/panel/proto/go;protoapi,Mauth_api.proto=github.com/Encedeus/panel/proto/go;protoapi,Muser_api.proto=github.com/Encedeus/panel/proto/go;protoapi,Mrole_api.proto=github.com/Encedeus/panel/proto/go;protoapi,Mapi_key_api.proto=github.com/Encedeus/panel/proto/go;protoapi,Mvalidate.proto=github.com/Encedeus/panel/proto/go;protoapi services.proto
@@ -0,0 +1,796 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: services.proto
+
+// The services are served over gRPC, gRPC-Web and Connect next to the REST API, on the same address.
+// Every RPC but the ones of AuthService needs an access token or account API key in the Authorization header.
+package protoapiconnect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	_go "github.com/Encedeus/panel/proto/go"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion0_1_0
+
+const (
+	// AuthServiceName is the fully-qualified name of the AuthService service.
+	AuthServiceName = "encedeus.panel.v1.AuthService"
+	// UserServiceName is the fully-qualified name of the UserService service.
+	UserServiceName = "encedeus.panel.v1.UserService"
+	// RoleServiceName is the fully-qualified name of the RoleService service.
+	RoleServiceName = "encedeus.panel.v1.RoleService"
+	// ApiKeyServiceName is the fully-qualified name of the ApiKeyService service.
+	ApiKeyServiceName = "encedeus.panel.v1.ApiKeyService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// AuthServiceSignInProcedure is the fully-qualified name of the AuthService's SignIn RPC.
+	AuthServiceSignInProcedure = "/encedeus.panel.v1.AuthService/SignIn"
+	// AuthServiceRefreshAccessTokenProcedure is the fully-qualified name of the AuthService's
+	// RefreshAccessToken RPC.
+	AuthServiceRefreshAccessTokenProcedure = "/encedeus.panel.v1.AuthService/RefreshAccessToken"
+	// UserServiceFindUserProcedure is the fully-qualified name of the UserService's FindUser RPC.
+	UserServiceFindUserProcedure = "/encedeus.panel.v1.UserService/FindUser"
+	// UserServiceCreateUserProcedure is the fully-qualified name of the UserService's CreateUser RPC.
+	UserServiceCreateUserProcedure = "/encedeus.panel.v1.UserService/CreateUser"
+	// UserServiceUpdateUserProcedure is the fully-qualified name of the UserService's UpdateUser RPC.
+	UserServiceUpdateUserProcedure = "/encedeus.panel.v1.UserService/UpdateUser"
+	// UserServiceDeleteUserProcedure is the fully-qualified name of the UserService's DeleteUser RPC.
+	UserServiceDeleteUserProcedure = "/encedeus.panel.v1.UserService/DeleteUser"
+	// UserServiceRestoreUserProcedure is the fully-qualified name of the UserService's RestoreUser RPC.
+	UserServiceRestoreUserProcedure = "/encedeus.panel.v1.UserService/RestoreUser"
+	// UserServiceChangePasswordProcedure is the fully-qualified name of the UserService's
+	// ChangePassword RPC.
+	UserServiceChangePasswordProcedure = "/encedeus.panel.v1.UserService/ChangePassword"
+	// UserServiceChangeUsernameProcedure is the fully-qualified name of the UserService's
+	// ChangeUsername RPC.
+	UserServiceChangeUsernameProcedure = "/encedeus.panel.v1.UserService/ChangeUsername"
+	// UserServiceChangeEmailProcedure is the fully-qualified name of the UserService's ChangeEmail RPC.
+	UserServiceChangeEmailProcedure = "/encedeus.panel.v1.UserService/ChangeEmail"
+	// UserServiceFindRoleGrantsProcedure is the fully-qualified name of the UserService's
+	// FindRoleGrants RPC.
+	UserServiceFindRoleGrantsProcedure = "/encedeus.panel.v1.UserService/FindRoleGrants"
+	// UserServiceGrantRoleProcedure is the fully-qualified name of the UserService's GrantRole RPC.
+	UserServiceGrantRoleProcedure = "/encedeus.panel.v1.UserService/GrantRole"
+	// UserServiceRevokeRoleProcedure is the fully-qualified name of the UserService's RevokeRole RPC.
+	UserServiceRevokeRoleProcedure = "/encedeus.panel.v1.UserService/RevokeRole"
+	// RoleServiceFindRoleProcedure is the fully-qualified name of the RoleService's FindRole RPC.
+	RoleServiceFindRoleProcedure = "/encedeus.panel.v1.RoleService/FindRole"
+	// RoleServiceFindRoleEffectivePermissionsProcedure is the fully-qualified name of the RoleService's
+	// FindRoleEffectivePermissions RPC.
+	RoleServiceFindRoleEffectivePermissionsProcedure = "/encedeus.panel.v1.RoleService/FindRoleEffectivePermissions"
+	// RoleServiceCreateRoleProcedure is the fully-qualified name of the RoleService's CreateRole RPC.
+	RoleServiceCreateRoleProcedure = "/encedeus.panel.v1.RoleService/CreateRole"
+	// RoleServiceUpdateRoleProcedure is the fully-qualified name of the RoleService's UpdateRole RPC.
+	RoleServiceUpdateRoleProcedure = "/encedeus.panel.v1.RoleService/UpdateRole"
+	// RoleServiceDeleteRoleProcedure is the fully-qualified name of the RoleService's DeleteRole RPC.
+	RoleServiceDeleteRoleProcedure = "/encedeus.panel.v1.RoleService/DeleteRole"
+	// RoleServiceRestoreRoleProcedure is the fully-qualified name of the RoleService's RestoreRole RPC.
+	RoleServiceRestoreRoleProcedure = "/encedeus.panel.v1.RoleService/RestoreRole"
+	// ApiKeyServiceCreateAccountAPIKeyProcedure is the fully-qualified name of the ApiKeyService's
+	// CreateAccountAPIKey RPC.
+	ApiKeyServiceCreateAccountAPIKeyProcedure = "/encedeus.panel.v1.ApiKeyService/CreateAccountAPIKey"
+	// ApiKeyServiceDeleteAccountAPIKeyProcedure is the fully-qualified name of the ApiKeyService's
+	// DeleteAccountAPIKey RPC.
+	ApiKeyServiceDeleteAccountAPIKeyProcedure = "/encedeus.panel.v1.ApiKeyService/DeleteAccountAPIKey"
+	// ApiKeyServiceFindAccountAPIKeysByUserProcedure is the fully-qualified name of the ApiKeyService's
+	// FindAccountAPIKeysByUser RPC.
+	ApiKeyServiceFindAccountAPIKeysByUserProcedure = "/encedeus.panel.v1.ApiKeyService/FindAccountAPIKeysByUser"
+)
+
+// AuthServiceClient is a client for the encedeus.panel.v1.AuthService service.
+type AuthServiceClient interface {
+	// SignIn returns both tokens in the body instead of setting the refresh token cookie
+	SignIn(context.Context, *connect.Request[_go.UserSignInRequest]) (*connect.Response[_go.UserSignInResponse], error)
+	RefreshAccessToken(context.Context, *connect.Request[_go.AccessTokenRefreshRequest]) (*connect.Response[_go.AccessTokenRefreshResponse], error)
+}
+
+// NewAuthServiceClient constructs a client for the encedeus.panel.v1.AuthService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAuthServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AuthServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &authServiceClient{
+		signIn: connect.NewClient[_go.UserSignInRequest, _go.UserSignInResponse](
+			httpClient,
+			baseURL+AuthServiceSignInProcedure,
+			opts...,
+		),
+		refreshAccessToken: connect.NewClient[_go.AccessTokenRefreshRequest, _go.AccessTokenRefreshResponse](
+			httpClient,
+			baseURL+AuthServiceRefreshAccessTokenProcedure,
+			opts...,
+		),
+	}
+}
+
+// authServiceClient implements AuthServiceClient.
+type authServiceClient struct {
+	signIn             *connect.Client[_go.UserSignInRequest, _go.UserSignInResponse]
+	refreshAccessToken *connect.Client[_go.AccessTokenRefreshRequest, _go.AccessTokenRefreshResponse]
+}
+
+// SignIn calls encedeus.panel.v1.AuthService.SignIn.
+func (c *authServiceClient) SignIn(ctx context.Context, req *connect.Request[_go.UserSignInRequest]) (*connect.Response[_go.UserSignInResponse], error) {
+	return c.signIn.CallUnary(ctx, req)
+}
+
+// RefreshAccessToken calls encedeus.panel.v1.AuthService.RefreshAccessToken.
+func (c *authServiceClient) RefreshAccessToken(ctx context.Context, req *connect.Request[_go.AccessTokenRefreshRequest]) (*connect.Response[_go.AccessTokenRefreshResponse], error) {
+	return c.refreshAccessToken.CallUnary(ctx, req)
+}
+
+// AuthServiceHandler is an implementation of the encedeus.panel.v1.AuthService service.
+type AuthServiceHandler interface {
+	// SignIn returns both tokens in the body instead of setting the refresh token cookie
+	SignIn(context.Context, *connect.Request[_go.UserSignInRequest]) (*connect.Response[_go.UserSignInResponse], error)
+	RefreshAccessToken(context.Context, *connect.Request[_go.AccessTokenRefreshRequest]) (*connect.Response[_go.AccessTokenRefreshResponse], error)
+}
+
+// NewAuthServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAuthServiceHandler(svc AuthServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	authServiceSignInHandler := connect.NewUnaryHandler(
+		AuthServiceSignInProcedure,
+		svc.SignIn,
+		opts...,
+	)
+	authServiceRefreshAccessTokenHandler := connect.NewUnaryHandler(
+		AuthServiceRefreshAccessTokenProcedure,
+		svc.RefreshAccessToken,
+		opts...,
+	)
+	return "/encedeus.panel.v1.AuthService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AuthServiceSignInProcedure:
+			authServiceSignInHandler.ServeHTTP(w, r)
+		case AuthServiceRefreshAccessTokenProcedure:
+			authServiceRefreshAccessTokenHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAuthServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAuthServiceHandler struct{}
+
+func (UnimplementedAuthServiceHandler) SignIn(context.Context, *connect.Request[_go.UserSignInRequest]) (*connect.Response[_go.UserSignInResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.AuthService.SignIn is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) RefreshAccessToken(context.Context, *connect.Request[_go.AccessTokenRefreshRequest]) (*connect.Response[_go.AccessTokenRefreshResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.AuthService.RefreshAccessToken is not implemented"))
+}
+
+// UserServiceClient is a client for the encedeus.panel.v1.UserService service.
+type UserServiceClient interface {
+	FindUser(context.Context, *connect.Request[_go.UserFindOneRequest]) (*connect.Response[_go.UserFindOneResponse], error)
+	CreateUser(context.Context, *connect.Request[_go.UserCreateRequest]) (*connect.Response[_go.UserCreateResponse], error)
+	UpdateUser(context.Context, *connect.Request[_go.UserUpdateRequest]) (*connect.Response[_go.UserUpdateResponse], error)
+	DeleteUser(context.Context, *connect.Request[_go.UserDeleteRequest]) (*connect.Response[_go.UserDeleteResponse], error)
+	RestoreUser(context.Context, *connect.Request[_go.UserRestoreRequest]) (*connect.Response[_go.UserRestoreResponse], error)
+	// the change RPCs act on the authenticated user, the user_id of the request is ignored
+	ChangePassword(context.Context, *connect.Request[_go.UserChangePasswordRequest]) (*connect.Response[_go.UserChangePasswordResponse], error)
+	ChangeUsername(context.Context, *connect.Request[_go.UserChangeUsernameRequest]) (*connect.Response[_go.UserChangeUsernameResponse], error)
+	ChangeEmail(context.Context, *connect.Request[_go.UserChangeEmailRequest]) (*connect.Response[_go.UserChangeEmailResponse], error)
+	FindRoleGrants(context.Context, *connect.Request[_go.UserRoleGrantFindManyRequest]) (*connect.Response[_go.UserRoleGrantFindManyResponse], error)
+	GrantRole(context.Context, *connect.Request[_go.UserRoleGrantRequest]) (*connect.Response[_go.UserRoleGrantResponse], error)
+	RevokeRole(context.Context, *connect.Request[_go.UserRoleRevokeRequest]) (*connect.Response[_go.UserRoleRevokeResponse], error)
+}
+
+// NewUserServiceClient constructs a client for the encedeus.panel.v1.UserService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewUserServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) UserServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &userServiceClient{
+		findUser: connect.NewClient[_go.UserFindOneRequest, _go.UserFindOneResponse](
+			httpClient,
+			baseURL+UserServiceFindUserProcedure,
+			opts...,
+		),
+		createUser: connect.NewClient[_go.UserCreateRequest, _go.UserCreateResponse](
+			httpClient,
+			baseURL+UserServiceCreateUserProcedure,
+			opts...,
+		),
+		updateUser: connect.NewClient[_go.UserUpdateRequest, _go.UserUpdateResponse](
+			httpClient,
+			baseURL+UserServiceUpdateUserProcedure,
+			opts...,
+		),
+		deleteUser: connect.NewClient[_go.UserDeleteRequest, _go.UserDeleteResponse](
+			httpClient,
+			baseURL+UserServiceDeleteUserProcedure,
+			opts...,
+		),
+		restoreUser: connect.NewClient[_go.UserRestoreRequest, _go.UserRestoreResponse](
+			httpClient,
+			baseURL+UserServiceRestoreUserProcedure,
+			opts...,
+		),
+		changePassword: connect.NewClient[_go.UserChangePasswordRequest, _go.UserChangePasswordResponse](
+			httpClient,
+			baseURL+UserServiceChangePasswordProcedure,
+			opts...,
+		),
+		changeUsername: connect.NewClient[_go.UserChangeUsernameRequest, _go.UserChangeUsernameResponse](
+			httpClient,
+			baseURL+UserServiceChangeUsernameProcedure,
+			opts...,
+		),
+		changeEmail: connect.NewClient[_go.UserChangeEmailRequest, _go.UserChangeEmailResponse](
+			httpClient,
+			baseURL+UserServiceChangeEmailProcedure,
+			opts...,
+		),
+		findRoleGrants: connect.NewClient[_go.UserRoleGrantFindManyRequest, _go.UserRoleGrantFindManyResponse](
+			httpClient,
+			baseURL+UserServiceFindRoleGrantsProcedure,
+			opts...,
+		),
+		grantRole: connect.NewClient[_go.UserRoleGrantRequest, _go.UserRoleGrantResponse](
+			httpClient,
+			baseURL+UserServiceGrantRoleProcedure,
+			opts...,
+		),
+		revokeRole: connect.NewClient[_go.UserRoleRevokeRequest, _go.UserRoleRevokeResponse](
+			httpClient,
+			baseURL+UserServiceRevokeRoleProcedure,
+			opts...,
+		),
+	}
+}
+
+// userServiceClient implements UserServiceClient.
+type userServiceClient struct {
+	findUser       *connect.Client[_go.UserFindOneRequest, _go.UserFindOneResponse]
+	createUser     *connect.Client[_go.UserCreateRequest, _go.UserCreateResponse]
+	updateUser     *connect.Client[_go.UserUpdateRequest, _go.UserUpdateResponse]
+	deleteUser     *connect.Client[_go.UserDeleteRequest, _go.UserDeleteResponse]
+	restoreUser    *connect.Client[_go.UserRestoreRequest, _go.UserRestoreResponse]
+	changePassword *connect.Client[_go.UserChangePasswordRequest, _go.UserChangePasswordResponse]
+	changeUsername *connect.Client[_go.UserChangeUsernameRequest, _go.UserChangeUsernameResponse]
+	changeEmail    *connect.Client[_go.UserChangeEmailRequest, _go.UserChangeEmailResponse]
+	findRoleGrants *connect.Client[_go.UserRoleGrantFindManyRequest, _go.UserRoleGrantFindManyResponse]
+	grantRole      *connect.Client[_go.UserRoleGrantRequest, _go.UserRoleGrantResponse]
+	revokeRole     *connect.Client[_go.UserRoleRevokeRequest, _go.UserRoleRevokeResponse]
+}
+
+// FindUser calls encedeus.panel.v1.UserService.FindUser.
+func (c *userServiceClient) FindUser(ctx context.Context, req *connect.Request[_go.UserFindOneRequest]) (*connect.Response[_go.UserFindOneResponse], error) {
+	return c.findUser.CallUnary(ctx, req)
+}
+
+// CreateUser calls encedeus.panel.v1.UserService.CreateUser.
+func (c *userServiceClient) CreateUser(ctx context.Context, req *connect.Request[_go.UserCreateRequest]) (*connect.Response[_go.UserCreateResponse], error) {
+	return c.createUser.CallUnary(ctx, req)
+}
+
+// UpdateUser calls encedeus.panel.v1.UserService.UpdateUser.
+func (c *userServiceClient) UpdateUser(ctx context.Context, req *connect.Request[_go.UserUpdateRequest]) (*connect.Response[_go.UserUpdateResponse], error) {
+	return c.updateUser.CallUnary(ctx, req)
+}
+
+// DeleteUser calls encedeus.panel.v1.UserService.DeleteUser.
+func (c *userServiceClient) DeleteUser(ctx context.Context, req *connect.Request[_go.UserDeleteRequest]) (*connect.Response[_go.UserDeleteResponse], error) {
+	return c.deleteUser.CallUnary(ctx, req)
+}
+
+// RestoreUser calls encedeus.panel.v1.UserService.RestoreUser.
+func (c *userServiceClient) RestoreUser(ctx context.Context, req *connect.Request[_go.UserRestoreRequest]) (*connect.Response[_go.UserRestoreResponse], error) {
+	return c.restoreUser.CallUnary(ctx, req)
+}
+
+// ChangePassword calls encedeus.panel.v1.UserService.ChangePassword.
+func (c *userServiceClient) ChangePassword(ctx context.Context, req *connect.Request[_go.UserChangePasswordRequest]) (*connect.Response[_go.UserChangePasswordResponse], error) {
+	return c.changePassword.CallUnary(ctx, req)
+}
+
+// ChangeUsername calls encedeus.panel.v1.UserService.ChangeUsername.
+func (c *userServiceClient) ChangeUsername(ctx context.Context, req *connect.Request[_go.UserChangeUsernameRequest]) (*connect.Response[_go.UserChangeUsernameResponse], error) {
+	return c.changeUsername.CallUnary(ctx, req)
+}
+
+// ChangeEmail calls encedeus.panel.v1.UserService.ChangeEmail.
+func (c *userServiceClient) ChangeEmail(ctx context.Context, req *connect.Request[_go.UserChangeEmailRequest]) (*connect.Response[_go.UserChangeEmailResponse], error) {
+	return c.changeEmail.CallUnary(ctx, req)
+}
+
+// FindRoleGrants calls encedeus.panel.v1.UserService.FindRoleGrants.
+func (c *userServiceClient) FindRoleGrants(ctx context.Context, req *connect.Request[_go.UserRoleGrantFindManyRequest]) (*connect.Response[_go.UserRoleGrantFindManyResponse], error) {
+	return c.findRoleGrants.CallUnary(ctx, req)
+}
+
+// GrantRole calls encedeus.panel.v1.UserService.GrantRole.
+func (c *userServiceClient) GrantRole(ctx context.Context, req *connect.Request[_go.UserRoleGrantRequest]) (*connect.Response[_go.UserRoleGrantResponse], error) {
+	return c.grantRole.CallUnary(ctx, req)
+}
+
+// RevokeRole calls encedeus.panel.v1.UserService.RevokeRole.
+func (c *userServiceClient) RevokeRole(ctx context.Context, req *connect.Request[_go.UserRoleRevokeRequest]) (*connect.Response[_go.UserRoleRevokeResponse], error) {
+	return c.revokeRole.CallUnary(ctx, req)
+}
+
+// UserServiceHandler is an implementation of the encedeus.panel.v1.UserService service.
+type UserServiceHandler interface {
+	FindUser(context.Context, *connect.Request[_go.UserFindOneRequest]) (*connect.Response[_go.UserFindOneResponse], error)
+	CreateUser(context.Context, *connect.Request[_go.UserCreateRequest]) (*connect.Response[_go.UserCreateResponse], error)
+	UpdateUser(context.Context, *connect.Request[_go.UserUpdateRequest]) (*connect.Response[_go.UserUpdateResponse], error)
+	DeleteUser(context.Context, *connect.Request[_go.UserDeleteRequest]) (*connect.Response[_go.UserDeleteResponse], error)
+	RestoreUser(context.Context, *connect.Request[_go.UserRestoreRequest]) (*connect.Response[_go.UserRestoreResponse], error)
+	// the change RPCs act on the authenticated user, the user_id of the request is ignored
+	ChangePassword(context.Context, *connect.Request[_go.UserChangePasswordRequest]) (*connect.Response[_go.UserChangePasswordResponse], error)
+	ChangeUsername(context.Context, *connect.Request[_go.UserChangeUsernameRequest]) (*connect.Response[_go.UserChangeUsernameResponse], error)
+	ChangeEmail(context.Context, *connect.Request[_go.UserChangeEmailRequest]) (*connect.Response[_go.UserChangeEmailResponse], error)
+	FindRoleGrants(context.Context, *connect.Request[_go.UserRoleGrantFindManyRequest]) (*connect.Response[_go.UserRoleGrantFindManyResponse], error)
+	GrantRole(context.Context, *connect.Request[_go.UserRoleGrantRequest]) (*connect.Response[_go.UserRoleGrantResponse], error)
+	RevokeRole(context.Context, *connect.Request[_go.UserRoleRevokeRequest]) (*connect.Response[_go.UserRoleRevokeResponse], error)
+}
+
+// NewUserServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewUserServiceHandler(svc UserServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	userServiceFindUserHandler := connect.NewUnaryHandler(
+		UserServiceFindUserProcedure,
+		svc.FindUser,
+		opts...,
+	)
+	userServiceCreateUserHandler := connect.NewUnaryHandler(
+		UserServiceCreateUserProcedure,
+		svc.CreateUser,
+		opts...,
+	)
+	userServiceUpdateUserHandler := connect.NewUnaryHandler(
+		UserServiceUpdateUserProcedure,
+		svc.UpdateUser,
+		opts...,
+	)
+	userServiceDeleteUserHandler := connect.NewUnaryHandler(
+		UserServiceDeleteUserProcedure,
+		svc.DeleteUser,
+		opts...,
+	)
+	userServiceRestoreUserHandler := connect.NewUnaryHandler(
+		UserServiceRestoreUserProcedure,
+		svc.RestoreUser,
+		opts...,
+	)
+	userServiceChangePasswordHandler := connect.NewUnaryHandler(
+		UserServiceChangePasswordProcedure,
+		svc.ChangePassword,
+		opts...,
+	)
+	userServiceChangeUsernameHandler := connect.NewUnaryHandler(
+		UserServiceChangeUsernameProcedure,
+		svc.ChangeUsername,
+		opts...,
+	)
+	userServiceChangeEmailHandler := connect.NewUnaryHandler(
+		UserServiceChangeEmailProcedure,
+		svc.ChangeEmail,
+		opts...,
+	)
+	userServiceFindRoleGrantsHandler := connect.NewUnaryHandler(
+		UserServiceFindRoleGrantsProcedure,
+		svc.FindRoleGrants,
+		opts...,
+	)
+	userServiceGrantRoleHandler := connect.NewUnaryHandler(
+		UserServiceGrantRoleProcedure,
+		svc.GrantRole,
+		opts...,
+	)
+	userServiceRevokeRoleHandler := connect.NewUnaryHandler(
+		UserServiceRevokeRoleProcedure,
+		svc.RevokeRole,
+		opts...,
+	)
+	return "/encedeus.panel.v1.UserService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case UserServiceFindUserProcedure:
+			userServiceFindUserHandler.ServeHTTP(w, r)
+		case UserServiceCreateUserProcedure:
+			userServiceCreateUserHandler.ServeHTTP(w, r)
+		case UserServiceUpdateUserProcedure:
+			userServiceUpdateUserHandler.ServeHTTP(w, r)
+		case UserServiceDeleteUserProcedure:
+			userServiceDeleteUserHandler.ServeHTTP(w, r)
+		case UserServiceRestoreUserProcedure:
+			userServiceRestoreUserHandler.ServeHTTP(w, r)
+		case UserServiceChangePasswordProcedure:
+			userServiceChangePasswordHandler.ServeHTTP(w, r)
+		case UserServiceChangeUsernameProcedure:
+			userServiceChangeUsernameHandler.ServeHTTP(w, r)
+		case UserServiceChangeEmailProcedure:
+			userServiceChangeEmailHandler.ServeHTTP(w, r)
+		case UserServiceFindRoleGrantsProcedure:
+			userServiceFindRoleGrantsHandler.ServeHTTP(w, r)
+		case UserServiceGrantRoleProcedure:
+			userServiceGrantRoleHandler.ServeHTTP(w, r)
+		case UserServiceRevokeRoleProcedure:
+			userServiceRevokeRoleHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedUserServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedUserServiceHandler struct{}
+
+func (UnimplementedUserServiceHandler) FindUser(context.Context, *connect.Request[_go.UserFindOneRequest]) (*connect.Response[_go.UserFindOneResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.UserService.FindUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) CreateUser(context.Context, *connect.Request[_go.UserCreateRequest]) (*connect.Response[_go.UserCreateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.UserService.CreateUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) UpdateUser(context.Context, *connect.Request[_go.UserUpdateRequest]) (*connect.Response[_go.UserUpdateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.UserService.UpdateUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) DeleteUser(context.Context, *connect.Request[_go.UserDeleteRequest]) (*connect.Response[_go.UserDeleteResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.UserService.DeleteUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) RestoreUser(context.Context, *connect.Request[_go.UserRestoreRequest]) (*connect.Response[_go.UserRestoreResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.UserService.RestoreUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) ChangePassword(context.Context, *connect.Request[_go.UserChangePasswordRequest]) (*connect.Response[_go.UserChangePasswordResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.UserService.ChangePassword is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) ChangeUsername(context.Context, *connect.Request[_go.UserChangeUsernameRequest]) (*connect.Response[_go.UserChangeUsernameResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.UserService.ChangeUsername is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) ChangeEmail(context.Context, *connect.Request[_go.UserChangeEmailRequest]) (*connect.Response[_go.UserChangeEmailResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.UserService.ChangeEmail is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) FindRoleGrants(context.Context, *connect.Request[_go.UserRoleGrantFindManyRequest]) (*connect.Response[_go.UserRoleGrantFindManyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.UserService.FindRoleGrants is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) GrantRole(context.Context, *connect.Request[_go.UserRoleGrantRequest]) (*connect.Response[_go.UserRoleGrantResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.UserService.GrantRole is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) RevokeRole(context.Context, *connect.Request[_go.UserRoleRevokeRequest]) (*connect.Response[_go.UserRoleRevokeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.UserService.RevokeRole is not implemented"))
+}
+
+// RoleServiceClient is a client for the encedeus.panel.v1.RoleService service.
+type RoleServiceClient interface {
+	FindRole(context.Context, *connect.Request[_go.RoleFindOneRequest]) (*connect.Response[_go.RoleFindOneResponse], error)
+	FindRoleEffectivePermissions(context.Context, *connect.Request[_go.RoleFindOneRequest]) (*connect.Response[_go.RoleEffectivePermissionsResponse], error)
+	CreateRole(context.Context, *connect.Request[_go.RoleCreateRequest]) (*connect.Response[_go.RoleCreateResponse], error)
+	UpdateRole(context.Context, *connect.Request[_go.RoleUpdateRequest]) (*connect.Response[_go.RoleUpdateResponse], error)
+	DeleteRole(context.Context, *connect.Request[_go.RoleDeleteRequest]) (*connect.Response[_go.RoleDeleteResponse], error)
+	RestoreRole(context.Context, *connect.Request[_go.RoleRestoreRequest]) (*connect.Response[_go.RoleRestoreResponse], error)
+}
+
+// NewRoleServiceClient constructs a client for the encedeus.panel.v1.RoleService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewRoleServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) RoleServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &roleServiceClient{
+		findRole: connect.NewClient[_go.RoleFindOneRequest, _go.RoleFindOneResponse](
+			httpClient,
+			baseURL+RoleServiceFindRoleProcedure,
+			opts...,
+		),
+		findRoleEffectivePermissions: connect.NewClient[_go.RoleFindOneRequest, _go.RoleEffectivePermissionsResponse](
+			httpClient,
+			baseURL+RoleServiceFindRoleEffectivePermissionsProcedure,
+			opts...,
+		),
+		createRole: connect.NewClient[_go.RoleCreateRequest, _go.RoleCreateResponse](
+			httpClient,
+			baseURL+RoleServiceCreateRoleProcedure,
+			opts...,
+		),
+		updateRole: connect.NewClient[_go.RoleUpdateRequest, _go.RoleUpdateResponse](
+			httpClient,
+			baseURL+RoleServiceUpdateRoleProcedure,
+			opts...,
+		),
+		deleteRole: connect.NewClient[_go.RoleDeleteRequest, _go.RoleDeleteResponse](
+			httpClient,
+			baseURL+RoleServiceDeleteRoleProcedure,
+			opts...,
+		),
+		restoreRole: connect.NewClient[_go.RoleRestoreRequest, _go.RoleRestoreResponse](
+			httpClient,
+			baseURL+RoleServiceRestoreRoleProcedure,
+			opts...,
+		),
+	}
+}
+
+// roleServiceClient implements RoleServiceClient.
+type roleServiceClient struct {
+	findRole                     *connect.Client[_go.RoleFindOneRequest, _go.RoleFindOneResponse]
+	findRoleEffectivePermissions *connect.Client[_go.RoleFindOneRequest, _go.RoleEffectivePermissionsResponse]
+	createRole                   *connect.Client[_go.RoleCreateRequest, _go.RoleCreateResponse]
+	updateRole                   *connect.Client[_go.RoleUpdateRequest, _go.RoleUpdateResponse]
+	deleteRole                   *connect.Client[_go.RoleDeleteRequest, _go.RoleDeleteResponse]
+	restoreRole                  *connect.Client[_go.RoleRestoreRequest, _go.RoleRestoreResponse]
+}
+
+// FindRole calls encedeus.panel.v1.RoleService.FindRole.
+func (c *roleServiceClient) FindRole(ctx context.Context, req *connect.Request[_go.RoleFindOneRequest]) (*connect.Response[_go.RoleFindOneResponse], error) {
+	return c.findRole.CallUnary(ctx, req)
+}
+
+// FindRoleEffectivePermissions calls encedeus.panel.v1.RoleService.FindRoleEffectivePermissions.
+func (c *roleServiceClient) FindRoleEffectivePermissions(ctx context.Context, req *connect.Request[_go.RoleFindOneRequest]) (*connect.Response[_go.RoleEffectivePermissionsResponse], error) {
+	return c.findRoleEffectivePermissions.CallUnary(ctx, req)
+}
+
+// CreateRole calls encedeus.panel.v1.RoleService.CreateRole.
+func (c *roleServiceClient) CreateRole(ctx context.Context, req *connect.Request[_go.RoleCreateRequest]) (*connect.Response[_go.RoleCreateResponse], error) {
+	return c.createRole.CallUnary(ctx, req)
+}
+
+// UpdateRole calls encedeus.panel.v1.RoleService.UpdateRole.
+func (c *roleServiceClient) UpdateRole(ctx context.Context, req *connect.Request[_go.RoleUpdateRequest]) (*connect.Response[_go.RoleUpdateResponse], error) {
+	return c.updateRole.CallUnary(ctx, req)
+}
+
+// DeleteRole calls encedeus.panel.v1.RoleService.DeleteRole.
+func (c *roleServiceClient) DeleteRole(ctx context.Context, req *connect.Request[_go.RoleDeleteRequest]) (*connect.Response[_go.RoleDeleteResponse], error) {
+	return c.deleteRole.CallUnary(ctx, req)
+}
+
+// RestoreRole calls encedeus.panel.v1.RoleService.RestoreRole.
+func (c *roleServiceClient) RestoreRole(ctx context.Context, req *connect.Request[_go.RoleRestoreRequest]) (*connect.Response[_go.RoleRestoreResponse], error) {
+	return c.restoreRole.CallUnary(ctx, req)
+}
+
+// RoleServiceHandler is an implementation of the encedeus.panel.v1.RoleService service.
+type RoleServiceHandler interface {
+	FindRole(context.Context, *connect.Request[_go.RoleFindOneRequest]) (*connect.Response[_go.RoleFindOneResponse], error)
+	FindRoleEffectivePermissions(context.Context, *connect.Request[_go.RoleFindOneRequest]) (*connect.Response[_go.RoleEffectivePermissionsResponse], error)
+	CreateRole(context.Context, *connect.Request[_go.RoleCreateRequest]) (*connect.Response[_go.RoleCreateResponse], error)
+	UpdateRole(context.Context, *connect.Request[_go.RoleUpdateRequest]) (*connect.Response[_go.RoleUpdateResponse], error)
+	DeleteRole(context.Context, *connect.Request[_go.RoleDeleteRequest]) (*connect.Response[_go.RoleDeleteResponse], error)
+	RestoreRole(context.Context, *connect.Request[_go.RoleRestoreRequest]) (*connect.Response[_go.RoleRestoreResponse], error)
+}
+
+// NewRoleServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewRoleServiceHandler(svc RoleServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	roleServiceFindRoleHandler := connect.NewUnaryHandler(
+		RoleServiceFindRoleProcedure,
+		svc.FindRole,
+		opts...,
+	)
+	roleServiceFindRoleEffectivePermissionsHandler := connect.NewUnaryHandler(
+		RoleServiceFindRoleEffectivePermissionsProcedure,
+		svc.FindRoleEffectivePermissions,
+		opts...,
+	)
+	roleServiceCreateRoleHandler := connect.NewUnaryHandler(
+		RoleServiceCreateRoleProcedure,
+		svc.CreateRole,
+		opts...,
+	)
+	roleServiceUpdateRoleHandler := connect.NewUnaryHandler(
+		RoleServiceUpdateRoleProcedure,
+		svc.UpdateRole,
+		opts...,
+	)
+	roleServiceDeleteRoleHandler := connect.NewUnaryHandler(
+		RoleServiceDeleteRoleProcedure,
+		svc.DeleteRole,
+		opts...,
+	)
+	roleServiceRestoreRoleHandler := connect.NewUnaryHandler(
+		RoleServiceRestoreRoleProcedure,
+		svc.RestoreRole,
+		opts...,
+	)
+	return "/encedeus.panel.v1.RoleService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case RoleServiceFindRoleProcedure:
+			roleServiceFindRoleHandler.ServeHTTP(w, r)
+		case RoleServiceFindRoleEffectivePermissionsProcedure:
+			roleServiceFindRoleEffectivePermissionsHandler.ServeHTTP(w, r)
+		case RoleServiceCreateRoleProcedure:
+			roleServiceCreateRoleHandler.ServeHTTP(w, r)
+		case RoleServiceUpdateRoleProcedure:
+			roleServiceUpdateRoleHandler.ServeHTTP(w, r)
+		case RoleServiceDeleteRoleProcedure:
+			roleServiceDeleteRoleHandler.ServeHTTP(w, r)
+		case RoleServiceRestoreRoleProcedure:
+			roleServiceRestoreRoleHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedRoleServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedRoleServiceHandler struct{}
+
+func (UnimplementedRoleServiceHandler) FindRole(context.Context, *connect.Request[_go.RoleFindOneRequest]) (*connect.Response[_go.RoleFindOneResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.RoleService.FindRole is not implemented"))
+}
+
+func (UnimplementedRoleServiceHandler) FindRoleEffectivePermissions(context.Context, *connect.Request[_go.RoleFindOneRequest]) (*connect.Response[_go.RoleEffectivePermissionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.RoleService.FindRoleEffectivePermissions is not implemented"))
+}
+
+func (UnimplementedRoleServiceHandler) CreateRole(context.Context, *connect.Request[_go.RoleCreateRequest]) (*connect.Response[_go.RoleCreateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.RoleService.CreateRole is not implemented"))
+}
+
+func (UnimplementedRoleServiceHandler) UpdateRole(context.Context, *connect.Request[_go.RoleUpdateRequest]) (*connect.Response[_go.RoleUpdateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.RoleService.UpdateRole is not implemented"))
+}
+
+func (UnimplementedRoleServiceHandler) DeleteRole(context.Context, *connect.Request[_go.RoleDeleteRequest]) (*connect.Response[_go.RoleDeleteResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.RoleService.DeleteRole is not implemented"))
+}
+
+func (UnimplementedRoleServiceHandler) RestoreRole(context.Context, *connect.Request[_go.RoleRestoreRequest]) (*connect.Response[_go.RoleRestoreResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.RoleService.RestoreRole is not implemented"))
+}
+
+// ApiKeyServiceClient is a client for the encedeus.panel.v1.ApiKeyService service.
+type ApiKeyServiceClient interface {
+	CreateAccountAPIKey(context.Context, *connect.Request[_go.AccountAPIKeyCreateRequest]) (*connect.Response[_go.AccountAPIKeyCreateResponse], error)
+	DeleteAccountAPIKey(context.Context, *connect.Request[_go.AccountAPIKeyDeleteRequest]) (*connect.Response[_go.AccountAPIKeyDeleteResponse], error)
+	FindAccountAPIKeysByUser(context.Context, *connect.Request[_go.AccountAPIKeyFindManyByUserRequest]) (*connect.Response[_go.AccountAPIKeyFindManyResponse], error)
+}
+
+// NewApiKeyServiceClient constructs a client for the encedeus.panel.v1.ApiKeyService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewApiKeyServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) ApiKeyServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &apiKeyServiceClient{
+		createAccountAPIKey: connect.NewClient[_go.AccountAPIKeyCreateRequest, _go.AccountAPIKeyCreateResponse](
+			httpClient,
+			baseURL+ApiKeyServiceCreateAccountAPIKeyProcedure,
+			opts...,
+		),
+		deleteAccountAPIKey: connect.NewClient[_go.AccountAPIKeyDeleteRequest, _go.AccountAPIKeyDeleteResponse](
+			httpClient,
+			baseURL+ApiKeyServiceDeleteAccountAPIKeyProcedure,
+			opts...,
+		),
+		findAccountAPIKeysByUser: connect.NewClient[_go.AccountAPIKeyFindManyByUserRequest, _go.AccountAPIKeyFindManyResponse](
+			httpClient,
+			baseURL+ApiKeyServiceFindAccountAPIKeysByUserProcedure,
+			opts...,
+		),
+	}
+}
+
+// apiKeyServiceClient implements ApiKeyServiceClient.
+type apiKeyServiceClient struct {
+	createAccountAPIKey      *connect.Client[_go.AccountAPIKeyCreateRequest, _go.AccountAPIKeyCreateResponse]
+	deleteAccountAPIKey      *connect.Client[_go.AccountAPIKeyDeleteRequest, _go.AccountAPIKeyDeleteResponse]
+	findAccountAPIKeysByUser *connect.Client[_go.AccountAPIKeyFindManyByUserRequest, _go.AccountAPIKeyFindManyResponse]
+}
+
+// CreateAccountAPIKey calls encedeus.panel.v1.ApiKeyService.CreateAccountAPIKey.
+func (c *apiKeyServiceClient) CreateAccountAPIKey(ctx context.Context, req *connect.Request[_go.AccountAPIKeyCreateRequest]) (*connect.Response[_go.AccountAPIKeyCreateResponse], error) {
+	return c.createAccountAPIKey.CallUnary(ctx, req)
+}
+
+// DeleteAccountAPIKey calls encedeus.panel.v1.ApiKeyService.DeleteAccountAPIKey.
+func (c *apiKeyServiceClient) DeleteAccountAPIKey(ctx context.Context, req *connect.Request[_go.AccountAPIKeyDeleteRequest]) (*connect.Response[_go.AccountAPIKeyDeleteResponse], error) {
+	return c.deleteAccountAPIKey.CallUnary(ctx, req)
+}
+
+// FindAccountAPIKeysByUser calls encedeus.panel.v1.ApiKeyService.FindAccountAPIKeysByUser.
+func (c *apiKeyServiceClient) FindAccountAPIKeysByUser(ctx context.Context, req *connect.Request[_go.AccountAPIKeyFindManyByUserRequest]) (*connect.Response[_go.AccountAPIKeyFindManyResponse], error) {
+	return c.findAccountAPIKeysByUser.CallUnary(ctx, req)
+}
+
+// ApiKeyServiceHandler is an implementation of the encedeus.panel.v1.ApiKeyService service.
+type ApiKeyServiceHandler interface {
+	CreateAccountAPIKey(context.Context, *connect.Request[_go.AccountAPIKeyCreateRequest]) (*connect.Response[_go.AccountAPIKeyCreateResponse], error)
+	DeleteAccountAPIKey(context.Context, *connect.Request[_go.AccountAPIKeyDeleteRequest]) (*connect.Response[_go.AccountAPIKeyDeleteResponse], error)
+	FindAccountAPIKeysByUser(context.Context, *connect.Request[_go.AccountAPIKeyFindManyByUserRequest]) (*connect.Response[_go.AccountAPIKeyFindManyResponse], error)
+}
+
+// NewApiKeyServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewApiKeyServiceHandler(svc ApiKeyServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	apiKeyServiceCreateAccountAPIKeyHandler := connect.NewUnaryHandler(
+		ApiKeyServiceCreateAccountAPIKeyProcedure,
+		svc.CreateAccountAPIKey,
+		opts...,
+	)
+	apiKeyServiceDeleteAccountAPIKeyHandler := connect.NewUnaryHandler(
+		ApiKeyServiceDeleteAccountAPIKeyProcedure,
+		svc.DeleteAccountAPIKey,
+		opts...,
+	)
+	apiKeyServiceFindAccountAPIKeysByUserHandler := connect.NewUnaryHandler(
+		ApiKeyServiceFindAccountAPIKeysByUserProcedure,
+		svc.FindAccountAPIKeysByUser,
+		opts...,
+	)
+	return "/encedeus.panel.v1.ApiKeyService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ApiKeyServiceCreateAccountAPIKeyProcedure:
+			apiKeyServiceCreateAccountAPIKeyHandler.ServeHTTP(w, r)
+		case ApiKeyServiceDeleteAccountAPIKeyProcedure:
+			apiKeyServiceDeleteAccountAPIKeyHandler.ServeHTTP(w, r)
+		case ApiKeyServiceFindAccountAPIKeysByUserProcedure:
+			apiKeyServiceFindAccountAPIKeysByUserHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedApiKeyServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedApiKeyServiceHandler struct{}
+
+func (UnimplementedApiKeyServiceHandler) CreateAccountAPIKey(context.Context, *connect.Request[_go.AccountAPIKeyCreateRequest]) (*connect.Response[_go.AccountAPIKeyCreateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.ApiKeyService.CreateAccountAPIKey is not implemented"))
+}
+
+func (UnimplementedApiKeyServiceHandler) DeleteAccountAPIKey(context.Context, *connect.Request[_go.AccountAPIKeyDeleteRequest]) (*connect.Response[_go.AccountAPIKeyDeleteResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.ApiKeyService.DeleteAccountAPIKey is not implemented"))
+}
+
+func (UnimplementedApiKeyServiceHandler) FindAccountAPIKeysByUser(context.Context, *connect.Request[_go.AccountAPIKeyFindManyByUserRequest]) (*connect.Response[_go.AccountAPIKeyFindManyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("encedeus.panel.v1.ApiKeyService.FindAccountAPIKeysByUser is not implemented"))
+}
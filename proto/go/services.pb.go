@@ -0,0 +1,256 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: services.proto
+
+// The services are served over gRPC, gRPC-Web and Connect next to the REST API, on the same address.
+// Every RPC but the ones of AuthService needs an access token or account API key in the Authorization header.
+
+package protoapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_services_proto protoreflect.FileDescriptor
+
+var file_services_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x11, 0x65, 0x6e, 0x63, 0x65, 0x64, 0x65, 0x75, 0x73, 0x2e, 0x70, 0x61, 0x6e, 0x65, 0x6c,
+	0x2e, 0x76, 0x31, 0x1a, 0x0e, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x0e, 0x72, 0x6f, 0x6c, 0x65, 0x5f, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x11, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x61, 0x70, 0x69,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0x8f, 0x01, 0x0a, 0x0b, 0x41, 0x75, 0x74, 0x68, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e,
+	0x12, 0x12, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x49,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x12, 0x52, 0x65, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
+	0x1a, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x41, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xc7, 0x05, 0x0a, 0x0b, 0x55, 0x73, 0x65,
+	0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x46, 0x69, 0x6e, 0x64,
+	0x55, 0x73, 0x65, 0x72, 0x12, 0x13, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x46, 0x69, 0x6e, 0x64, 0x4f,
+	0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x55, 0x73, 0x65, 0x72,
+	0x46, 0x69, 0x6e, 0x64, 0x4f, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x35, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x13, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a,
+	0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x13, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x55,
+	0x73, 0x65, 0x72, 0x12, 0x13, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49,
+	0x0a, 0x0e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
+	0x12, 0x1a, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x55,
+	0x73, 0x65, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0e, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x45, 0x6d,
+	0x61, 0x69, 0x6c, 0x12, 0x17, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x45, 0x6d, 0x61, 0x69, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x55,
+	0x73, 0x65, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0e, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x6f,
+	0x6c, 0x65, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x1d, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52,
+	0x6f, 0x6c, 0x65, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61, 0x6e, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x6f,
+	0x6c, 0x65, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61, 0x6e, 0x79, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x09, 0x47, 0x72, 0x61, 0x6e, 0x74,
+	0x52, 0x6f, 0x6c, 0x65, 0x12, 0x15, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x47,
+	0x72, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x0a, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x52, 0x6f, 0x6c,
+	0x65, 0x12, 0x16, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x6f, 0x6c, 0x65, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x32, 0xfb, 0x02, 0x0a, 0x0b, 0x52, 0x6f, 0x6c, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x13,
+	0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x46, 0x69, 0x6e, 0x64, 0x4f, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x46, 0x69, 0x6e, 0x64, 0x4f, 0x6e,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x56, 0x0a, 0x1c, 0x46, 0x69, 0x6e,
+	0x64, 0x52, 0x6f, 0x6c, 0x65, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x50, 0x65,
+	0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x13, 0x2e, 0x52, 0x6f, 0x6c, 0x65,
+	0x46, 0x69, 0x6e, 0x64, 0x4f, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21,
+	0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x50, 0x65,
+	0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x35, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6c, 0x65, 0x12,
+	0x12, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x12, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x52, 0x6f, 0x6c,
+	0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x35, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x12, 0x2e,
+	0x52, 0x6f, 0x6c, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x13, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x13, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x52, 0x6f, 0x6c,
+	0x65, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x32, 0x94, 0x02, 0x0a, 0x0d, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x50, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x12, 0x1b, 0x2e, 0x41, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x12, 0x1b, 0x2e, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x18, 0x46, 0x69, 0x6e, 0x64, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x73, 0x42, 0x79, 0x55, 0x73,
+	0x65, 0x72, 0x12, 0x23, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x4b,
+	0x65, 0x79, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61, 0x6e, 0x79, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61, 0x6e, 0x79, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0f, 0x5a, 0x0d, 0x2e, 0x2f, 0x67, 0x6f, 0x3b,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var file_services_proto_goTypes = []interface{}{
+	(*UserSignInRequest)(nil),                  // 0: UserSignInRequest
+	(*AccessTokenRefreshRequest)(nil),          // 1: AccessTokenRefreshRequest
+	(*UserFindOneRequest)(nil),                 // 2: UserFindOneRequest
+	(*UserCreateRequest)(nil),                  // 3: UserCreateRequest
+	(*UserUpdateRequest)(nil),                  // 4: UserUpdateRequest
+	(*UserDeleteRequest)(nil),                  // 5: UserDeleteRequest
+	(*UserRestoreRequest)(nil),                 // 6: UserRestoreRequest
+	(*UserChangePasswordRequest)(nil),          // 7: UserChangePasswordRequest
+	(*UserChangeUsernameRequest)(nil),          // 8: UserChangeUsernameRequest
+	(*UserChangeEmailRequest)(nil),             // 9: UserChangeEmailRequest
+	(*UserRoleGrantFindManyRequest)(nil),       // 10: UserRoleGrantFindManyRequest
+	(*UserRoleGrantRequest)(nil),               // 11: UserRoleGrantRequest
+	(*UserRoleRevokeRequest)(nil),              // 12: UserRoleRevokeRequest
+	(*RoleFindOneRequest)(nil),                 // 13: RoleFindOneRequest
+	(*RoleCreateRequest)(nil),                  // 14: RoleCreateRequest
+	(*RoleUpdateRequest)(nil),                  // 15: RoleUpdateRequest
+	(*RoleDeleteRequest)(nil),                  // 16: RoleDeleteRequest
+	(*RoleRestoreRequest)(nil),                 // 17: RoleRestoreRequest
+	(*AccountAPIKeyCreateRequest)(nil),         // 18: AccountAPIKeyCreateRequest
+	(*AccountAPIKeyDeleteRequest)(nil),         // 19: AccountAPIKeyDeleteRequest
+	(*AccountAPIKeyFindManyByUserRequest)(nil), // 20: AccountAPIKeyFindManyByUserRequest
+	(*UserSignInResponse)(nil),                 // 21: UserSignInResponse
+	(*AccessTokenRefreshResponse)(nil),         // 22: AccessTokenRefreshResponse
+	(*UserFindOneResponse)(nil),                // 23: UserFindOneResponse
+	(*UserCreateResponse)(nil),                 // 24: UserCreateResponse
+	(*UserUpdateResponse)(nil),                 // 25: UserUpdateResponse
+	(*UserDeleteResponse)(nil),                 // 26: UserDeleteResponse
+	(*UserRestoreResponse)(nil),                // 27: UserRestoreResponse
+	(*UserChangePasswordResponse)(nil),         // 28: UserChangePasswordResponse
+	(*UserChangeUsernameResponse)(nil),         // 29: UserChangeUsernameResponse
+	(*UserChangeEmailResponse)(nil),            // 30: UserChangeEmailResponse
+	(*UserRoleGrantFindManyResponse)(nil),      // 31: UserRoleGrantFindManyResponse
+	(*UserRoleGrantResponse)(nil),              // 32: UserRoleGrantResponse
+	(*UserRoleRevokeResponse)(nil),             // 33: UserRoleRevokeResponse
+	(*RoleFindOneResponse)(nil),                // 34: RoleFindOneResponse
+	(*RoleEffectivePermissionsResponse)(nil),   // 35: RoleEffectivePermissionsResponse
+	(*RoleCreateResponse)(nil),                 // 36: RoleCreateResponse
+	(*RoleUpdateResponse)(nil),                 // 37: RoleUpdateResponse
+	(*RoleDeleteResponse)(nil),                 // 38: RoleDeleteResponse
+	(*RoleRestoreResponse)(nil),                // 39: RoleRestoreResponse
+	(*AccountAPIKeyCreateResponse)(nil),        // 40: AccountAPIKeyCreateResponse
+	(*AccountAPIKeyDeleteResponse)(nil),        // 41: AccountAPIKeyDeleteResponse
+	(*AccountAPIKeyFindManyResponse)(nil),      // 42: AccountAPIKeyFindManyResponse
+}
+var file_services_proto_depIdxs = []int32{
+	0,  // 0: encedeus.panel.v1.AuthService.SignIn:input_type -> UserSignInRequest
+	1,  // 1: encedeus.panel.v1.AuthService.RefreshAccessToken:input_type -> AccessTokenRefreshRequest
+	2,  // 2: encedeus.panel.v1.UserService.FindUser:input_type -> UserFindOneRequest
+	3,  // 3: encedeus.panel.v1.UserService.CreateUser:input_type -> UserCreateRequest
+	4,  // 4: encedeus.panel.v1.UserService.UpdateUser:input_type -> UserUpdateRequest
+	5,  // 5: encedeus.panel.v1.UserService.DeleteUser:input_type -> UserDeleteRequest
+	6,  // 6: encedeus.panel.v1.UserService.RestoreUser:input_type -> UserRestoreRequest
+	7,  // 7: encedeus.panel.v1.UserService.ChangePassword:input_type -> UserChangePasswordRequest
+	8,  // 8: encedeus.panel.v1.UserService.ChangeUsername:input_type -> UserChangeUsernameRequest
+	9,  // 9: encedeus.panel.v1.UserService.ChangeEmail:input_type -> UserChangeEmailRequest
+	10, // 10: encedeus.panel.v1.UserService.FindRoleGrants:input_type -> UserRoleGrantFindManyRequest
+	11, // 11: encedeus.panel.v1.UserService.GrantRole:input_type -> UserRoleGrantRequest
+	12, // 12: encedeus.panel.v1.UserService.RevokeRole:input_type -> UserRoleRevokeRequest
+	13, // 13: encedeus.panel.v1.RoleService.FindRole:input_type -> RoleFindOneRequest
+	13, // 14: encedeus.panel.v1.RoleService.FindRoleEffectivePermissions:input_type -> RoleFindOneRequest
+	14, // 15: encedeus.panel.v1.RoleService.CreateRole:input_type -> RoleCreateRequest
+	15, // 16: encedeus.panel.v1.RoleService.UpdateRole:input_type -> RoleUpdateRequest
+	16, // 17: encedeus.panel.v1.RoleService.DeleteRole:input_type -> RoleDeleteRequest
+	17, // 18: encedeus.panel.v1.RoleService.RestoreRole:input_type -> RoleRestoreRequest
+	18, // 19: encedeus.panel.v1.ApiKeyService.CreateAccountAPIKey:input_type -> AccountAPIKeyCreateRequest
+	19, // 20: encedeus.panel.v1.ApiKeyService.DeleteAccountAPIKey:input_type -> AccountAPIKeyDeleteRequest
+	20, // 21: encedeus.panel.v1.ApiKeyService.FindAccountAPIKeysByUser:input_type -> AccountAPIKeyFindManyByUserRequest
+	21, // 22: encedeus.panel.v1.AuthService.SignIn:output_type -> UserSignInResponse
+	22, // 23: encedeus.panel.v1.AuthService.RefreshAccessToken:output_type -> AccessTokenRefreshResponse
+	23, // 24: encedeus.panel.v1.UserService.FindUser:output_type -> UserFindOneResponse
+	24, // 25: encedeus.panel.v1.UserService.CreateUser:output_type -> UserCreateResponse
+	25, // 26: encedeus.panel.v1.UserService.UpdateUser:output_type -> UserUpdateResponse
+	26, // 27: encedeus.panel.v1.UserService.DeleteUser:output_type -> UserDeleteResponse
+	27, // 28: encedeus.panel.v1.UserService.RestoreUser:output_type -> UserRestoreResponse
+	28, // 29: encedeus.panel.v1.UserService.ChangePassword:output_type -> UserChangePasswordResponse
+	29, // 30: encedeus.panel.v1.UserService.ChangeUsername:output_type -> UserChangeUsernameResponse
+	30, // 31: encedeus.panel.v1.UserService.ChangeEmail:output_type -> UserChangeEmailResponse
+	31, // 32: encedeus.panel.v1.UserService.FindRoleGrants:output_type -> UserRoleGrantFindManyResponse
+	32, // 33: encedeus.panel.v1.UserService.GrantRole:output_type -> UserRoleGrantResponse
+	33, // 34: encedeus.panel.v1.UserService.RevokeRole:output_type -> UserRoleRevokeResponse
+	34, // 35: encedeus.panel.v1.RoleService.FindRole:output_type -> RoleFindOneResponse
+	35, // 36: encedeus.panel.v1.RoleService.FindRoleEffectivePermissions:output_type -> RoleEffectivePermissionsResponse
+	36, // 37: encedeus.panel.v1.RoleService.CreateRole:output_type -> RoleCreateResponse
+	37, // 38: encedeus.panel.v1.RoleService.UpdateRole:output_type -> RoleUpdateResponse
+	38, // 39: encedeus.panel.v1.RoleService.DeleteRole:output_type -> RoleDeleteResponse
+	39, // 40: encedeus.panel.v1.RoleService.RestoreRole:output_type -> RoleRestoreResponse
+	40, // 41: encedeus.panel.v1.ApiKeyService.CreateAccountAPIKey:output_type -> AccountAPIKeyCreateResponse
+	41, // 42: encedeus.panel.v1.ApiKeyService.DeleteAccountAPIKey:output_type -> AccountAPIKeyDeleteResponse
+	42, // 43: encedeus.panel.v1.ApiKeyService.FindAccountAPIKeysByUser:output_type -> AccountAPIKeyFindManyResponse
+	22, // [22:44] is the sub-list for method output_type
+	0,  // [0:22] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_services_proto_init() }
+func file_services_proto_init() {
+	if File_services_proto != nil {
+		return
+	}
+	file_auth_api_proto_init()
+	file_user_api_proto_init()
+	file_role_api_proto_init()
+	file_api_key_api_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_services_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   4,
+		},
+		GoTypes:           file_services_proto_goTypes,
+		DependencyIndexes: file_services_proto_depIdxs,
+	}.Build()
+	File_services_proto = out.File
+	file_services_proto_rawDesc = nil
+	file_services_proto_goTypes = nil
+	file_services_proto_depIdxs = nil
+}
@@ -8,7 +8,6 @@ import (
     "google.golang.org/protobuf/encoding/protojson"
     "google.golang.org/protobuf/proto"
     "google.golang.org/protobuf/types/known/timestamppb"
-    "net/http"
 )
 
 func ProtoUUIDToUUID(id *protoapi.UUID) uuid.UUID {
@@ -21,6 +20,29 @@ func UUIDToProtoUUID(id uuid.UUID) *protoapi.UUID {
     }
 }
 
+// ParseProtoUUIDs converts a list of proto UUIDs, failing on the first invalid one
+func ParseProtoUUIDs(ids []*protoapi.UUID) ([]uuid.UUID, error) {
+    parsed := make([]uuid.UUID, len(ids))
+    for i, id := range ids {
+        var err error
+        parsed[i], err = uuid.Parse(id.GetValue())
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    return parsed, nil
+}
+
+func UUIDsToProtoUUIDs(ids []uuid.UUID) []*protoapi.UUID {
+    protoIds := make([]*protoapi.UUID, len(ids))
+    for i, id := range ids {
+        protoIds[i] = UUIDToProtoUUID(id)
+    }
+
+    return protoIds
+}
+
 func EntUserEntityToProtoUser(user *ent.User) *protoapi.User {
     return &protoapi.User{
         Id:        UUIDToProtoUUID(user.ID),
@@ -30,6 +52,7 @@ func EntUserEntityToProtoUser(user *ent.User) *protoapi.User {
         RoleId:    UUIDToProtoUUID(user.RoleID),
         CreatedAt: timestamppb.New(user.CreatedAt),
         UpdatedAt: timestamppb.New(user.UpdatedAt),
+        Version:   user.Version,
     }
 }
 
@@ -43,18 +66,27 @@ func ProtoUserToEntUserEntity(user *protoapi.User) *ent.User {
         Email:     user.Email,
         Password:  user.Password,
         RoleID:    ProtoUUIDToUUID(user.RoleId),
+        Version:   user.Version,
     }
 }
 
 func EntRoleEntityToProtoRole(role *ent.Role) *protoapi.Role {
-    return &protoapi.Role{
+    protoRole := &protoapi.Role{
         Id:          UUIDToProtoUUID(role.ID),
         Name:        role.Name,
         CreatedAt:   timestamppb.New(role.CreatedAt),
         UpdatedAt:   timestamppb.New(role.UpdatedAt),
         DeletedAt:   timestamppb.New(role.DeletedAt),
         Permissions: role.Permissions,
+        Version:     role.Version,
+    }
+
+    // parents are only set if the edge was loaded
+    for _, parent := range role.Edges.Parents {
+        protoRole.ParentIds = append(protoRole.ParentIds, UUIDToProtoUUID(parent.ID))
     }
+
+    return protoRole
 }
 
 func ProtoRoleToEntRoleEntity(role *protoapi.Role) *ent.Role {
@@ -65,6 +97,7 @@ func ProtoRoleToEntRoleEntity(role *protoapi.Role) *ent.Role {
         UpdatedAt:   role.UpdatedAt.AsTime(),
         DeletedAt:   role.DeletedAt.AsTime(),
         Permissions: role.Permissions,
+        Version:     role.Version,
     }
 }
 
@@ -100,15 +133,70 @@ func EntAccountAPIKeyToProtoKey(key *ent.ApiKey) *protoapi.AccountAPIKey {
         IpAddresses: key.IPAddresses,
         UserId:      UUIDToProtoUUID(key.UserID),
         Key:         key.Key,
+        Version:     key.Version,
+    }
+}
+
+func EntRoleGrantToProtoRoleGrant(grant *ent.RoleGrant) *protoapi.RoleGrant {
+    protoGrant := &protoapi.RoleGrant{
+        Id:        UUIDToProtoUUID(grant.ID),
+        CreatedAt: timestamppb.New(grant.CreatedAt),
+        UserId:    UUIDToProtoUUID(grant.UserID),
+        RoleId:    UUIDToProtoUUID(grant.RoleID),
     }
+    if grant.ExpiresAt != nil {
+        protoGrant.ExpiresAt = timestamppb.New(*grant.ExpiresAt)
+    }
+    if grant.ExpiredAt != nil {
+        protoGrant.ExpiredAt = timestamppb.New(*grant.ExpiredAt)
+    }
+    if grant.GrantedBy != nil {
+        protoGrant.GrantedBy = UUIDToProtoUUID(*grant.GrantedBy)
+    }
+
+    return protoGrant
+}
+
+// EntWebhookToProtoWebhook leaves out the secret, it's only returned when it's generated
+func EntWebhookToProtoWebhook(webhook *ent.Webhook) *protoapi.Webhook {
+    return &protoapi.Webhook{
+        Id:          UUIDToProtoUUID(webhook.ID),
+        CreatedAt:   timestamppb.New(webhook.CreatedAt),
+        UpdatedAt:   timestamppb.New(webhook.UpdatedAt),
+        Url:         webhook.URL,
+        Events:      webhook.Events,
+        Description: webhook.Description,
+        Enabled:     webhook.Enabled,
+    }
+}
+
+func EntWebhookDeliveryToProtoWebhookDelivery(delivery *ent.WebhookDelivery) *protoapi.WebhookDelivery {
+    protoDelivery := &protoapi.WebhookDelivery{
+        Id:             UUIDToProtoUUID(delivery.ID),
+        CreatedAt:      timestamppb.New(delivery.CreatedAt),
+        WebhookId:      UUIDToProtoUUID(delivery.WebhookID),
+        EventId:        UUIDToProtoUUID(delivery.EventID),
+        Event:          delivery.Event,
+        Payload:        delivery.Payload,
+        Status:         delivery.Status.String(),
+        Attempts:       int32(delivery.Attempts),
+        ResponseStatus: int32(delivery.ResponseStatus),
+        Error:          delivery.Error,
+    }
+    if delivery.NextAttemptAt != nil {
+        protoDelivery.NextAttemptAt = timestamppb.New(*delivery.NextAttemptAt)
+    }
+    if delivery.DeliveredAt != nil {
+        protoDelivery.DeliveredAt = timestamppb.New(*delivery.DeliveredAt)
+    }
+
+    return protoDelivery
 }
 
 func MarshalControllerProtoResponseToJSON(c *echo.Context, okStatus int, message proto.Message) (err error) {
     json, err := protojson.Marshal(message)
     if err != nil {
-        return (*c).JSON(http.StatusInternalServerError, echo.Map{
-            "message": "internal server error",
-        })
+        return err
     }
 
     return (*c).JSONBlob(okStatus, json)
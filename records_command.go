@@ -0,0 +1,134 @@
+package main
+
+import (
+    "context"
+    "flag"
+    "fmt"
+    "github.com/Encedeus/panel/ent"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "io"
+    "log"
+    "os"
+    "path/filepath"
+    "text/tabwriter"
+)
+
+// runImport handles `panel user|role import`, reading the records of the file with read and importing them with
+// importRecords. It prints the report and exits with 1 if any record failed.
+func runImport[R any](ctx context.Context, db *ent.Client, resource string, args []string,
+    read func(r io.Reader, format services.Format) ([]R, error),
+    importRecords func(ctx context.Context, db *ent.Client, records []R, opts services.ImportOptions) (*protoapi.ImportReport, error)) {
+    fs := flag.NewFlagSet(resource+" import", flag.ExitOnError)
+    format := fs.String("format", "", "json or csv, guessed from the file extension if not set")
+    dryRun := fs.Bool("dry-run", false, "report what would be imported without writing anything")
+    bestEffort := fs.Bool("best-effort", false, "import the valid records even if others fail")
+    _ = fs.Parse(args)
+    if fs.NArg() != 1 {
+        log.Fatalf("usage: panel %s import [--format json|csv] [--dry-run] [--best-effort] <file or - for stdin>", resource)
+    }
+
+    in := os.Stdin
+    if path := fs.Arg(0); path != "-" {
+        f, err := os.Open(path)
+        if err != nil {
+            log.Fatalf("failed opening %s: %v", path, err)
+        }
+        defer f.Close()
+        in = f
+    }
+
+    records, err := read(in, recordsFormat(*format, fs.Arg(0)))
+    if err != nil {
+        log.Fatalf("failed reading %s: %v", fs.Arg(0), err)
+    }
+
+    report, err := importRecords(ctx, db, records, services.ImportOptions{
+        DryRun:     *dryRun,
+        BestEffort: *bestEffort,
+    })
+    if err != nil {
+        log.Fatalf("failed importing %s: %v", resource, err)
+    }
+
+    printImportReport(report)
+    if report.Failed != 0 {
+        os.Exit(1)
+    }
+}
+
+func printImportReport(report *protoapi.ImportReport) {
+    if report.Failed != 0 {
+        w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+        _, _ = fmt.Fprintln(w, "ROW\tNAME\tERROR")
+        for _, record := range report.Records {
+            if record.Error == "" {
+                continue
+            }
+
+            message := record.Error
+            for _, field := range record.FieldErrors {
+                if field.Message != record.Error {
+                    message += fmt.Sprintf(", %s: %s", field.Field, field.Message)
+                }
+            }
+            _, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", record.Row, record.Name, message)
+        }
+        _ = w.Flush()
+    }
+
+    switch {
+    case report.DryRun:
+        fmt.Printf("dry run, %d records would be imported and %d failed\n", report.Succeeded, report.Failed)
+    case report.Committed:
+        fmt.Printf("imported %d records, %d failed\n", report.Succeeded, report.Failed)
+    default:
+        fmt.Printf("imported nothing, %d records failed\n", report.Failed)
+    }
+}
+
+// runExport handles `panel user|role export`, writing the records export returns to the file or stdout
+func runExport[R any](ctx context.Context, db *ent.Client, resource string, args []string,
+    export func(ctx context.Context, db *ent.Client) ([]R, error),
+    write func(w io.Writer, format services.Format, records []R) error) {
+    fs := flag.NewFlagSet(resource+" export", flag.ExitOnError)
+    format := fs.String("format", "", "json or csv, guessed from the file extension if not set")
+    _ = fs.Parse(args)
+    if fs.NArg() > 1 {
+        log.Fatalf("usage: panel %s export [--format json|csv] [file]", resource)
+    }
+
+    records, err := export(ctx, db)
+    if err != nil {
+        log.Fatalf("failed exporting %s: %v", resource, err)
+    }
+
+    out := os.Stdout
+    if path := fs.Arg(0); path != "" {
+        // exports hold password hashes, so only the owner may read them
+        f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+        if err != nil {
+            log.Fatalf("failed creating %s: %v", path, err)
+        }
+        defer f.Close()
+        out = f
+    }
+
+    if err = write(out, recordsFormat(*format, fs.Arg(0)), records); err != nil {
+        log.Fatalf("failed writing %s: %v", resource, err)
+    }
+}
+
+// recordsFormat parses the --format flag, falling back to the extension of path and then JSON
+func recordsFormat(name string, path string) services.Format {
+    if name == "" && filepath.Ext(path) == ".csv" {
+        return services.FormatCSV
+    }
+
+    format, err := services.ParseFormat(name)
+    if err != nil {
+        log.Fatalln(err)
+    }
+
+    return format
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+    "context"
+    "flag"
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "github.com/google/uuid"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "log"
+    "time"
+)
+
+// runRole handles `panel role grant|import|export`
+func runRole(args []string) {
+    if len(args) == 0 {
+        log.Fatalln("usage: panel role grant|import|export")
+    }
+
+    db := config.InitDB()
+    defer db.Close()
+    // queued here, the deliveries are sent by the running panel
+    services.SubscribeWebhooks(db)
+
+    ctx := context.Background()
+    switch args[0] {
+    case "grant":
+        runRoleGrant(ctx, db, args[1:])
+    case "import":
+        runImport(ctx, db, "role", args[1:], services.ReadRoleRecords, services.ImportRoles)
+    case "export":
+        runExport(ctx, db, "role", args[1:], services.ExportRoles, services.WriteRoleRecords)
+    default:
+        log.Fatalf("unknown role command %q, expected grant, import or export", args[0])
+    }
+}
+
+func runRoleGrant(ctx context.Context, db *ent.Client, args []string) {
+    fs := flag.NewFlagSet("role grant", flag.ExitOnError)
+    expires := fs.Duration("expires", 0, "how long the grant lasts, forever if not set")
+    _ = fs.Parse(args)
+    if fs.NArg() != 2 {
+        log.Fatalln("usage: panel role grant [--expires duration] <user> <role>")
+    }
+
+    req := &protoapi.UserRoleGrantRequest{
+        UserId:   proto.UUIDToProtoUUID(findUserID(ctx, db, fs.Arg(0))),
+        RoleId:   &protoapi.UUID{Value: fs.Arg(1)},
+        RoleName: fs.Arg(1),
+    }
+    if *expires > 0 {
+        req.ExpiresAt = timestamppb.New(time.Now().Add(*expires))
+    }
+
+    resp, err := services.GrantUserRole(ctx, db, uuid.Nil, req)
+    if err != nil {
+        log.Fatalf("failed granting role: %v", err)
+    }
+
+    fmt.Printf("granted role %s to %s with grant id %s\n", fs.Arg(1), fs.Arg(0), resp.RoleGrant.Id.Value)
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+    "connectrpc.com/connect"
+    "context"
+    "github.com/Encedeus/panel/ent"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+)
+
+// apiKeyServer serves the same operations as the /key/account routes, but only to authenticated callers
+type apiKeyServer struct {
+    db *ent.Client
+}
+
+func (s apiKeyServer) CreateAccountAPIKey(ctx context.Context, req *connect.Request[protoapi.AccountAPIKeyCreateRequest]) (*connect.Response[protoapi.AccountAPIKeyCreateResponse], error) {
+    resp, err := services.CreateAccountAPIKey(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+func (s apiKeyServer) DeleteAccountAPIKey(ctx context.Context, req *connect.Request[protoapi.AccountAPIKeyDeleteRequest]) (*connect.Response[protoapi.AccountAPIKeyDeleteResponse], error) {
+    resp, err := services.DeleteAccountAPIKey(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+func (s apiKeyServer) FindAccountAPIKeysByUser(ctx context.Context, req *connect.Request[protoapi.AccountAPIKeyFindManyByUserRequest]) (*connect.Response[protoapi.AccountAPIKeyFindManyResponse], error) {
+    resp, err := services.FindAccountAPIKeysByUserID(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
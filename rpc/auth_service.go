@@ -0,0 +1,32 @@
+package rpc
+
+import (
+    "connectrpc.com/connect"
+    "context"
+    "github.com/Encedeus/panel/ent"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+)
+
+// authServer hands out tokens, RPC clients get the refresh token in the response instead of a cookie
+type authServer struct {
+    db *ent.Client
+}
+
+func (s authServer) SignIn(ctx context.Context, req *connect.Request[protoapi.UserSignInRequest]) (*connect.Response[protoapi.UserSignInResponse], error) {
+    resp, err := services.SignIn(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+func (authServer) RefreshAccessToken(_ context.Context, req *connect.Request[protoapi.AccessTokenRefreshRequest]) (*connect.Response[protoapi.AccessTokenRefreshResponse], error) {
+    resp, err := services.RefreshAccessToken(req.Msg.RefreshToken)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
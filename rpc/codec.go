@@ -0,0 +1,43 @@
+package rpc
+
+import (
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "google.golang.org/protobuf/encoding/protojson"
+    "google.golang.org/protobuf/proto"
+)
+
+// jsonCodec replaces connect's JSON codec so unknown fields follow the unknown_fields setting like in the REST API
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+    return "json"
+}
+
+func (jsonCodec) Marshal(msg any) ([]byte, error) {
+    m, ok := msg.(proto.Message)
+    if !ok {
+        return nil, fmt.Errorf("%T is not a proto.Message", msg)
+    }
+
+    return protojson.Marshal(m)
+}
+
+func (jsonCodec) Unmarshal(data []byte, msg any) error {
+    m, ok := msg.(proto.Message)
+    if !ok {
+        return fmt.Errorf("%T is not a proto.Message", msg)
+    }
+    // connect sends empty messages as empty bodies
+    if len(data) == 0 {
+        proto.Reset(m)
+
+        return nil
+    }
+
+    opts := protojson.UnmarshalOptions{
+        DiscardUnknown: config.Current().Server.UnknownFields == config.UnknownFieldsDiscard,
+    }
+
+    return opts.Unmarshal(data, m)
+}
@@ -0,0 +1,92 @@
+package rpc
+
+import (
+    "connectrpc.com/connect"
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+)
+
+// roleServer mirrors the permission checks of the /role routes
+type roleServer struct {
+    db *ent.Client
+}
+
+func (s roleServer) FindRole(ctx context.Context, req *connect.Request[protoapi.RoleFindOneRequest]) (*connect.Response[protoapi.RoleFindOneResponse], error) {
+    resp, err := services.FindRole(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return withETag(connect.NewResponse(resp), resp.Role.Version), nil
+}
+
+func (s roleServer) FindRoleEffectivePermissions(ctx context.Context, req *connect.Request[protoapi.RoleFindOneRequest]) (*connect.Response[protoapi.RoleEffectivePermissionsResponse], error) {
+    resp, err := services.FindRoleEffectivePermissions(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+func (s roleServer) CreateRole(ctx context.Context, req *connect.Request[protoapi.RoleCreateRequest]) (*connect.Response[protoapi.RoleCreateResponse], error) {
+    if _, err := requirePermission(ctx, s.db, "create_role"); err != nil {
+        return nil, err
+    }
+
+    resp, err := services.CreateRole(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+func (s roleServer) UpdateRole(ctx context.Context, req *connect.Request[protoapi.RoleUpdateRequest]) (*connect.Response[protoapi.RoleUpdateResponse], error) {
+    if _, err := requirePermission(ctx, s.db, "update_role"); err != nil {
+        return nil, err
+    }
+
+    resp, err := services.UpdateRole(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return withETag(connect.NewResponse(resp), resp.Role.Version), nil
+}
+
+func (s roleServer) DeleteRole(ctx context.Context, req *connect.Request[protoapi.RoleDeleteRequest]) (*connect.Response[protoapi.RoleDeleteResponse], error) {
+    if _, err := requirePermission(ctx, s.db, "delete_role"); err != nil {
+        return nil, err
+    }
+
+    resp, err := services.DeleteRole(ctx, s.db, req.Msg)
+    if err != nil {
+        if errors.Is(err, services.ErrAlreadyDeleted) {
+            return nil, services.NewGoneError("role already deleted")
+        }
+
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+func (s roleServer) RestoreRole(ctx context.Context, req *connect.Request[protoapi.RoleRestoreRequest]) (*connect.Response[protoapi.RoleRestoreResponse], error) {
+    if _, err := requirePermission(ctx, s.db, "restore_role"); err != nil {
+        return nil, err
+    }
+
+    role, err := services.RestoreRole(ctx, s.db, proto.ProtoUUIDToUUID(req.Msg.Id))
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(&protoapi.RoleRestoreResponse{
+        Role: role,
+    }), nil
+}
@@ -0,0 +1,68 @@
+package rpc
+
+import (
+    "connectrpc.com/connect"
+    "context"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/proto/go/protoapiconnect"
+    "github.com/Encedeus/panel/services"
+    "testing"
+)
+
+func TestRoleServiceChecksAuthAndPermissions(t *testing.T) {
+    srv, db := newTestServer(t)
+    creator := createTestUser(t, db, "creator", "create_role")
+    other := createTestUser(t, db, "other")
+
+    protocols := []struct {
+        name string
+        opts []connect.ClientOption
+    }{
+        {"connect", []connect.ClientOption{connect.WithProtoJSON()}},
+        {"grpc", []connect.ClientOption{connect.WithGRPC()}},
+    }
+    for _, protocol := range protocols {
+        t.Run(protocol.name, func(t *testing.T) {
+            client := protoapiconnect.NewRoleServiceClient(srv.Client(), srv.URL, protocol.opts...)
+            create := func(token string, name string) (*connect.Response[protoapi.RoleCreateResponse], error) {
+                return client.CreateRole(context.Background(), withToken(connect.NewRequest(&protoapi.RoleCreateRequest{
+                    Name:        name,
+                    Permissions: []string{"read"},
+                }), token))
+            }
+
+            tests := []struct {
+                name  string
+                token string
+                role  string
+                want  connect.Code
+            }{
+                {"without a token", "", protocol.name + "-a", connect.CodeUnauthenticated},
+                {"without permission", other, protocol.name + "-b", connect.CodePermissionDenied},
+                {"invalid name", creator, "", connect.CodeInvalidArgument},
+            }
+            for _, tt := range tests {
+                t.Run(tt.name, func(t *testing.T) {
+                    _, err := create(tt.token, tt.role)
+                    if code := connect.CodeOf(err); code != tt.want {
+                        t.Errorf("got %v (%v), want %v", code, err, tt.want)
+                    }
+                })
+            }
+
+            resp, err := create(creator, protocol.name+"-role")
+            if err != nil {
+                t.Fatal(err)
+            }
+            found, err := client.FindRole(context.Background(), withToken(connect.NewRequest(&protoapi.RoleFindOneRequest{
+                Id: resp.Msg.Role.Id,
+            }), other))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if found.Msg.Role.Name != protocol.name+"-role" || found.Header().Get("ETag") != services.ETag(found.Msg.Role.Version) {
+                t.Errorf("found role %v with ETag %s", found.Msg.Role, found.Header().Get("ETag"))
+            }
+        })
+    }
+}
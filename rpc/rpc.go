@@ -0,0 +1,170 @@
+// Package rpc serves the services over gRPC, gRPC-Web and Connect, sharing the services package with the REST API
+package rpc
+
+import (
+    "connectrpc.com/connect"
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/middleware"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/proto/go/protoapiconnect"
+    "github.com/Encedeus/panel/services"
+    "github.com/google/uuid"
+    "google.golang.org/protobuf/proto"
+    "log/slog"
+    "net/http"
+)
+
+// Handler serves the procedures of a service under its path, which ends with a slash
+type Handler struct {
+    Path    string
+    Handler http.Handler
+}
+
+// Handlers returns the handlers of the Auth, User, Role and ApiKey services,
+// all of them except AuthService require an access token or API key like the REST API.
+// Requests are decoded and validated like the REST API's, see codec and validationInterceptor
+func Handlers(db *ent.Client) []Handler {
+    decoding := connect.WithHandlerOptions(
+        connect.WithCodec(jsonCodec{}),
+        connect.WithReadMaxBytes(int(config.Config.Server.MaxBodySize)),
+    )
+    public := connect.WithHandlerOptions(decoding,
+        connect.WithInterceptors(errorInterceptor(), validationInterceptor()),
+    )
+    authenticated := connect.WithHandlerOptions(decoding,
+        connect.WithInterceptors(errorInterceptor(), authInterceptor(db), validationInterceptor(), ifMatchInterceptor()),
+    )
+
+    return []Handler{
+        newHandler(protoapiconnect.NewAuthServiceHandler(authServer{db: db}, public)),
+        newHandler(protoapiconnect.NewUserServiceHandler(userServer{db: db}, authenticated)),
+        newHandler(protoapiconnect.NewRoleServiceHandler(roleServer{db: db}, authenticated)),
+        newHandler(protoapiconnect.NewApiKeyServiceHandler(apiKeyServer{db: db}, authenticated)),
+    }
+}
+
+func newHandler(path string, handler http.Handler) Handler {
+    return Handler{
+        Path:    path,
+        Handler: handler,
+    }
+}
+
+// authInterceptor authenticates calls with the Authorization header, the equivalent of middleware.AccessJWTAuth
+func authInterceptor(db *ent.Client) connect.UnaryInterceptorFunc {
+    return func(next connect.UnaryFunc) connect.UnaryFunc {
+        return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+            ctx, err := middleware.AuthenticateAccess(ctx, db, req.Header().Get("Authorization"), req.Peer().Addr)
+            if err != nil {
+                return nil, err
+            }
+
+            return next(ctx, req)
+        }
+    }
+}
+
+// validationInterceptor checks requests against the rules declared in their proto definitions
+func validationInterceptor() connect.UnaryInterceptorFunc {
+    return func(next connect.UnaryFunc) connect.UnaryFunc {
+        return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+            if msg, ok := req.Any().(proto.Message); ok {
+                if err := services.ValidateRequest(msg); err != nil {
+                    return nil, err
+                }
+            }
+
+            return next(ctx, req)
+        }
+    }
+}
+
+// ifMatchInterceptor passes the If-Match header on to the services, see services.WithIfMatch
+func ifMatchInterceptor() connect.UnaryInterceptorFunc {
+    return func(next connect.UnaryFunc) connect.UnaryFunc {
+        return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+            return next(services.WithIfMatch(ctx, req.Header().Get("If-Match")), req)
+        }
+    }
+}
+
+// withETag sets the ETag header to the version of the resource in the response
+func withETag[T any](resp *connect.Response[T], version int64) *connect.Response[T] {
+    resp.Header().Set("ETag", services.ETag(version))
+
+    return resp
+}
+
+var kindCode = map[services.Kind]connect.Code{
+    services.KindValidation:         connect.CodeInvalidArgument,
+    services.KindUnauthorized:       connect.CodeUnauthenticated,
+    services.KindForbidden:          connect.CodePermissionDenied,
+    services.KindNotFound:           connect.CodeNotFound,
+    services.KindConflict:           connect.CodeAlreadyExists,
+    services.KindGone:               connect.CodeNotFound,
+    services.KindPreconditionFailed: connect.CodeAborted,
+}
+
+// errorInterceptor converts the returned errors to connect errors, domain errors keep their message and
+// field errors are attached as FieldError details, unexpected ones are logged and answered with a generic one
+func errorInterceptor() connect.UnaryInterceptorFunc {
+    return func(next connect.UnaryFunc) connect.UnaryFunc {
+        return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+            resp, err := next(ctx, req)
+            if err != nil {
+                return nil, connectError(ctx, req.Spec().Procedure, err)
+            }
+
+            return resp, nil
+        }
+    }
+}
+
+func connectError(ctx context.Context, procedure string, err error) error {
+    domainErr := services.DomainError(err)
+    if domainErr == nil {
+        slog.ErrorContext(ctx, "uncaught error", "procedure", procedure, "error", err)
+
+        return connect.NewError(connect.CodeInternal, errInternal)
+    }
+
+    connectErr := connect.NewError(kindCode[domainErr.Kind], domainErr)
+    for _, field := range domainErr.Fields {
+        detail, detailErr := connect.NewErrorDetail(&protoapi.FieldError{
+            Field:   field.Field,
+            Message: field.Message,
+        })
+        if detailErr == nil {
+            connectErr.AddDetail(detail)
+        }
+    }
+
+    return connectErr
+}
+
+var errInternal = errors.New("internal server error")
+
+// requirePermission fails unless the authenticated user has the permission
+func requirePermission(ctx context.Context, db *ent.Client, permission string) (uuid.UUID, error) {
+    userId, _ := middleware.IDFromAccessContext(ctx)
+    if !services.DoesUserHavePermission(ctx, db, permission, userId) {
+        return uuid.Nil, services.ErrMissingPermission
+    }
+
+    return userId, nil
+}
+
+// requireSelfOrPermission is requirePermission, except that the authenticated user passes if they are userId
+func requireSelfOrPermission(ctx context.Context, db *ent.Client, permission string, userId uuid.UUID) error {
+    authId, _ := middleware.IDFromAccessContext(ctx)
+    if authId != uuid.Nil && authId == userId {
+        return nil
+    }
+
+    _, err := requirePermission(ctx, db, permission)
+
+    return err
+}
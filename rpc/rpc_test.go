@@ -0,0 +1,85 @@
+package rpc
+
+import (
+    "connectrpc.com/connect"
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "github.com/Encedeus/panel/testdb"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+// newTestServer serves the RPC handlers over HTTP/2 with TLS, so gRPC works too
+func newTestServer(t *testing.T) (*httptest.Server, *ent.Client) {
+    t.Helper()
+
+    config.Config = config.DefaultConfiguration()
+    config.Config.Auth.JWTSecretAccess = "test access secret"
+    config.Config.Auth.JWTSecretRefresh = "test refresh secret"
+
+    db := testdb.NewClient(t)
+    mux := http.NewServeMux()
+    for _, h := range Handlers(db) {
+        mux.Handle(h.Path, h.Handler)
+    }
+
+    srv := httptest.NewUnstartedServer(mux)
+    srv.EnableHTTP2 = true
+    srv.StartTLS()
+    t.Cleanup(srv.Close)
+
+    return srv, db
+}
+
+// createTestUser creates a user with a new role holding permissions, returning an access token of the user
+func createTestUser(t *testing.T, db *ent.Client, name string, permissions ...string) string {
+    t.Helper()
+
+    u := testdb.CreateUser(t, db, name, testdb.CreateRole(t, db, name+"-role", permissions).ID)
+    token, err := services.GenerateAccessToken(&protoapi.AccessToken{
+        Token: &protoapi.Token{UserId: proto.UUIDToProtoUUID(u.ID)},
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return token
+}
+
+func withToken[T any](req *connect.Request[T], token string) *connect.Request[T] {
+    if token != "" {
+        req.Header().Set("Authorization", "Bearer "+token)
+    }
+
+    return req
+}
+
+func TestConnectErrorDetails(t *testing.T) {
+    err := connectError(context.Background(), "/test", services.NewFieldError("name", "required"))
+
+    var connectErr *connect.Error
+    if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeInvalidArgument {
+        t.Fatalf("got %v, want an invalid argument error", err)
+    }
+    details := connectErr.Details()
+    if len(details) != 1 {
+        t.Fatalf("got %d details, want 1", len(details))
+    }
+    detail, err := details[0].Value()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if field, ok := detail.(*protoapi.FieldError); !ok || field.Field != "name" {
+        t.Errorf("got detail %v, want a FieldError for name", detail)
+    }
+
+    if err = connectError(context.Background(), "/test", errors.New("connection reset")); connect.CodeOf(err) != connect.CodeInternal || errors.Unwrap(err).Error() != "internal server error" {
+        t.Errorf("unexpected error got %v, want a generic internal error", err)
+    }
+}
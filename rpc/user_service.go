@@ -0,0 +1,172 @@
+package rpc
+
+import (
+    "connectrpc.com/connect"
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/middleware"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "github.com/google/uuid"
+)
+
+// userServer mirrors the permission checks of the /user routes
+type userServer struct {
+    db *ent.Client
+}
+
+func (s userServer) FindUser(ctx context.Context, req *connect.Request[protoapi.UserFindOneRequest]) (*connect.Response[protoapi.UserFindOneResponse], error) {
+    resp, err := services.FindOneUser(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return withETag(connect.NewResponse(resp), resp.User.Version), nil
+}
+
+func (s userServer) CreateUser(ctx context.Context, req *connect.Request[protoapi.UserCreateRequest]) (*connect.Response[protoapi.UserCreateResponse], error) {
+    authUUID, err := requirePermission(ctx, s.db, "create_user")
+    if err != nil {
+        return nil, err
+    }
+
+    resp, err := services.CreateUser(ctx, s.db, authUUID, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+func (s userServer) UpdateUser(ctx context.Context, req *connect.Request[protoapi.UserUpdateRequest]) (*connect.Response[protoapi.UserUpdateResponse], error) {
+    authUUID, err := requirePermission(ctx, s.db, "update_user")
+    if err != nil {
+        return nil, err
+    }
+
+    resp, err := services.UpdateUser(ctx, s.db, authUUID, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return withETag(connect.NewResponse(resp), resp.User.Version), nil
+}
+
+func (s userServer) DeleteUser(ctx context.Context, req *connect.Request[protoapi.UserDeleteRequest]) (*connect.Response[protoapi.UserDeleteResponse], error) {
+    if _, err := requirePermission(ctx, s.db, "delete_user"); err != nil {
+        return nil, err
+    }
+
+    resp, err := services.DeleteUser(ctx, s.db, req.Msg)
+    if err != nil {
+        if errors.Is(err, services.ErrAlreadyDeleted) {
+            return nil, services.NewGoneError("user already deleted")
+        }
+
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+func (s userServer) RestoreUser(ctx context.Context, req *connect.Request[protoapi.UserRestoreRequest]) (*connect.Response[protoapi.UserRestoreResponse], error) {
+    if _, err := requirePermission(ctx, s.db, "restore_user"); err != nil {
+        return nil, err
+    }
+
+    user, err := services.RestoreUser(ctx, s.db, proto.ProtoUUIDToUUID(req.Msg.UserId))
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(&protoapi.UserRestoreResponse{
+        User: user,
+    }), nil
+}
+
+// ChangePassword changes the password of the authenticated user, the request's user ID is ignored
+func (s userServer) ChangePassword(ctx context.Context, req *connect.Request[protoapi.UserChangePasswordRequest]) (*connect.Response[protoapi.UserChangePasswordResponse], error) {
+    authUUID, _ := middleware.IDFromAccessContext(ctx)
+    req.Msg.UserId = proto.UUIDToProtoUUID(authUUID)
+
+    resp, err := services.ChangeUserPassword(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+// ChangeUsername changes the username of the authenticated user, the request's user ID is ignored
+func (s userServer) ChangeUsername(ctx context.Context, req *connect.Request[protoapi.UserChangeUsernameRequest]) (*connect.Response[protoapi.UserChangeUsernameResponse], error) {
+    authUUID, _ := middleware.IDFromAccessContext(ctx)
+    req.Msg.UserId = proto.UUIDToProtoUUID(authUUID)
+
+    resp, err := services.ChangeUsername(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+// ChangeEmail changes the email of the authenticated user, the request's user ID is ignored
+func (s userServer) ChangeEmail(ctx context.Context, req *connect.Request[protoapi.UserChangeEmailRequest]) (*connect.Response[protoapi.UserChangeEmailResponse], error) {
+    authUUID, _ := middleware.IDFromAccessContext(ctx)
+    req.Msg.UserId = proto.UUIDToProtoUUID(authUUID)
+
+    resp, err := services.ChangeUserEmail(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+func (s userServer) FindRoleGrants(ctx context.Context, req *connect.Request[protoapi.UserRoleGrantFindManyRequest]) (*connect.Response[protoapi.UserRoleGrantFindManyResponse], error) {
+    // an invalid id is nobody's own, it's reported as not found after the permission check
+    userId, _ := uuid.Parse(req.Msg.GetUserId().GetValue())
+    if err := requireSelfOrPermission(ctx, s.db, "grant_role", userId); err != nil {
+        return nil, err
+    }
+
+    resp, err := services.FindUserRoleGrants(ctx, s.db, req.Msg)
+    if err != nil {
+        if errors.Is(err, services.ErrInvalidUserId) {
+            return nil, services.ErrUserNotFound
+        }
+
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+func (s userServer) GrantRole(ctx context.Context, req *connect.Request[protoapi.UserRoleGrantRequest]) (*connect.Response[protoapi.UserRoleGrantResponse], error) {
+    authUUID, err := requirePermission(ctx, s.db, "grant_role")
+    if err != nil {
+        return nil, err
+    }
+
+    resp, err := services.GrantUserRole(ctx, s.db, authUUID, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
+
+func (s userServer) RevokeRole(ctx context.Context, req *connect.Request[protoapi.UserRoleRevokeRequest]) (*connect.Response[protoapi.UserRoleRevokeResponse], error) {
+    if _, err := requirePermission(ctx, s.db, "revoke_role"); err != nil {
+        return nil, err
+    }
+
+    resp, err := services.RevokeUserRole(ctx, s.db, req.Msg)
+    if err != nil {
+        return nil, err
+    }
+
+    return connect.NewResponse(resp), nil
+}
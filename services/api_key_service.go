@@ -3,7 +3,6 @@ package services
 import (
     "context"
     "entgo.io/ent/dialect/sql"
-    "errors"
     "github.com/Encedeus/panel/ent"
     "github.com/Encedeus/panel/ent/apikey"
     "github.com/Encedeus/panel/proto"
@@ -49,6 +48,7 @@ func CreateAccountAPIKey(ctx context.Context, db *ent.Client, req *protoapi.Acco
             IpAddresses: apiKey.IPAddresses,
             UserId:      proto.UUIDToProtoUUID(apiKey.UserID),
             Key:         apiKey.Key,
+            Version:     apiKey.Version,
         },
     }
 
@@ -57,11 +57,22 @@ func CreateAccountAPIKey(ctx context.Context, db *ent.Client, req *protoapi.Acco
 
 func DeleteAccountAPIKey(ctx context.Context, db *ent.Client, req *protoapi.AccountAPIKeyDeleteRequest) (resp *protoapi.AccountAPIKeyDeleteResponse, err error) {
     if s := req.Id.Value; strings.TrimSpace(s) == "" {
-        return nil, errors.New("missing API key")
+        return nil, NewFieldError("id", "missing API key")
     }
 
-    err = db.ApiKey.DeleteOneID(proto.ProtoUUIDToUUID(req.Id)).Exec(ctx)
+    keyId := proto.ProtoUUIDToUUID(req.Id)
+    deletion := db.ApiKey.DeleteOneID(keyId)
+    if versions, ok := ifMatch(ctx); ok {
+        deletion.Where(apikey.VersionIn(versions...))
+    }
 
+    err = deletion.Exec(ctx)
+    if ent.IsNotFound(err) {
+        // the key exists, so the If-Match header didn't match it
+        if exists, _ := db.ApiKey.Query().Where(apikey.IDEQ(keyId)).Exist(ctx); exists {
+            return nil, ErrVersionMismatch
+        }
+    }
     if err != nil {
         return nil, err
     }
@@ -88,6 +99,9 @@ func FindAccountAPIKeysByUserID(ctx context.Context, db *ent.Client, req *protoa
 
 func FindAccountAPIKeyByID(ctx context.Context, db *ent.Client, req *protoapi.AccountAPIKeyFindOneRequest) (resp *protoapi.AccountAPIKeyFindOneResponse, err error) {
     apiKey, err := db.ApiKey.Get(ctx, proto.ProtoUUIDToUUID(req.Id))
+    if ent.IsNotFound(err) {
+        return nil, ErrAPIKeyNotFound
+    }
     if err != nil {
         return nil, err
     }
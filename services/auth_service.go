@@ -4,9 +4,13 @@ import (
     "context"
     "github.com/Encedeus/panel/ent"
     "github.com/Encedeus/panel/ent/user"
+    "github.com/Encedeus/panel/hashing"
+    "github.com/Encedeus/panel/metrics"
     "github.com/Encedeus/panel/proto"
     protoapi "github.com/Encedeus/panel/proto/go"
-    "github.com/labstack/gommon/log"
+    "log/slog"
+    "net/mail"
+    "strings"
 )
 
 // GetUserAuthDataAndHashByUsername returns the user's uuid and hashed password provided the username of the user
@@ -15,7 +19,7 @@ func GetUserAuthDataAndHashByUsername(ctx context.Context, db *ent.Client, usern
 
     if err != nil {
         if !ent.IsNotFound(err) {
-            log.Errorf("error querying db on user login (username): %v", err)
+            slog.ErrorContext(ctx, "error querying db on user login (username)", "error", err)
         }
 
         return "", nil, err
@@ -31,8 +35,8 @@ func GetUserAuthDataAndHashByEmail(ctx context.Context, db *ent.Client, email st
     userData, err := db.User.Query().Where(user.Email(email)).Select(user.FieldID, user.FieldPassword).First(ctx)
 
     if err != nil {
-        if ent.IsNotFound(err) {
-            log.Errorf("error querying db on user login (email): %v", err)
+        if !ent.IsNotFound(err) {
+            slog.ErrorContext(ctx, "error querying db on user login (email)", "error", err)
         }
 
         return "", nil, err
@@ -42,3 +46,74 @@ func GetUserAuthDataAndHashByEmail(ctx context.Context, db *ent.Client, email st
         UserId: proto.UUIDToProtoUUID(userData.ID),
     }, nil
 }
+
+// SignIn checks the password of the user whose username or email is the request's uid, returning a new token pair
+func SignIn(ctx context.Context, db *ent.Client, req *protoapi.UserSignInRequest) (*protoapi.UserSignInResponse, error) {
+    if strings.TrimSpace(req.Uid) == "" {
+        return nil, NewFieldError("uid", "either username or email must be specified")
+    }
+
+    var (
+        passwordHash string
+        tokenData    *protoapi.Token
+        err          error
+    )
+
+    // check which method was used for log in
+    if _, mailErr := mail.ParseAddress(req.Uid); mailErr != nil {
+        passwordHash, tokenData, err = GetUserAuthDataAndHashByUsername(ctx, db, req.Uid)
+    } else {
+        passwordHash, tokenData, err = GetUserAuthDataAndHashByEmail(ctx, db, req.Uid)
+    }
+    if err != nil {
+        if ent.IsNotFound(err) {
+            metrics.AuthAttempt("password", false)
+
+            return nil, ErrUserNotFound
+        }
+
+        return nil, err
+    }
+
+    // check if the password hash is a match
+    auth := hashing.VerifyHash(req.Password, passwordHash)
+
+    metrics.AuthAttempt("password", auth)
+    if !auth {
+        return nil, ErrUnauthorized
+    }
+
+    accessToken, refreshToken, err := GetTokenPair(tokenData)
+    if err != nil {
+        return nil, err
+    }
+
+    return &protoapi.UserSignInResponse{
+        AccessToken:  accessToken,
+        RefreshToken: refreshToken,
+    }, nil
+}
+
+// RefreshAccessToken issues a new access token for the user the refresh token belongs to
+func RefreshAccessToken(refreshToken string) (*protoapi.AccessTokenRefreshResponse, error) {
+    isValid, claims, err := ValidateRefreshJWT(refreshToken)
+
+    metrics.AuthAttempt("refresh_token", isValid && err == nil)
+    if !isValid || err != nil {
+        return nil, ErrUnauthorized
+    }
+
+    accessToken, err := GenerateAccessToken(&protoapi.AccessToken{
+        Token: &protoapi.Token{
+            UserId: claims.Token.UserId,
+            Type:   protoapi.TokenType_ACCESS_TOKEN,
+        },
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &protoapi.AccessTokenRefreshResponse{
+        AccessToken: accessToken,
+    }, nil
+}
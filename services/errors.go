@@ -1,49 +1,200 @@
 package services
 
-import "errors"
+import (
+    "errors"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/validate"
+    "google.golang.org/protobuf/proto"
+    "strings"
+)
+
+// Kind says what went wrong, the HTTP error handler maps each kind to a status code
+type Kind int
+
+const (
+    KindValidation Kind = iota + 1
+    KindUnauthorized
+    KindForbidden
+    KindNotFound
+    KindConflict
+    KindGone
+    KindPreconditionFailed
+)
+
+// FieldError describes why the value of a single request field was rejected
+type FieldError struct {
+    Field   string
+    Message string
+}
+
+// Error is a domain error safe to show to API clients, anything else is reported as an internal error
+type Error struct {
+    Kind    Kind
+    Message string
+    Fields  []FieldError
+}
+
+func (e *Error) Error() string {
+    return e.Message
+}
+
+func newError(kind Kind, message string, fields ...FieldError) *Error {
+    return &Error{
+        Kind:    kind,
+        Message: message,
+        Fields:  fields,
+    }
+}
+
+// NewValidationError is returned when the request is malformed, fields lists the offending ones if known
+func NewValidationError(message string, fields ...FieldError) *Error {
+    return newError(KindValidation, message, fields...)
+}
+
+// NewFieldError is a validation error about a single field, the message is used for both the error and the field
+func NewFieldError(field string, message string) *Error {
+    return NewValidationError(message, FieldError{Field: field, Message: message})
+}
+
+func NewUnauthorizedError(message string) *Error {
+    return newError(KindUnauthorized, message)
+}
 
-type ValidationError struct {
-    message string
+func NewForbiddenError(message string) *Error {
+    return newError(KindForbidden, message)
 }
 
-func (e ValidationError) Error() string {
-    return e.message
+func NewNotFoundError(message string) *Error {
+    return newError(KindNotFound, message)
 }
 
-func NewValidationError(message string) ValidationError {
-    ve := ValidationError{
-        message: message,
+// NewConflictError is returned when the request clashes with the current state, like a taken name
+func NewConflictError(message string) *Error {
+    return newError(KindConflict, message)
+}
+
+// NewGoneError is returned when the requested resource is soft-deleted
+func NewGoneError(message string) *Error {
+    return newError(KindGone, message)
+}
+
+// NewPreconditionFailedError is returned when the resource changed since the version the request was based on
+func NewPreconditionFailedError(message string) *Error {
+    return newError(KindPreconditionFailed, message)
+}
+
+// KindOf returns the kind of the domain error in err's chain, it's 0 if there's none
+func KindOf(err error) Kind {
+    var e *Error
+    if errors.As(err, &e) {
+        return e.Kind
     }
 
-    return ve
+    return 0
 }
 
 func IsValidationError(err error) bool {
-    if errors.As(err, &ValidationError{}) {
-        return true
+    return KindOf(err) == KindValidation
+}
+
+// ValidateRequest checks req against the rules declared in its proto definition,
+// returning a validation error listing every field that breaks them
+func ValidateRequest(req proto.Message) error {
+    violations := validate.Message(req)
+    if len(violations) == 0 {
+        return nil
+    }
+
+    fields := make([]FieldError, len(violations))
+    for i, v := range violations {
+        fields[i] = FieldError{
+            Field:   v.Field,
+            Message: v.Message,
+        }
+    }
+
+    return NewValidationError("invalid request", fields...)
+}
+
+// DomainError returns the domain error in err's chain, translating ent's not found, validation and constraint errors,
+// it's nil for any other error, which shouldn't be shown to clients
+func DomainError(err error) *Error {
+    var (
+        domainErr     *Error
+        validationErr *ent.ValidationError
+    )
+
+    switch {
+    case errors.As(err, &domainErr):
+        return domainErr
+    case ent.IsNotFound(err):
+        // the message is "ent: <entity label> not found"
+        return NewNotFoundError(strings.ReplaceAll(strings.TrimPrefix(err.Error(), "ent: "), "_", " "))
+    case errors.As(err, &validationErr):
+        return NewValidationError("invalid "+strings.ReplaceAll(validationErr.Name, "_", " "), FieldError{
+            Field:   jsonFieldName(validationErr.Name),
+            Message: validationErr.Unwrap().Error(),
+        })
+    case ent.IsConstraintError(err):
+        return NewConflictError("conflicts with an existing resource")
+    }
+
+    return nil
+}
+
+// jsonFieldName converts an ent field name to the lowerCamelCase name the field has in request bodies
+func jsonFieldName(name string) string {
+    parts := strings.Split(name, "_")
+    for i := 1; i < len(parts); i++ {
+        if parts[i] != "" {
+            parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+        }
     }
 
-    return false
+    return strings.Join(parts, "")
 }
 
 var (
     ErrInvalidTokenType         = errors.New("invalid JWT  type")
-    ErrInvalidAPIKeyDescription = NewValidationError("invalid API key description")
-    ErrInvalidUserId            = NewValidationError("invalid user id")
-    ErrInvalidIPAddress         = NewValidationError("invalid IP address")
-    ErrInvalidEmail             = NewValidationError("invalid email")
-    ErrInvalidUsername          = NewValidationError("invalid username")
-    ErrInvalidRoleID            = NewValidationError("invalid role id")
-    ErrInvalidRoleName          = NewValidationError("invalid role name")
-    ErrInvalidPassword          = NewValidationError("invalid password")
-    ErrInvalidPermission        = NewValidationError("invalid permission")
-    ErrOldUsernameDoesNotMatch  = NewValidationError("old username does not match current one")
-    ErrNewUsernameEqualsOld     = NewValidationError("old username equals new one")
-    ErrOldPasswordDoesNotMatch  = NewValidationError("old password does not match current one")
-    ErrNewPasswordEqualsOld     = NewValidationError("old password equals new one")
-    ErrOldEmailDoesNotMatch     = NewValidationError("old email does not match current one")
-    ErrNewEmailEqualsOld        = NewValidationError("old email equals new one")
-    ErrUserNotFound             = errors.New("user not found")
+    ErrInvalidAPIKeyDescription = NewFieldError("description", "invalid API key description")
+    ErrInvalidUserId            = NewFieldError("userId", "invalid user id")
+    ErrInvalidIPAddress         = NewFieldError("ipAddresses", "invalid IP address")
+    ErrInvalidEmail             = NewFieldError("email", "invalid email")
+    ErrInvalidUsername          = NewFieldError("name", "invalid username")
+    ErrInvalidRoleID            = NewFieldError("id", "invalid role id")
+    ErrInvalidRoleName          = NewFieldError("name", "invalid role name")
+    ErrInvalidPassword          = NewFieldError("password", "invalid password")
+    ErrInvalidPasswordHash      = NewFieldError("passwordHash", "password hash isn't a bcrypt hash")
+    ErrPasswordAndHash          = NewFieldError("passwordHash", "password and password hash can't both be set")
+    ErrInvalidPermission        = NewFieldError("permissions", "invalid permission")
+    ErrInvalidRoleParent        = NewFieldError("parentIds", "invalid parent role")
+    ErrRoleCycle                = NewFieldError("parentIds", "role can't inherit from itself or its descendants")
+    ErrInvalidGrantExpiry       = NewFieldError("expiresAt", "grant expiry must be in the future")
+    ErrOldUsernameDoesNotMatch  = NewFieldError("oldUsername", "old username does not match current one")
+    ErrNewUsernameEqualsOld     = NewFieldError("newUsername", "old username equals new one")
+    ErrOldPasswordDoesNotMatch  = NewForbiddenError("old password does not match current one")
+    ErrNewPasswordEqualsOld     = NewFieldError("newPassword", "old password equals new one")
+    ErrOldEmailDoesNotMatch     = NewFieldError("oldEmail", "old email does not match current one")
+    ErrNewEmailEqualsOld        = NewFieldError("newEmail", "old email equals new one")
+    ErrMissingFields            = NewValidationError("required fields are missing")
+    ErrUserNotFound             = NewNotFoundError("user not found")
+    ErrUserDeleted              = NewGoneError("user deleted")
+    ErrUserNotDeleted           = NewConflictError("user not deleted")
+    ErrUsernameTaken            = NewConflictError("username taken")
+    ErrRoleDeleted              = NewGoneError("role deleted")
+    ErrRoleNotDeleted           = NewConflictError("role not deleted")
+    ErrRoleNameTaken            = NewConflictError("role name taken")
+    ErrUnknownRole              = NewFieldError("role", "role not found")
+    ErrUnknownRoleParent        = NewFieldError("parents", "parent role not found")
+    ErrAlreadyDeleted           = NewGoneError("already deleted")
+    ErrRoleNotAssignable        = NewForbiddenError("role has permissions the user doesn't have")
+    ErrRoleGrantNotFound        = NewNotFoundError("role grant not found")
+    ErrAPIKeyNotFound           = NewNotFoundError("API key not found")
+    ErrSetupComplete            = NewConflictError("setup already completed")
+    ErrInvalidSetupToken        = NewUnauthorizedError("invalid setup token")
+    ErrUnauthorized             = NewUnauthorizedError("unauthorised")
+    ErrIPAddressNotAllowed      = NewForbiddenError("access from this IP address not allowed")
+    ErrMissingPermission        = NewForbiddenError("missing permission")
 
     ErrWrongPassword = errors.New("wrong password")
 )
@@ -0,0 +1,42 @@
+package services
+
+import (
+    "context"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/events"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/google/uuid"
+)
+
+// publishUser publishes an event about a user, leaving out the password hash
+func publishUser(ctx context.Context, db *ent.Client, topic events.Topic[*protoapi.User], u *protoapi.User) error {
+    return topic.Publish(ctx, db, &protoapi.User{
+        Id:        u.Id,
+        CreatedAt: u.CreatedAt,
+        UpdatedAt: u.UpdatedAt,
+        DeletedAt: u.DeletedAt,
+        Email:     u.Email,
+        Name:      u.Name,
+        RoleId:    u.RoleId,
+        Version:   u.Version,
+    })
+}
+
+func publishEntUser(ctx context.Context, db *ent.Client, topic events.Topic[*protoapi.User], u *ent.User) error {
+    return publishUser(ctx, db, topic, proto.EntUserEntityToProtoUser(u))
+}
+
+// publishUserDeleted only includes the id, deleted users can't be queried anymore
+func publishUserDeleted(ctx context.Context, db *ent.Client, userId uuid.UUID) error {
+    return events.UserDeleted.Publish(ctx, db, &protoapi.User{
+        Id: proto.UUIDToProtoUUID(userId),
+    })
+}
+
+// publishRoleDeleted only includes the id, deleted roles can't be queried anymore
+func publishRoleDeleted(ctx context.Context, db *ent.Client, roleId uuid.UUID) error {
+    return events.RoleDeleted.Publish(ctx, db, &protoapi.Role{
+        Id: proto.UUIDToProtoUUID(roleId),
+    })
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+    "context"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/ent/role"
+    "github.com/Encedeus/panel/ent/user"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/google/uuid"
+)
+
+// ExportUsers returns every user that isn't deleted ordered by name, along with their password hash
+// so they keep their password when imported into another panel
+func ExportUsers(ctx context.Context, db *ent.Client) ([]*protoapi.UserRecord, error) {
+    users, err := db.User.Query().WithRole().Order(user.ByName()).All(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    records := make([]*protoapi.UserRecord, len(users))
+    for i, u := range users {
+        records[i] = &protoapi.UserRecord{
+            Name:         u.Name,
+            Email:        u.Email,
+            PasswordHash: u.Password,
+        }
+        // empty if the role was soft-deleted
+        if u.Edges.Role != nil {
+            records[i].Role = u.Edges.Role.Name
+        }
+    }
+
+    return records, nil
+}
+
+// ExportRoles returns every role that isn't deleted, each after its parents so the export can be imported as it is
+func ExportRoles(ctx context.Context, db *ent.Client) ([]*protoapi.RoleRecord, error) {
+    roles, err := db.Role.Query().WithParents().Order(role.ByName()).All(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    records := make([]*protoapi.RoleRecord, 0, len(roles))
+    exported := make(map[uuid.UUID]bool, len(roles))
+    byId := make(map[uuid.UUID]*ent.Role, len(roles))
+    for _, r := range roles {
+        byId[r.ID] = r
+    }
+
+    // roles can't inherit from their descendants, so this always ends
+    var export func(r *ent.Role)
+    export = func(r *ent.Role) {
+        if exported[r.ID] {
+            return
+        }
+        exported[r.ID] = true
+
+        record := &protoapi.RoleRecord{
+            Name:        r.Name,
+            Permissions: r.Permissions,
+        }
+        for _, parent := range r.Edges.Parents {
+            if p, ok := byId[parent.ID]; ok {
+                export(p)
+            }
+            record.Parents = append(record.Parents, parent.Name)
+        }
+        records = append(records, record)
+    }
+    for _, r := range roles {
+        export(r)
+    }
+
+    return records, nil
+}
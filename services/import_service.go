@@ -0,0 +1,234 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/ent/role"
+    "github.com/Encedeus/panel/ent/user"
+    "github.com/Encedeus/panel/events"
+    "github.com/Encedeus/panel/hashing"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/validate"
+    "github.com/google/uuid"
+)
+
+// ImportOptions says how the records of a bulk import are written
+type ImportOptions struct {
+    // DryRun checks and imports the records in a transaction that's always rolled back,
+    // reporting what the import would do without writing anything
+    DryRun bool
+    // BestEffort commits every record that can be imported on its own, by default nothing is written
+    // if any of the records fails
+    BestEffort bool
+}
+
+// errImportRolledBack rolls back the transaction of an import that must not be committed
+var errImportRolledBack = errors.New("import rolled back")
+
+// ImportUsers creates a user for every record, users passing a bcrypt hash keep their password.
+// Unless importedBy is uuid.Nil, which the CLI passes, importedBy has to be able to assign the role of every record.
+// Records that can't be imported are listed in the report, the error is only set if the import couldn't run at all.
+func ImportUsers(ctx context.Context, db *ent.Client, importedBy uuid.UUID, records []*protoapi.UserRecord, opts ImportOptions) (*protoapi.ImportReport, error) {
+    return importRecords(ctx, db, records, opts, func(ctx context.Context, tx *ent.Tx, record *protoapi.UserRecord) error {
+        return importUser(ctx, db, tx, importedBy, record)
+    })
+}
+
+func importUser(ctx context.Context, db *ent.Client, tx *ent.Tx, importedBy uuid.UUID, record *protoapi.UserRecord) error {
+    if !validate.IsUsername(record.Name) {
+        return ErrInvalidUsername
+    }
+    // checking that the domain of every email is reachable would make large imports take minutes
+    if !validate.IsEmailSyntax(record.Email) {
+        return ErrInvalidEmail
+    }
+    passwordHash, err := recordPasswordHash(record)
+    if err != nil {
+        return err
+    }
+
+    roleId, err := tx.Role.Query().Where(role.NameEQ(record.Role)).OnlyID(ctx)
+    if ent.IsNotFound(err) {
+        return ErrUnknownRole
+    }
+    if err != nil {
+        return err
+    }
+    if err = checkRoleAssignable(ctx, tx.Client(), importedBy, roleId); err != nil {
+        return err
+    }
+
+    // checked up front since a failed insert aborts the whole transaction on some databases
+    taken, err := tx.User.Query().Where(user.NameEQ(record.Name)).Exist(ctx)
+    if err != nil {
+        return err
+    }
+    if taken {
+        return ErrUsernameTaken
+    }
+
+    userData, err := tx.User.Create().
+        SetName(record.Name).
+        SetEmail(record.Email).
+        SetPassword(passwordHash).
+        SetRoleID(roleId).
+        Save(ctx)
+    if err != nil {
+        return err
+    }
+
+    return publishEntUser(ctx, db, events.UserCreated, userData)
+}
+
+// recordPasswordHash hashes the password of the record or returns its password hash as it is
+func recordPasswordHash(record *protoapi.UserRecord) (string, error) {
+    switch {
+    case record.Password != "" && record.PasswordHash != "":
+        return "", ErrPasswordAndHash
+    case record.PasswordHash != "":
+        if !hashing.IsHash(record.PasswordHash) {
+            return "", ErrInvalidPasswordHash
+        }
+
+        return record.PasswordHash, nil
+    case record.Password == "" || !validate.IsPassword(record.Password):
+        return "", ErrInvalidPassword
+    }
+
+    return hashing.HashPassword(record.Password), nil
+}
+
+// ImportRoles creates a role for every record, a parent has to exist already or come earlier in the import.
+// Records that can't be imported are listed in the report, the error is only set if the import couldn't run at all.
+func ImportRoles(ctx context.Context, db *ent.Client, records []*protoapi.RoleRecord, opts ImportOptions) (*protoapi.ImportReport, error) {
+    return importRecords(ctx, db, records, opts, func(ctx context.Context, tx *ent.Tx, record *protoapi.RoleRecord) error {
+        return importRole(ctx, db, tx, record)
+    })
+}
+
+func importRole(ctx context.Context, db *ent.Client, tx *ent.Tx, record *protoapi.RoleRecord) error {
+    if !validate.IsRoleNameSyntax(record.Name) {
+        return ErrInvalidRoleName
+    }
+    if !validate.IsPermissionList(record.Permissions) {
+        return ErrInvalidPermission
+    }
+
+    taken, err := tx.Role.Query().Where(role.NameEQ(record.Name)).Exist(ctx)
+    if err != nil {
+        return err
+    }
+    if taken {
+        return ErrRoleNameTaken
+    }
+
+    // the parents already exist, so a new role can't end up in a cycle
+    parentIds := make([]uuid.UUID, len(record.Parents))
+    for i, parent := range record.Parents {
+        parentIds[i], err = tx.Role.Query().Where(role.NameEQ(parent)).OnlyID(ctx)
+        if ent.IsNotFound(err) {
+            return ErrUnknownRoleParent
+        }
+        if err != nil {
+            return err
+        }
+    }
+
+    roleData, err := tx.Role.Create().
+        SetName(record.Name).
+        SetPermissions(record.Permissions).
+        AddParentIDs(parentIds...).
+        Save(ctx)
+    if err != nil {
+        return err
+    }
+
+    roleData, err = tx.Role.Query().Where(role.IDEQ(roleData.ID)).WithParents().Only(ctx)
+    if err != nil {
+        return err
+    }
+
+    return events.RoleCreated.Publish(ctx, db, proto.EntRoleEntityToProtoRole(roleData))
+}
+
+// importRecord is a record of a bulk import, its name identifies it in the report
+type importRecord interface {
+    GetName() string
+}
+
+// importRecords runs importOne for every record and reports how each went. Best-effort imports that aren't
+// dry runs give every record its own transaction, others run in one that's only committed if nothing failed
+// and it isn't a dry run. An error that isn't a domain error stops the import, records a best-effort import
+// committed until then stay.
+func importRecords[R importRecord](ctx context.Context, db *ent.Client, records []R, opts ImportOptions, importOne func(ctx context.Context, tx *ent.Tx, record R) error) (*protoapi.ImportReport, error) {
+    report := &protoapi.ImportReport{
+        DryRun:  opts.DryRun,
+        Records: make([]*protoapi.ImportRecordResult, 0, len(records)),
+    }
+
+    if opts.BestEffort && !opts.DryRun {
+        for i, record := range records {
+            _, err := withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (struct{}, error) {
+                return struct{}{}, importOne(ctx, tx, record)
+            })
+            if err = addImportResult(report, i, record.GetName(), err); err != nil {
+                return nil, err
+            }
+        }
+        report.Committed = report.Succeeded != 0
+
+        return report, nil
+    }
+
+    _, err := withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (struct{}, error) {
+        for i, record := range records {
+            err := importOne(ctx, tx, record)
+            if err = addImportResult(report, i, record.GetName(), err); err != nil {
+                return struct{}{}, err
+            }
+        }
+
+        if opts.DryRun || report.Failed != 0 {
+            return struct{}{}, errImportRolledBack
+        }
+
+        return struct{}{}, nil
+    })
+    if err != nil && !errors.Is(err, errImportRolledBack) {
+        return nil, err
+    }
+    report.Committed = err == nil && report.Succeeded != 0
+
+    return report, nil
+}
+
+// addImportResult adds how importing the record at index went to the report,
+// returning err back if it isn't a domain error
+func addImportResult(report *protoapi.ImportReport, index int, name string, err error) error {
+    result := &protoapi.ImportRecordResult{
+        Row:  int32(index + 1),
+        Name: name,
+    }
+    if err != nil {
+        domainErr := DomainError(err)
+        if domainErr == nil {
+            return err
+        }
+
+        result.Error = domainErr.Message
+        for _, field := range domainErr.Fields {
+            result.FieldErrors = append(result.FieldErrors, &protoapi.FieldError{
+                Field:   field.Field,
+                Message: field.Message,
+            })
+        }
+        report.Failed++
+    } else {
+        report.Succeeded++
+    }
+    report.Records = append(report.Records, result)
+
+    return nil
+}
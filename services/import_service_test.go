@@ -0,0 +1,102 @@
+package services
+
+import (
+    "context"
+    "github.com/Encedeus/panel/ent/role"
+    "github.com/Encedeus/panel/ent/user"
+    "github.com/Encedeus/panel/hashing"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/testdb"
+    "github.com/google/uuid"
+    "testing"
+)
+
+func TestImportRolesModes(t *testing.T) {
+    records := []*protoapi.RoleRecord{
+        {Name: "base", Permissions: []string{"read"}},
+        {Name: "child", Permissions: []string{"write"}, Parents: []string{"base"}},
+        {Name: "orphan", Permissions: []string{"read"}, Parents: []string{"missing"}},
+    }
+
+    tests := []struct {
+        name      string
+        opts      ImportOptions
+        committed bool
+        written   int
+    }{
+        {"transactional", ImportOptions{}, false, 0},
+        {"dry run", ImportOptions{DryRun: true}, false, 0},
+        {"best effort", ImportOptions{BestEffort: true}, true, 2},
+        {"best effort dry run", ImportOptions{DryRun: true, BestEffort: true}, false, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            db := testdb.NewClient(t)
+            ctx := context.Background()
+
+            report, err := ImportRoles(ctx, db, records, tt.opts)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if report.Succeeded != 2 || report.Failed != 1 || report.Committed != tt.committed || report.DryRun != tt.opts.DryRun {
+                t.Errorf("got report %v", report)
+            }
+            if orphan := report.Records[2]; orphan.Row != 3 || orphan.Error != ErrUnknownRoleParent.Message {
+                t.Errorf("got %v for the record with a missing parent", orphan)
+            }
+
+            written, err := db.Role.Query().Where(role.NameIn("base", "child", "orphan")).Count(ctx)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if written != tt.written {
+                t.Errorf("wrote %d roles, want %d", written, tt.written)
+            }
+        })
+    }
+}
+
+func TestImportUsers(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    member := testdb.CreateRole(t, db, "member", []string{"read"})
+    testdb.CreateRole(t, db, "admin", []string{"*"})
+    importer := testdb.CreateUser(t, db, "importer", member.ID)
+
+    hash := hashing.HashPassword("kept-password")
+    records := []*protoapi.UserRecord{
+        {Name: "hashed", Email: "hashed@example.com", PasswordHash: hash, Role: "member"},
+        {Name: "plain", Email: "plain@example.com", Password: "new-password", Role: "member"},
+        {Name: "both", Email: "both@example.com", Password: "new-password", PasswordHash: hash, Role: "member"},
+        {Name: "escalated", Email: "escalated@example.com", Password: "new-password", Role: "admin"},
+    }
+
+    report, err := ImportUsers(ctx, db, importer.ID, records, ImportOptions{BestEffort: true})
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := []string{"", "", ErrPasswordAndHash.Message, ErrRoleNotAssignable.Message}
+    for i, result := range report.Records {
+        if result.Error != want[i] {
+            t.Errorf("record %s got %q, want %q", result.Name, result.Error, want[i])
+        }
+    }
+
+    hashed, err := db.User.Query().Where(user.NameEQ("hashed")).Only(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !hashing.VerifyHash("kept-password", hashed.Password) {
+        t.Error("imported user can't sign in with the password of their hash")
+    }
+
+    // the CLI imports without checking what roles it can assign
+    report, err = ImportUsers(ctx, db, uuid.Nil, records[3:], ImportOptions{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !report.Committed || report.Failed != 0 {
+        t.Errorf("got report %v importing without an importer", report)
+    }
+}
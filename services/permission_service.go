@@ -0,0 +1,174 @@
+package services
+
+import (
+    "context"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/ent/role"
+    "github.com/Encedeus/panel/ent/user"
+    "github.com/google/uuid"
+    "golang.org/x/exp/slices"
+)
+
+// RoleAncestors returns all the roles a role inherits from, nearest ones first
+func RoleAncestors(ctx context.Context, db *ent.Client, roleId uuid.UUID) ([]*ent.Role, error) {
+    visited := map[uuid.UUID]bool{roleId: true}
+    queue := []uuid.UUID{roleId}
+    var ancestors []*ent.Role
+
+    for len(queue) != 0 {
+        parents, err := db.Role.Query().
+            Where(role.HasChildrenWith(role.IDIn(queue...))).
+            All(ctx)
+        if err != nil {
+            return nil, err
+        }
+
+        queue = nil
+        for _, parent := range parents {
+            if visited[parent.ID] {
+                continue
+            }
+
+            visited[parent.ID] = true
+            ancestors = append(ancestors, parent)
+            queue = append(queue, parent.ID)
+        }
+    }
+
+    return ancestors, nil
+}
+
+// RoleEffectivePermissions returns the permissions of a role merged with the ones of its ancestors
+func RoleEffectivePermissions(ctx context.Context, db *ent.Client, roleId uuid.UUID) ([]string, error) {
+    roleData, err := db.Role.Get(ctx, roleId)
+    if err != nil {
+        return nil, err
+    }
+
+    ancestors, err := RoleAncestors(ctx, db, roleId)
+    if err != nil {
+        return nil, err
+    }
+
+    permissions := append([]string{}, roleData.Permissions...)
+    for _, ancestor := range ancestors {
+        permissions = mergePermissions(permissions, ancestor.Permissions)
+    }
+
+    return permissions, nil
+}
+
+// UserEffectivePermissions returns the union of the effective permissions of the user's role
+// and of the roles from its active grants
+func UserEffectivePermissions(ctx context.Context, db *ent.Client, userId uuid.UUID) ([]string, error) {
+    userData, err := db.User.Query().Where(user.IDEQ(userId)).Select(user.FieldRoleID).First(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    permissions, err := RoleEffectivePermissions(ctx, db, userData.RoleID)
+    if err != nil && !ent.IsNotFound(err) {
+        return nil, err
+    }
+
+    grantedRoleIds, err := activeRoleGrantRoleIDs(ctx, db, userId)
+    if err != nil {
+        return nil, err
+    }
+
+    for _, roleId := range grantedRoleIds {
+        granted, err := RoleEffectivePermissions(ctx, db, roleId)
+        if err != nil {
+            // the granted role was deleted
+            if ent.IsNotFound(err) {
+                continue
+            }
+
+            return nil, err
+        }
+
+        permissions = mergePermissions(permissions, granted)
+    }
+
+    return permissions, nil
+}
+
+// CanUserAssignRole checks if the role's effective permissions are a subset of the user's own,
+// so that users can't hand out more privileges than they have
+func CanUserAssignRole(ctx context.Context, db *ent.Client, userId uuid.UUID, roleId uuid.UUID) bool {
+    userPermissions, err := UserEffectivePermissions(ctx, db, userId)
+    if err != nil {
+        return false
+    }
+
+    rolePermissions, err := RoleEffectivePermissions(ctx, db, roleId)
+    if err != nil {
+        return false
+    }
+
+    return isPermissionSubset(rolePermissions, userPermissions)
+}
+
+// checkRoleAssignable makes sure assignedBy can hand out the role, see CanUserAssignRole.
+// uuid.Nil stands for the panel itself, like the CLI, which can assign any role.
+func checkRoleAssignable(ctx context.Context, db *ent.Client, assignedBy uuid.UUID, roleId uuid.UUID) error {
+    if assignedBy != uuid.Nil && !CanUserAssignRole(ctx, db, assignedBy, roleId) {
+        return ErrRoleNotAssignable
+    }
+
+    return nil
+}
+
+// checkRoleParents makes sure none of the parents is the role itself or one of its descendants
+func checkRoleParents(ctx context.Context, db *ent.Client, roleId uuid.UUID, parentIds []uuid.UUID) error {
+    for _, parentId := range parentIds {
+        if parentId == roleId {
+            return ErrRoleCycle
+        }
+
+        exists, err := db.Role.Query().Where(role.IDEQ(parentId)).Exist(ctx)
+        if err != nil {
+            return err
+        }
+        if !exists {
+            return ErrInvalidRoleParent
+        }
+
+        ancestors, err := RoleAncestors(ctx, db, parentId)
+        if err != nil {
+            return err
+        }
+
+        for _, ancestor := range ancestors {
+            if ancestor.ID == roleId {
+                return ErrRoleCycle
+            }
+        }
+    }
+
+    return nil
+}
+
+func hasPermission(permissions []string, permission string) bool {
+    return slices.Contains(permissions, permission) || slices.Contains(permissions, "*")
+}
+
+func isPermissionSubset(subset []string, permissions []string) bool {
+    for _, permission := range subset {
+        if !hasPermission(permissions, permission) {
+            return false
+        }
+    }
+
+    return true
+}
+
+func mergePermissions(permissions []string, other []string) []string {
+    for _, permission := range other {
+        if !slices.Contains(permissions, permission) {
+            permissions = append(permissions, permission)
+        }
+    }
+
+    return permissions
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/testdb"
+    "github.com/google/uuid"
+    "golang.org/x/exp/slices"
+    "testing"
+)
+
+func TestRoleEffectivePermissionsIncludeAncestors(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    base := testdb.CreateRole(t, db, "base", []string{"read"})
+    editor := testdb.CreateRole(t, db, "editor", []string{"write"}, base)
+    lead := testdb.CreateRole(t, db, "lead", []string{"publish", "read"}, editor)
+
+    permissions, err := RoleEffectivePermissions(ctx, db, lead.ID)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    slices.Sort(permissions)
+    if want := []string{"publish", "read", "write"}; !slices.Equal(permissions, want) {
+        t.Errorf("effective permissions are %v, want %v", permissions, want)
+    }
+}
+
+func TestCheckRoleParentsRejectsCycles(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    parent := testdb.CreateRole(t, db, "parent", nil)
+    child := testdb.CreateRole(t, db, "child", nil, parent)
+    grandchild := testdb.CreateRole(t, db, "grandchild", nil, child)
+
+    tests := []struct {
+        name      string
+        roleId    uuid.UUID
+        parentIds []uuid.UUID
+        want      error
+    }{
+        {"itself", parent.ID, []uuid.UUID{parent.ID}, ErrRoleCycle},
+        {"child", parent.ID, []uuid.UUID{child.ID}, ErrRoleCycle},
+        {"grandchild", parent.ID, []uuid.UUID{grandchild.ID}, ErrRoleCycle},
+        {"unknown", parent.ID, []uuid.UUID{uuid.New()}, ErrInvalidRoleParent},
+        {"sibling", grandchild.ID, []uuid.UUID{parent.ID}, nil},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if err := checkRoleParents(ctx, db, tt.roleId, tt.parentIds); !errors.Is(err, tt.want) {
+                t.Errorf("got %v, want %v", err, tt.want)
+            }
+        })
+    }
+}
+
+func TestRoleAssignmentGuard(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    manager := testdb.CreateRole(t, db, "manager", []string{"grant_role", "update_user", "moderate"})
+    moderator := testdb.CreateRole(t, db, "moderator", []string{"moderate"})
+    admin := testdb.CreateRole(t, db, "admin", []string{"*"})
+    grantor := testdb.CreateUser(t, db, "grantor", manager.ID)
+    member := testdb.CreateUser(t, db, "member", moderator.ID)
+
+    grant := func(grantedBy uuid.UUID, roleName string) error {
+        _, err := GrantUserRole(ctx, db, grantedBy, &protoapi.UserRoleGrantRequest{
+            UserId:   proto.UUIDToProtoUUID(member.ID),
+            RoleName: roleName,
+        })
+
+        return err
+    }
+    if err := grant(grantor.ID, moderator.Name); err != nil {
+        t.Errorf("granting a role with a subset of the grantor's permissions failed: %v", err)
+    }
+    if err := grant(grantor.ID, admin.Name); !errors.Is(err, ErrRoleNotAssignable) {
+        t.Errorf("granting a role with more permissions than the grantor returned %v", err)
+    }
+    if err := grant(uuid.Nil, admin.Name); err != nil {
+        t.Errorf("the panel itself couldn't grant a role: %v", err)
+    }
+
+    _, err := UpdateUser(ctx, db, grantor.ID, &protoapi.UserUpdateRequest{
+        UserId:   proto.UUIDToProtoUUID(member.ID),
+        RoleName: admin.Name,
+    })
+    if !errors.Is(err, ErrRoleNotAssignable) {
+        t.Errorf("updating a user to a role with more permissions than the updater returned %v", err)
+    }
+
+    memberData, err := db.User.Get(ctx, member.ID)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if memberData.RoleID != moderator.ID {
+        t.Error("the rejected update changed the role")
+    }
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+    "context"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/ent/apikey"
+    "github.com/Encedeus/panel/ent/role"
+    "github.com/Encedeus/panel/ent/rolegrant"
+    "github.com/Encedeus/panel/ent/schema"
+    "github.com/Encedeus/panel/ent/user"
+    "github.com/Encedeus/panel/tracing"
+    "github.com/google/uuid"
+    "log/slog"
+    "time"
+)
+
+// PurgeSoftDeleted permanently removes users and roles that were soft-deleted before the given time.
+// Roles still referenced by a user are kept until that user is purged as well,
+// grants of purged users and roles are removed with them.
+func PurgeSoftDeleted(ctx context.Context, db *ent.Client, before time.Time) (usersPurged int, rolesPurged int, err error) {
+    ctx = schema.SkipSoftDelete(ctx)
+
+    tx, err := db.Tx(ctx)
+    if err != nil {
+        return 0, 0, err
+    }
+
+    userIds, err := tx.User.Query().Where(user.DeletedAtLT(before)).IDs(ctx)
+    if err != nil {
+        return 0, 0, rollback(tx, err)
+    }
+
+    if len(userIds) != 0 {
+        _, err = tx.ApiKey.Delete().Where(apikey.UserIDIn(userIds...)).Exec(ctx)
+        if err != nil {
+            return 0, 0, rollback(tx, err)
+        }
+        _, err = tx.RoleGrant.Delete().Where(rolegrant.UserIDIn(userIds...)).Exec(ctx)
+        if err != nil {
+            return 0, 0, rollback(tx, err)
+        }
+
+        usersPurged, err = tx.User.Delete().Where(user.IDIn(userIds...)).Exec(ctx)
+        if err != nil {
+            return 0, 0, rollback(tx, err)
+        }
+    }
+
+    var referencedRoleIds []uuid.UUID
+    err = tx.User.Query().Unique(true).Select(user.FieldRoleID).Scan(ctx, &referencedRoleIds)
+    if err != nil {
+        return 0, 0, rollback(tx, err)
+    }
+
+    roleIds, err := tx.Role.Query().
+        Where(role.DeletedAtLT(before), role.IDNotIn(referencedRoleIds...)).
+        IDs(ctx)
+    if err != nil {
+        return 0, 0, rollback(tx, err)
+    }
+
+    if len(roleIds) != 0 {
+        _, err = tx.RoleGrant.Delete().Where(rolegrant.RoleIDIn(roleIds...)).Exec(ctx)
+        if err != nil {
+            return 0, 0, rollback(tx, err)
+        }
+
+        rolesPurged, err = tx.Role.Delete().Where(role.IDIn(roleIds...)).Exec(ctx)
+        if err != nil {
+            return 0, 0, rollback(tx, err)
+        }
+    }
+
+    return usersPurged, rolesPurged, tx.Commit()
+}
+
+// RunSoftDeletePurger purges soft-deleted rows older than the retention period every interval until ctx is done
+func RunSoftDeletePurger(ctx context.Context, db *ent.Client, interval time.Duration, retention func() time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        runCtx, span := tracing.Tracer().Start(ctx, "purge soft-deleted rows")
+        users, roles, err := PurgeSoftDeleted(runCtx, db, time.Now().Add(-retention()))
+        if err != nil {
+            slog.ErrorContext(runCtx, "error purging soft-deleted rows", "error", err)
+        } else if users != 0 || roles != 0 {
+            slog.InfoContext(runCtx, "purged soft-deleted rows", "users", users, "roles", roles)
+        }
+        span.End()
+
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
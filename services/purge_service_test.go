@@ -0,0 +1,73 @@
+package services
+
+import (
+    "context"
+    "github.com/Encedeus/panel/ent/schema"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/testdb"
+    "github.com/google/uuid"
+    "testing"
+    "time"
+)
+
+func TestPurgeSoftDeleted(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    kept := testdb.CreateRole(t, db, "kept", nil)
+    unused := testdb.CreateRole(t, db, "unused", nil)
+    referenced := testdb.CreateRole(t, db, "referenced", nil)
+    member := testdb.CreateUser(t, db, "member", referenced.ID)
+    recent := testdb.CreateUser(t, db, "recent", kept.ID)
+
+    if _, err := GrantUserRole(ctx, db, uuid.Nil, &protoapi.UserRoleGrantRequest{
+        UserId: proto.UUIDToProtoUUID(member.ID),
+        RoleId: proto.UUIDToProtoUUID(unused.ID),
+    }); err != nil {
+        t.Fatal(err)
+    }
+    for _, r := range []uuid.UUID{unused.ID, referenced.ID} {
+        if _, err := DeleteRole(ctx, db, &protoapi.RoleDeleteRequest{Id: proto.UUIDToProtoUUID(r)}); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if _, err := DeleteUser(ctx, db, &protoapi.UserDeleteRequest{UserId: proto.UUIDToProtoUUID(recent.ID)}); err != nil {
+        t.Fatal(err)
+    }
+
+    // only what was deleted before the cutoff is purged, and the role of a user that's kept stays
+    cutoff := time.Now().Add(time.Second)
+    if err := db.User.UpdateOne(recent).SetDeletedAt(cutoff.Add(time.Hour)).Exec(schema.SkipSoftDelete(ctx)); err != nil {
+        t.Fatal(err)
+    }
+    users, roles, err := PurgeSoftDeleted(ctx, db, cutoff)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if users != 0 || roles != 1 {
+        t.Errorf("purged %d users and %d roles, want 0 and 1", users, roles)
+    }
+    if grants, _ := db.RoleGrant.Query().Count(ctx); grants != 0 {
+        t.Errorf("%d grants of the purged role are left", grants)
+    }
+
+    // once its last user is purged, the role goes too
+    if _, err = DeleteUser(ctx, db, &protoapi.UserDeleteRequest{UserId: proto.UUIDToProtoUUID(member.ID)}); err != nil {
+        t.Fatal(err)
+    }
+    if users, roles, err = PurgeSoftDeleted(ctx, db, cutoff); err != nil {
+        t.Fatal(err)
+    }
+    if users != 1 || roles != 1 {
+        t.Errorf("purged %d users and %d roles, want 1 and 1", users, roles)
+    }
+
+    left, err := db.Role.Query().Count(schema.SkipSoftDelete(ctx))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if left != 1 {
+        t.Errorf("%d roles are left, want only the one that wasn't deleted", left)
+    }
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+    "encoding/csv"
+    "errors"
+    "fmt"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "golang.org/x/exp/slices"
+    "google.golang.org/protobuf/encoding/protojson"
+    "google.golang.org/protobuf/proto"
+    "io"
+    "strings"
+)
+
+// Format is how user and role records are encoded for import and export
+type Format string
+
+const (
+    // FormatJSON is the UserImportRequest or RoleImportRequest message, exports are the matching export response
+    FormatJSON Format = "json"
+    // FormatCSV has a header row naming the columns, in any order, and one record per row
+    FormatCSV Format = "csv"
+)
+
+// ParseFormat parses the name of a format, an empty name is JSON
+func ParseFormat(name string) (Format, error) {
+    switch Format(strings.ToLower(name)) {
+    case FormatJSON, "":
+        return FormatJSON, nil
+    case FormatCSV:
+        return FormatCSV, nil
+    }
+
+    return "", NewFieldError("format", fmt.Sprintf("unknown format %s, expected json or csv", name))
+}
+
+// csvListSeparator separates the permissions and parents of a role in a single CSV column
+const csvListSeparator = ";"
+
+var (
+    userColumns = []string{"name", "email", "password", "password_hash", "role"}
+    roleColumns = []string{"name", "permissions", "parents"}
+)
+
+// ReadUserRecords decodes the users of an import
+func ReadUserRecords(r io.Reader, format Format) ([]*protoapi.UserRecord, error) {
+    if format == FormatJSON {
+        req := new(protoapi.UserImportRequest)
+        if err := readJSON(r, req); err != nil {
+            return nil, err
+        }
+
+        return req.Users, nil
+    }
+
+    rows, err := readCSV(r, userColumns, "name", "email", "role")
+    if err != nil {
+        return nil, err
+    }
+
+    records := make([]*protoapi.UserRecord, len(rows))
+    for i, row := range rows {
+        records[i] = &protoapi.UserRecord{
+            Name:         row["name"],
+            Email:        row["email"],
+            Password:     row["password"],
+            PasswordHash: row["password_hash"],
+            Role:         row["role"],
+        }
+    }
+
+    return records, nil
+}
+
+// ReadRoleRecords decodes the roles of an import
+func ReadRoleRecords(r io.Reader, format Format) ([]*protoapi.RoleRecord, error) {
+    if format == FormatJSON {
+        req := new(protoapi.RoleImportRequest)
+        if err := readJSON(r, req); err != nil {
+            return nil, err
+        }
+
+        return req.Roles, nil
+    }
+
+    rows, err := readCSV(r, roleColumns, "name")
+    if err != nil {
+        return nil, err
+    }
+
+    records := make([]*protoapi.RoleRecord, len(rows))
+    for i, row := range rows {
+        records[i] = &protoapi.RoleRecord{
+            Name:        row["name"],
+            Permissions: splitCSVList(row["permissions"]),
+            Parents:     splitCSVList(row["parents"]),
+        }
+    }
+
+    return records, nil
+}
+
+// WriteUserRecords encodes the users of an export, they can be imported as they are
+func WriteUserRecords(w io.Writer, format Format, records []*protoapi.UserRecord) error {
+    if format == FormatJSON {
+        return writeJSON(w, &protoapi.UserExportResponse{Users: records})
+    }
+
+    rows := make([][]string, len(records))
+    for i, record := range records {
+        rows[i] = []string{record.Name, record.Email, record.Password, record.PasswordHash, record.Role}
+    }
+
+    return writeCSV(w, userColumns, rows)
+}
+
+// WriteRoleRecords encodes the roles of an export, they can be imported as they are
+func WriteRoleRecords(w io.Writer, format Format, records []*protoapi.RoleRecord) error {
+    if format == FormatJSON {
+        return writeJSON(w, &protoapi.RoleExportResponse{Roles: records})
+    }
+
+    rows := make([][]string, len(records))
+    for i, record := range records {
+        rows[i] = []string{
+            record.Name,
+            strings.Join(record.Permissions, csvListSeparator),
+            strings.Join(record.Parents, csvListSeparator),
+        }
+    }
+
+    return writeCSV(w, roleColumns, rows)
+}
+
+func readJSON(r io.Reader, req proto.Message) error {
+    body, err := io.ReadAll(r)
+    if err != nil {
+        return err
+    }
+    if err = protojson.Unmarshal(body, req); err != nil {
+        return NewValidationError("invalid JSON: " + err.Error())
+    }
+
+    return ValidateRequest(req)
+}
+
+func writeJSON(w io.Writer, resp proto.Message) error {
+    body, err := protojson.MarshalOptions{Multiline: true}.Marshal(resp)
+    if err != nil {
+        return err
+    }
+
+    _, err = w.Write(append(body, '\n'))
+
+    return err
+}
+
+// readCSV reads the rows after the header as maps from column name to value, the header may only name known columns
+// and has to name the required ones, the values of known columns it leaves out are empty.
+// Values aren't trimmed, passwords may start or end with spaces
+func readCSV(r io.Reader, known []string, required ...string) ([]map[string]string, error) {
+    reader := csv.NewReader(r)
+
+    header, err := reader.Read()
+    if errors.Is(err, io.EOF) {
+        return nil, NewValidationError("invalid CSV: missing header")
+    }
+    if err != nil {
+        return nil, NewValidationError("invalid CSV: " + err.Error())
+    }
+
+    for i, column := range header {
+        header[i] = strings.ToLower(strings.TrimSpace(column))
+        if !slices.Contains(known, header[i]) {
+            return nil, NewValidationError(fmt.Sprintf("invalid CSV: unknown column %s, expected %s", column, strings.Join(known, ", ")))
+        }
+    }
+    for _, column := range required {
+        if !slices.Contains(header, column) {
+            return nil, NewValidationError("invalid CSV: missing column " + column)
+        }
+    }
+
+    var rows []map[string]string
+    for {
+        values, err := reader.Read()
+        if errors.Is(err, io.EOF) {
+            return rows, nil
+        }
+        if err != nil {
+            return nil, NewValidationError("invalid CSV: " + err.Error())
+        }
+
+        row := make(map[string]string, len(header))
+        for i, column := range header {
+            row[column] = values[i]
+        }
+        rows = append(rows, row)
+    }
+}
+
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+    writer := csv.NewWriter(w)
+    if err := writer.Write(header); err != nil {
+        return err
+    }
+    if err := writer.WriteAll(rows); err != nil {
+        return err
+    }
+
+    return writer.Error()
+}
+
+func splitCSVList(value string) []string {
+    var items []string
+    for _, item := range strings.Split(value, csvListSeparator) {
+        if item = strings.TrimSpace(item); item != "" {
+            items = append(items, item)
+        }
+    }
+
+    return items
+}
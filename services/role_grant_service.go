@@ -0,0 +1,168 @@
+package services
+
+import (
+    "context"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/ent/predicate"
+    "github.com/Encedeus/panel/ent/rolegrant"
+    "github.com/Encedeus/panel/events"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/tracing"
+    "github.com/Encedeus/panel/validate"
+    "github.com/google/uuid"
+    "log/slog"
+    "time"
+)
+
+// GrantUserRole gives a user an additional role, optionally until req.ExpiresAt.
+// grantedBy is uuid.Nil for grants that didn't come from a user, e.g. from the CLI,
+// otherwise it has to be able to assign the role
+func GrantUserRole(ctx context.Context, db *ent.Client, grantedBy uuid.UUID, req *protoapi.UserRoleGrantRequest) (*protoapi.UserRoleGrantResponse, error) {
+    if req.ExpiresAt != nil && !req.ExpiresAt.AsTime().After(time.Now()) {
+        return nil, ErrInvalidGrantExpiry
+    }
+
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.UserRoleGrantResponse, error) {
+        if !validate.IsUserId(ctx, tx.Client(), req.UserId) {
+            return nil, ErrInvalidUserId
+        }
+
+        roleId, err := FindRoleID(ctx, tx.Client(), req.RoleId, req.RoleName)
+        if err != nil {
+            return nil, err
+        }
+        if err = checkRoleAssignable(ctx, tx.Client(), grantedBy, roleId); err != nil {
+            return nil, err
+        }
+
+        create := tx.RoleGrant.Create().
+            SetUserID(proto.ProtoUUIDToUUID(req.UserId)).
+            SetRoleID(roleId)
+        if grantedBy != uuid.Nil {
+            create.SetGrantedBy(grantedBy)
+        }
+        if req.ExpiresAt != nil {
+            create.SetExpiresAt(req.ExpiresAt.AsTime())
+        }
+
+        grant, err := create.Save(ctx)
+        if err != nil {
+            return nil, err
+        }
+
+        resp := &protoapi.UserRoleGrantResponse{
+            RoleGrant: proto.EntRoleGrantToProtoRoleGrant(grant),
+        }
+        if err := events.RoleGranted.Publish(ctx, db, resp.RoleGrant); err != nil {
+            return nil, err
+        }
+
+        return resp, nil
+    })
+}
+
+// RevokeUserRole removes a role grant from a user
+func RevokeUserRole(ctx context.Context, db *ent.Client, req *protoapi.UserRoleRevokeRequest) (*protoapi.UserRoleRevokeResponse, error) {
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.UserRoleRevokeResponse, error) {
+        deleted, err := tx.RoleGrant.Delete().
+            Where(
+                rolegrant.IDEQ(proto.ProtoUUIDToUUID(req.GrantId)),
+                rolegrant.UserIDEQ(proto.ProtoUUIDToUUID(req.UserId)),
+            ).
+            Exec(ctx)
+        if err != nil {
+            return nil, err
+        }
+        if deleted == 0 {
+            return nil, ErrRoleGrantNotFound
+        }
+        err = events.RoleRevoked.Publish(ctx, db, &protoapi.RoleGrant{
+            Id:     req.GrantId,
+            UserId: req.UserId,
+        })
+        if err != nil {
+            return nil, err
+        }
+
+        resp := &protoapi.UserRoleRevokeResponse{}
+
+        return resp, nil
+    })
+}
+
+// FindUserRoleGrants returns the active role grants of a user
+func FindUserRoleGrants(ctx context.Context, db *ent.Client, req *protoapi.UserRoleGrantFindManyRequest) (*protoapi.UserRoleGrantFindManyResponse, error) {
+    if !validate.IsUserId(ctx, db, req.UserId) {
+        return nil, ErrInvalidUserId
+    }
+
+    grants, err := db.RoleGrant.Query().
+        Where(rolegrant.UserIDEQ(proto.ProtoUUIDToUUID(req.UserId)), isRoleGrantActive()).
+        Order(rolegrant.ByCreatedAt()).
+        All(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    resp := &protoapi.UserRoleGrantFindManyResponse{}
+    resp.RoleGrants = make([]*protoapi.RoleGrant, len(grants))
+    for i, grant := range grants {
+        resp.RoleGrants[i] = proto.EntRoleGrantToProtoRoleGrant(grant)
+    }
+
+    return resp, nil
+}
+
+// activeRoleGrantRoleIDs returns the ids of the roles granted to a user that haven't expired yet
+func activeRoleGrantRoleIDs(ctx context.Context, db *ent.Client, userId uuid.UUID) ([]uuid.UUID, error) {
+    var roleIds []uuid.UUID
+    err := db.RoleGrant.Query().
+        Where(rolegrant.UserIDEQ(userId), isRoleGrantActive()).
+        Select(rolegrant.FieldRoleID).
+        Scan(ctx, &roleIds)
+
+    return roleIds, err
+}
+
+// ExpireRoleGrants marks all the grants past their expiry time as expired
+func ExpireRoleGrants(ctx context.Context, db *ent.Client, now time.Time) (int, error) {
+    return db.RoleGrant.Update().
+        Where(rolegrant.ExpiredAtIsNil(), rolegrant.ExpiresAtLTE(now)).
+        SetExpiredAt(now).
+        Save(ctx)
+}
+
+// RunRoleGrantExpirer expires role grants every interval until ctx is done
+func RunRoleGrantExpirer(ctx context.Context, db *ent.Client, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        runCtx, span := tracing.Tracer().Start(ctx, "expire role grants")
+        expired, err := ExpireRoleGrants(runCtx, db, time.Now())
+        if err != nil {
+            slog.ErrorContext(runCtx, "error expiring role grants", "error", err)
+        } else if expired != 0 {
+            slog.InfoContext(runCtx, "expired role grants", "count", expired)
+        }
+        span.End()
+
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+// isRoleGrantActive doesn't rely on the expiry job so grants stop applying as soon as they expire
+func isRoleGrantActive() predicate.RoleGrant {
+    return rolegrant.And(
+        rolegrant.ExpiredAtIsNil(),
+        rolegrant.Or(
+            rolegrant.ExpiresAtIsNil(),
+            rolegrant.ExpiresAtGT(time.Now()),
+        ),
+    )
+}
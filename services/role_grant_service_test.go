@@ -0,0 +1,79 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/ent/rolegrant"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/testdb"
+    "github.com/google/uuid"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "testing"
+    "time"
+)
+
+func TestRoleGrantAddsPermissionsUntilItExpires(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    member := testdb.CreateRole(t, db, "member", []string{"read"})
+    writer := testdb.CreateRole(t, db, "writer", []string{"write"})
+    u := testdb.CreateUser(t, db, "grantee", member.ID)
+
+    _, err := GrantUserRole(ctx, db, uuid.Nil, &protoapi.UserRoleGrantRequest{
+        UserId:    proto.UUIDToProtoUUID(u.ID),
+        RoleId:    proto.UUIDToProtoUUID(writer.ID),
+        ExpiresAt: timestamppb.New(time.Now().Add(time.Hour)),
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !DoesUserHavePermission(ctx, db, "read", u.ID) || !DoesUserHavePermission(ctx, db, "write", u.ID) {
+        t.Fatal("user is missing the permissions of their role or their grant")
+    }
+
+    // expiring the grant in the database, GrantUserRole only accepts future expiries
+    past := time.Now().Add(-time.Minute)
+    if err = db.RoleGrant.Update().Where(rolegrant.UserIDEQ(u.ID)).SetExpiresAt(past).Exec(ctx); err != nil {
+        t.Fatal(err)
+    }
+    if DoesUserHavePermission(ctx, db, "write", u.ID) {
+        t.Error("expired grant still gives its permissions before the expirer ran")
+    }
+    grants, err := FindUserRoleGrants(ctx, db, &protoapi.UserRoleGrantFindManyRequest{UserId: proto.UUIDToProtoUUID(u.ID)})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(grants.RoleGrants) != 0 {
+        t.Errorf("found %d grants, want the expired grant left out", len(grants.RoleGrants))
+    }
+
+    expired, err := ExpireRoleGrants(ctx, db, time.Now())
+    if err != nil {
+        t.Fatal(err)
+    }
+    if expired != 1 {
+        t.Errorf("expired %d grants, want 1", expired)
+    }
+    if expired, _ = ExpireRoleGrants(ctx, db, time.Now()); expired != 0 {
+        t.Errorf("expired %d grants again, want 0", expired)
+    }
+}
+
+func TestGrantUserRoleRejectsPastExpiry(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    member := testdb.CreateRole(t, db, "member", nil)
+    u := testdb.CreateUser(t, db, "grantee", member.ID)
+
+    _, err := GrantUserRole(ctx, db, uuid.Nil, &protoapi.UserRoleGrantRequest{
+        UserId:    proto.UUIDToProtoUUID(u.ID),
+        RoleId:    proto.UUIDToProtoUUID(member.ID),
+        ExpiresAt: timestamppb.New(time.Now().Add(-time.Hour)),
+    })
+    if !errors.Is(err, ErrInvalidGrantExpiry) {
+        t.Errorf("got %v, want %v", err, ErrInvalidGrantExpiry)
+    }
+}
@@ -2,95 +2,154 @@ package services
 
 import (
     "context"
-    "errors"
     "github.com/Encedeus/panel/ent"
     "github.com/Encedeus/panel/ent/role"
+    "github.com/Encedeus/panel/ent/schema"
+    "github.com/Encedeus/panel/events"
     "github.com/Encedeus/panel/proto"
     protoapi "github.com/Encedeus/panel/proto/go"
     "github.com/Encedeus/panel/validate"
-    "google.golang.org/protobuf/types/known/timestamppb"
+    "github.com/google/uuid"
     "strings"
-    "time"
 )
 
 func CreateRole(ctx context.Context, db *ent.Client, req *protoapi.RoleCreateRequest) (*protoapi.RoleCreateResponse, error) {
-    if !validate.IsRoleName(ctx, db, req.Name) {
-        return nil, ErrInvalidRoleName
-    }
     if !validate.IsPermissionList(req.Permissions) {
         return nil, ErrInvalidPermission
     }
+    parentIds, err := proto.ParseProtoUUIDs(req.ParentIds)
+    if err != nil {
+        return nil, ErrInvalidRoleParent
+    }
 
-    roleData, err := db.Role.Create().
-        SetName(req.Name).
-        SetPermissions(req.Permissions).
-        Save(ctx)
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.RoleCreateResponse, error) {
+        if err := checkRoleName(ctx, tx.Client(), uuid.Nil, req.Name); err != nil {
+            return nil, err
+        }
+        // a new role has no descendants, so this only checks that the parents exist
+        if err := checkRoleParents(ctx, tx.Client(), uuid.Nil, parentIds); err != nil {
+            return nil, err
+        }
 
-    resp := &protoapi.RoleCreateResponse{
-        Role: &protoapi.Role{
-            Id:          proto.UUIDToProtoUUID(roleData.ID),
-            CreatedAt:   timestamppb.New(roleData.CreatedAt),
-            UpdatedAt:   timestamppb.New(roleData.UpdatedAt),
-            DeletedAt:   timestamppb.New(roleData.DeletedAt),
-            Name:        roleData.Name,
-            Permissions: roleData.Permissions,
-        },
-    }
+        roleData, err := tx.Role.Create().
+            SetName(req.Name).
+            SetPermissions(req.Permissions).
+            AddParentIDs(parentIds...).
+            Save(ctx)
+        if err != nil {
+            if ent.IsConstraintError(err) {
+                return nil, ErrRoleNameTaken
+            }
 
-    return resp, err
+            return nil, err
+        }
+
+        roleData, err = tx.Role.Query().Where(role.IDEQ(roleData.ID)).WithParents().Only(ctx)
+        if err != nil {
+            return nil, err
+        }
+
+        resp := &protoapi.RoleCreateResponse{
+            Role: proto.EntRoleEntityToProtoRole(roleData),
+        }
+        if err := events.RoleCreated.Publish(ctx, db, resp.Role); err != nil {
+            return nil, err
+        }
+
+        return resp, nil
+    })
 }
 
-func UpdateRole(ctx context.Context, db *ent.Client, req *protoapi.RoleUpdateRequest) (*protoapi.RoleUpdateResponse, error) {
-    if !validate.IsRoleName(ctx, db, req.Name) {
-        return nil, ErrInvalidRoleName
+// checkRoleName makes sure name can be given to the role roleId, or to a new role if it's uuid.Nil.
+// Deleted roles don't keep their names, see the partial index of the role schema
+func checkRoleName(ctx context.Context, db *ent.Client, roleId uuid.UUID, name string) error {
+    if !validate.IsRoleNameSyntax(name) {
+        return ErrInvalidRoleName
     }
-    if !validate.IsRoleID(ctx, db, req.Id) {
-        return nil, ErrInvalidRoleID
+
+    taken, err := db.Role.Query().Where(role.NameEQ(name), role.IDNEQ(roleId)).Exist(ctx)
+    if err != nil {
+        return err
+    }
+    if taken {
+        return ErrRoleNameTaken
     }
+
+    return nil
+}
+
+func UpdateRole(ctx context.Context, db *ent.Client, req *protoapi.RoleUpdateRequest) (*protoapi.RoleUpdateResponse, error) {
     if !validate.IsPermissionList(req.Permissions) {
         return nil, ErrInvalidPermission
     }
-
-    roleData, err := db.Role.Get(ctx, proto.ProtoUUIDToUUID(req.Id))
+    parentIds, err := proto.ParseProtoUUIDs(req.ParentIds)
     if err != nil {
-        return nil, err
+        return nil, ErrInvalidRoleParent
     }
 
-    if IsRoleDeleted(roleData) {
-        return nil, errors.New("role deleted")
-    }
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.RoleUpdateResponse, error) {
+        client := tx.Client()
+        if isRoleSoftDeleted(ctx, client, req.Id) {
+            return nil, ErrRoleDeleted
+        }
+        if !validate.IsRoleID(ctx, client, req.Id) {
+            return nil, ErrInvalidRoleID
+        }
+        // an empty name keeps the current one
+        if req.Name != "" {
+            if err := checkRoleName(ctx, client, proto.ProtoUUIDToUUID(req.Id), req.Name); err != nil {
+                return nil, err
+            }
+        }
 
-    if req.Name != "" {
-        _, err = roleData.Update().SetName(req.Name).Save(ctx)
+        roleData, err := tx.Role.Get(ctx, proto.ProtoUUIDToUUID(req.Id))
         if err != nil {
             return nil, err
         }
-    }
+        if err = checkVersion(ctx, roleData.Version); err != nil {
+            return nil, err
+        }
+
+        // fails if another request changed the role since it was read
+        update := roleData.Update().Where(role.VersionEQ(roleData.Version))
+        if req.Name != "" {
+            update.SetName(req.Name)
+        }
+        if len(req.Permissions) != 0 {
+            update.SetPermissions(req.Permissions)
+        }
+        if len(req.ParentIds) != 0 || req.ClearParents {
+            if err = checkRoleParents(ctx, client, roleData.ID, parentIds); err != nil {
+                return nil, err
+            }
+            update.ClearParents().AddParentIDs(parentIds...)
+        }
+
+        if _, err = update.Save(ctx); err != nil {
+            if ent.IsNotFound(err) {
+                return nil, ErrVersionMismatch
+            }
+            if ent.IsConstraintError(err) {
+                return nil, ErrRoleNameTaken
+            }
 
-    if s := req.Id.Value; len(req.Permissions) != 0 && s != "" {
-        _, err = roleData.Update().SetPermissions(req.Permissions).Save(ctx)
-        if err != nil {
             return nil, err
         }
-    }
 
-    roleData, err = db.Role.Get(ctx, proto.ProtoUUIDToUUID(req.Id))
-    if err != nil {
-        return nil, err
-    }
+        roleData, err = tx.Role.Query().Where(role.IDEQ(roleData.ID)).WithParents().Only(ctx)
+        if err != nil {
+            return nil, err
+        }
 
-    resp := &protoapi.RoleUpdateResponse{
-        Role: &protoapi.Role{
-            Id:          proto.UUIDToProtoUUID(roleData.ID),
-            CreatedAt:   timestamppb.New(roleData.CreatedAt),
-            UpdatedAt:   timestamppb.New(roleData.UpdatedAt),
-            DeletedAt:   timestamppb.New(roleData.DeletedAt),
-            Name:        roleData.Name,
-            Permissions: roleData.Permissions,
-        },
-    }
+        resp := &protoapi.RoleUpdateResponse{
+            Role: proto.EntRoleEntityToProtoRole(roleData),
+        }
+        if err := events.RoleUpdated.Publish(ctx, db, resp.Role); err != nil {
+            return nil, err
+        }
 
-    return resp, err
+        return resp, nil
+    })
 }
 
 func DeleteRole(ctx context.Context, db *ent.Client, req *protoapi.RoleDeleteRequest) (*protoapi.RoleDeleteResponse, error) {
@@ -98,35 +157,80 @@ func DeleteRole(ctx context.Context, db *ent.Client, req *protoapi.RoleDeleteReq
         return nil, nil
     }
 
-    roleData, err := db.Role.Get(ctx, proto.ProtoUUIDToUUID(req.Id))
-    if err != nil {
-        return nil, err
-    }
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.RoleDeleteResponse, error) {
+        roleId := proto.ProtoUUIDToUUID(req.Id)
+        deletion := tx.Role.DeleteOneID(roleId)
+        if versions, ok := ifMatch(ctx); ok {
+            deletion.Where(role.VersionIn(versions...))
+        }
 
-    if IsRoleDeleted(roleData) {
-        return nil, errors.New("already deleted")
-    }
+        err := deletion.Exec(ctx)
+        if ent.IsNotFound(err) {
+            if isRoleSoftDeleted(ctx, tx.Client(), req.Id) {
+                return nil, ErrAlreadyDeleted
+            }
+            // the role exists, so the If-Match header didn't match it
+            if exists, _ := tx.Role.Query().Where(role.IDEQ(roleId)).Exist(ctx); exists {
+                return nil, ErrVersionMismatch
+            }
+        }
+        if err != nil {
+            return nil, err
+        }
 
-    _, err = roleData.Update().SetDeletedAt(time.Now()).Save(ctx)
-    if err != nil {
-        return nil, err
-    }
+        if err := publishRoleDeleted(ctx, db, roleId); err != nil {
+            return nil, err
+        }
 
-    resp := &protoapi.RoleDeleteResponse{}
+        resp := &protoapi.RoleDeleteResponse{}
 
-    return resp, nil
+        return resp, nil
+    })
+}
+
+// RestoreRole undoes the soft-deletion of a role
+func RestoreRole(ctx context.Context, db *ent.Client, roleId uuid.UUID) (*protoapi.Role, error) {
+    ctx = schema.SkipSoftDelete(ctx)
+
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.Role, error) {
+        roleData, err := tx.Role.Get(ctx, roleId)
+        if err != nil {
+            return nil, err
+        }
+
+        if roleData.DeletedAt.IsZero() {
+            return nil, ErrRoleNotDeleted
+        }
+
+        roleData, err = roleData.Update().ClearDeletedAt().Save(ctx)
+        if err != nil {
+            if ent.IsConstraintError(err) {
+                return nil, ErrRoleNameTaken
+            }
+
+            return nil, err
+        }
+
+        protoRole := proto.EntRoleEntityToProtoRole(roleData)
+        if err := events.RoleRestored.Publish(ctx, db, protoRole); err != nil {
+            return nil, err
+        }
+
+        return protoRole, nil
+    })
 }
 
 func FindRole(ctx context.Context, db *ent.Client, req *protoapi.RoleFindOneRequest) (*protoapi.RoleFindOneResponse, error) {
     roleData, err := db.Role.Query().
         Where(role.IDEQ(proto.ProtoUUIDToUUID(req.Id))).
+        WithParents().
         First(ctx)
     if err != nil {
-        return nil, err
-    }
+        if ent.IsNotFound(err) && isRoleSoftDeleted(ctx, db, req.Id) {
+            return nil, ErrRoleDeleted
+        }
 
-    if IsRoleDeleted(roleData) {
-        return nil, errors.New("role deleted")
+        return nil, err
     }
 
     resp := &protoapi.RoleFindOneResponse{
@@ -136,6 +240,54 @@ func FindRole(ctx context.Context, db *ent.Client, req *protoapi.RoleFindOneRequ
     return resp, err
 }
 
-func IsRoleDeleted(role *ent.Role) bool {
-    return role.DeletedAt.Unix() != -62135596800
+// FindRoleEffectivePermissions returns the permissions of a role including the inherited ones
+func FindRoleEffectivePermissions(ctx context.Context, db *ent.Client, req *protoapi.RoleFindOneRequest) (*protoapi.RoleEffectivePermissionsResponse, error) {
+    roleId := proto.ProtoUUIDToUUID(req.Id)
+
+    permissions, err := RoleEffectivePermissions(ctx, db, roleId)
+    if err != nil {
+        if ent.IsNotFound(err) && isRoleSoftDeleted(ctx, db, req.Id) {
+            return nil, ErrRoleDeleted
+        }
+
+        return nil, err
+    }
+
+    ancestors, err := RoleAncestors(ctx, db, roleId)
+    if err != nil {
+        return nil, err
+    }
+
+    resp := &protoapi.RoleEffectivePermissionsResponse{
+        Id:          req.Id,
+        Permissions: permissions,
+    }
+    for _, ancestor := range ancestors {
+        resp.AncestorIds = append(resp.AncestorIds, proto.UUIDToProtoUUID(ancestor.ID))
+    }
+
+    return resp, nil
+}
+
+// FindRoleID returns the id of a role given either its id or its name, the id takes precedence
+func FindRoleID(ctx context.Context, db *ent.Client, id *protoapi.UUID, name string) (uuid.UUID, error) {
+    if roleId, err := uuid.Parse(id.GetValue()); err == nil {
+        return roleId, nil
+    }
+
+    return db.Role.Query().Where(role.NameEQ(name)).OnlyID(ctx)
+}
+
+// isRoleSoftDeleted checks if a role exists but was soft-deleted
+func isRoleSoftDeleted(ctx context.Context, db *ent.Client, id *protoapi.UUID) bool {
+    roleId, err := uuid.Parse(id.Value)
+    if err != nil {
+        return false
+    }
+
+    exists, err := db.Role.Query().
+        Where(role.IDEQ(roleId), role.DeletedAtNotNil()).
+        Exist(schema.SkipSoftDelete(ctx))
+
+    return err == nil && exists
 }
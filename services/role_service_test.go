@@ -0,0 +1,73 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/testdb"
+    "testing"
+)
+
+func TestCreateRoleChecksName(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    testdb.CreateRole(t, db, "existing", nil)
+
+    tests := []struct {
+        name string
+        role string
+        want error
+    }{
+        {"new", "moderator", nil},
+        {"taken", "existing", ErrRoleNameTaken},
+        {"too short", "ab", ErrInvalidRoleName},
+        {"markup", "<b>bold</b>", ErrInvalidRoleName},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := CreateRole(ctx, db, &protoapi.RoleCreateRequest{Name: tt.role, Permissions: []string{"read"}})
+            if !errors.Is(err, tt.want) {
+                t.Errorf("got %v, want %v", err, tt.want)
+            }
+        })
+    }
+}
+
+func TestUpdateRoleChecksName(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    r := testdb.CreateRole(t, db, "editor", []string{"read"})
+    testdb.CreateRole(t, db, "existing", nil)
+
+    tests := []struct {
+        name     string
+        req      *protoapi.RoleUpdateRequest
+        want     error
+        wantName string
+    }{
+        {"keeps name if empty", &protoapi.RoleUpdateRequest{Permissions: []string{"write"}}, nil, "editor"},
+        {"own name", &protoapi.RoleUpdateRequest{Name: "editor"}, nil, "editor"},
+        {"taken", &protoapi.RoleUpdateRequest{Name: "existing"}, ErrRoleNameTaken, "editor"},
+        {"invalid", &protoapi.RoleUpdateRequest{Name: "ab"}, ErrInvalidRoleName, "editor"},
+        {"renamed", &protoapi.RoleUpdateRequest{Name: "writer"}, nil, "writer"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            tt.req.Id = proto.UUIDToProtoUUID(r.ID)
+            if _, err := UpdateRole(ctx, db, tt.req); !errors.Is(err, tt.want) {
+                t.Errorf("got %v, want %v", err, tt.want)
+            }
+
+            updated, err := db.Role.Get(ctx, r.ID)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if updated.Name != tt.wantName {
+                t.Errorf("role is named %s, want %s", updated.Name, tt.wantName)
+            }
+        })
+    }
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+    "context"
+    "crypto/rand"
+    "crypto/subtle"
+    "encoding/hex"
+    "errors"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/ent/role"
+    "github.com/Encedeus/panel/ent/schema"
+    "github.com/Encedeus/panel/events"
+    "github.com/Encedeus/panel/hashing"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/validate"
+    "io/fs"
+    "os"
+    "strings"
+)
+
+// IsSetupComplete checks if the first user was created, deleted users count since they can be restored
+func IsSetupComplete(ctx context.Context, db *ent.Client) (bool, error) {
+    return db.User.Query().Exist(schema.SkipSoftDelete(ctx))
+}
+
+// EnsureSetupToken returns the setup token, generating one if there's none yet
+func EnsureSetupToken(path string) (string, error) {
+    token, err := readSetupToken(path)
+    if err == nil {
+        return token, nil
+    }
+    if !errors.Is(err, fs.ErrNotExist) {
+        return "", err
+    }
+
+    return NewSetupToken(path)
+}
+
+// NewSetupToken generates a setup token, replacing the previous one
+func NewSetupToken(path string) (string, error) {
+    b := make([]byte, 32)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    token := hex.EncodeToString(b)
+
+    if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+        return "", err
+    }
+
+    return token, nil
+}
+
+func readSetupToken(path string) (string, error) {
+    b, err := os.ReadFile(path)
+    if err != nil {
+        return "", err
+    }
+
+    return strings.TrimSpace(string(b)), nil
+}
+
+// isSetupToken compares token against the one on disk, the file is read every time
+// so a token generated by the CLI works without restarting the panel
+func isSetupToken(token string) bool {
+    expected, err := readSetupToken(config.Config.Auth.SetupTokenFile)
+    if err != nil || expected == "" {
+        return false
+    }
+
+    return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// CompleteSetup creates the first user with the superuser role and removes the setup token
+func CompleteSetup(ctx context.Context, db *ent.Client, req *protoapi.SetupRequest) (*protoapi.SetupResponse, error) {
+    if !isSetupToken(req.Token) {
+        return nil, ErrInvalidSetupToken
+    }
+    if !validate.IsUsername(req.Name) {
+        return nil, ErrInvalidUsername
+    }
+    if !validate.IsEmail(req.Email) {
+        return nil, ErrInvalidEmail
+    }
+    if !validate.IsPassword(req.Password) {
+        return nil, ErrInvalidPassword
+    }
+
+    resp, err := withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.SetupResponse, error) {
+        // checked in the transaction so concurrent requests can't both create a superuser
+        complete, err := IsSetupComplete(ctx, tx.Client())
+        if err != nil {
+            return nil, err
+        }
+        if complete {
+            return nil, ErrSetupComplete
+        }
+
+        roleId, err := tx.Role.Query().Where(role.NameEQ(config.SuperuserRoleName)).OnlyID(ctx)
+        if err != nil {
+            return nil, err
+        }
+
+        userData, err := tx.User.Create().
+            SetName(req.Name).
+            SetEmail(req.Email).
+            SetPassword(hashing.HashPassword(req.Password)).
+            SetRoleID(roleId).
+            Save(ctx)
+        if err != nil {
+            return nil, err
+        }
+
+        resp := &protoapi.SetupResponse{
+            User: proto.EntUserEntityToProtoUser(userData),
+        }
+        if err := publishUser(ctx, db, events.UserCreated, resp.User); err != nil {
+            return nil, err
+        }
+
+        return resp, nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    // the token is useless from now on since setup can't run again
+    _ = os.Remove(config.Config.Auth.SetupTokenFile)
+
+    return resp, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/config"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/testdb"
+    "path/filepath"
+    "testing"
+)
+
+func TestSetupToken(t *testing.T) {
+    config.Config = config.DefaultConfiguration()
+    config.Config.Auth.SetupTokenFile = filepath.Join(t.TempDir(), "setup_token")
+
+    token, err := EnsureSetupToken(config.Config.Auth.SetupTokenFile)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if again, _ := EnsureSetupToken(config.Config.Auth.SetupTokenFile); again != token {
+        t.Errorf("EnsureSetupToken replaced the existing token")
+    }
+    if !isSetupToken(token) {
+        t.Error("generated token isn't accepted")
+    }
+
+    replaced, err := NewSetupToken(config.Config.Auth.SetupTokenFile)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if isSetupToken(token) || !isSetupToken(replaced) {
+        t.Error("NewSetupToken didn't replace the previous token")
+    }
+    if isSetupToken("") {
+        t.Error("empty token is accepted")
+    }
+}
+
+func TestCompleteSetupRequiresToken(t *testing.T) {
+    db := testdb.NewClient(t)
+    config.Config = config.DefaultConfiguration()
+    config.Config.Auth.SetupTokenFile = filepath.Join(t.TempDir(), "setup_token")
+    if _, err := NewSetupToken(config.Config.Auth.SetupTokenFile); err != nil {
+        t.Fatal(err)
+    }
+
+    _, err := CompleteSetup(context.Background(), db, &protoapi.SetupRequest{
+        Token:    "wrong",
+        Name:     "admin",
+        Email:    "admin@example.com",
+        Password: "password123",
+    })
+    if !errors.Is(err, ErrInvalidSetupToken) {
+        t.Errorf("got %v, want %v", err, ErrInvalidSetupToken)
+    }
+}
+
+func TestIsSetupCompleteCountsDeletedUsers(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    if complete, _ := IsSetupComplete(ctx, db); complete {
+        t.Fatal("setup is complete without users")
+    }
+
+    member := testdb.CreateRole(t, db, "member", nil)
+    u := testdb.CreateUser(t, db, "first", member.ID)
+    if err := db.User.DeleteOne(u).Exec(ctx); err != nil {
+        t.Fatal(err)
+    }
+
+    if complete, _ := IsSetupComplete(ctx, db); !complete {
+        t.Error("setup isn't complete after the first user was deleted")
+    }
+}
@@ -3,6 +3,7 @@ package services
 import (
     "errors"
     "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/metrics"
     "github.com/Encedeus/panel/proto"
     protoapi "github.com/Encedeus/panel/proto/go"
     "github.com/golang-jwt/jwt/v5"
@@ -45,6 +46,7 @@ func GenerateAccessToken(userData *protoapi.AccessToken) (string, error) {
     if err != nil {
         return "", err
     }
+    metrics.TokensIssued.WithLabelValues("access").Inc()
 
     return accessTokenString, nil
 }
@@ -62,6 +64,7 @@ func GenerateAPIKey(keyData *protoapi.AccountAPIKeyToken) (string, error) {
     if err != nil {
         return "", err
     }
+    metrics.TokensIssued.WithLabelValues("api_key").Inc()
 
     return keyString, nil
 }
@@ -84,6 +87,7 @@ func GenerateRefreshToken(keyData *protoapi.RefreshToken) (string, error) {
     if err != nil {
         return "", err
     }
+    metrics.TokensIssued.WithLabelValues("refresh").Inc()
 
     return accessTokenString, nil
 }
@@ -130,11 +134,14 @@ func ValidateAccessJWT(tokenString string) (isValid bool, token *protoapi.Token,
     tAcc, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
         return []byte(config.Config.Auth.JWTSecretAccess), nil
     })
-    tcl, ok := tAcc.Claims.(*TokenClaims)
-    if ok && tAcc.Valid {
-        if tcl.Token != nil {
-            if tcl.Token.Type == protoapi.TokenType_ACCESS_TOKEN {
-                token = tcl.Token
+    // the parsed token is nil if the string isn't a JWT at all
+    if tAcc != nil {
+        tcl, ok := tAcc.Claims.(*TokenClaims)
+        if ok && tAcc.Valid {
+            if tcl.Token != nil {
+                if tcl.Token.Type == protoapi.TokenType_ACCESS_TOKEN {
+                    token = tcl.Token
+                }
             }
         }
     }
@@ -145,11 +152,13 @@ func ValidateAccessJWT(tokenString string) (isValid bool, token *protoapi.Token,
     tApi, err := jwt.ParseWithClaims(tokenString, &AccountAPIKeyClaims{}, func(token *jwt.Token) (interface{}, error) {
         return []byte(config.Config.Auth.JWTSecretAccess), nil
     })
-    acl, ok := tApi.Claims.(*AccountAPIKeyClaims)
-    if ok && tApi.Valid {
-        if acl.Token != nil {
-            if acl.Token.Type == protoapi.TokenType_ACCOUNT_API_KEY {
-                token = acl.Token
+    if tApi != nil {
+        acl, ok := tApi.Claims.(*AccountAPIKeyClaims)
+        if ok && tApi.Valid {
+            if acl.Token != nil {
+                if acl.Token.Type == protoapi.TokenType_ACCOUNT_API_KEY {
+                    token = acl.Token
+                }
             }
         }
     }
@@ -0,0 +1,47 @@
+package services
+
+import (
+    "context"
+    "github.com/Encedeus/panel/ent"
+    "log/slog"
+)
+
+// withTx runs fn in a transaction and commits it, it's rolled back if fn returns an error or panics.
+// The transaction is also added to the context given to fn, so the events fn publishes are only dispatched
+// once it commits. If ctx already holds a transaction fn runs in it and the caller decides whether it commits.
+func withTx[T any](ctx context.Context, db *ent.Client, fn func(ctx context.Context, tx *ent.Tx) (T, error)) (T, error) {
+    if tx := ent.TxFromContext(ctx); tx != nil {
+        return fn(ctx, tx)
+    }
+
+    var zero T
+    tx, err := db.Tx(ctx)
+    if err != nil {
+        return zero, err
+    }
+    defer func() {
+        if r := recover(); r != nil {
+            _ = rollback(tx, nil)
+            panic(r)
+        }
+    }()
+
+    result, err := fn(ent.NewTxContext(ctx, tx), tx)
+    if err != nil {
+        return zero, rollback(tx, err)
+    }
+    if err = tx.Commit(); err != nil {
+        return zero, err
+    }
+
+    return result, nil
+}
+
+// rollback rolls the transaction back and returns the error that caused it
+func rollback(tx *ent.Tx, err error) error {
+    if rerr := tx.Rollback(); rerr != nil {
+        slog.Error("error rolling back transaction", "error", rerr)
+    }
+
+    return err
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/ent/role"
+    "github.com/Encedeus/panel/events"
+    "github.com/Encedeus/panel/hashing"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/testdb"
+    "github.com/google/uuid"
+    "testing"
+)
+
+func TestWithTxRollsBackOnError(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    dispatched := 0
+    t.Cleanup(events.RoleCreated.Subscribe(func(ctx context.Context, event events.Event[*protoapi.Role]) error {
+        dispatched++
+        return nil
+    }))
+
+    errFailed := errors.New("failed")
+    _, err := withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (struct{}, error) {
+        r, err := tx.Role.Create().SetName("rolledback").SetPermissions([]string{}).Save(ctx)
+        if err != nil {
+            return struct{}{}, err
+        }
+        if err = events.RoleCreated.Publish(ctx, db, proto.EntRoleEntityToProtoRole(r)); err != nil {
+            return struct{}{}, err
+        }
+
+        return struct{}{}, errFailed
+    })
+    if !errors.Is(err, errFailed) {
+        t.Fatalf("got %v, want %v", err, errFailed)
+    }
+
+    if exists, _ := db.Role.Query().Where(role.NameEQ("rolledback")).Exist(ctx); exists {
+        t.Error("role was created by a rolled back transaction")
+    }
+    if pending, _ := db.OutboxEvent.Query().Count(ctx); pending != 0 {
+        t.Errorf("%d events left in the outbox by a rolled back transaction", pending)
+    }
+    if dispatched != 0 {
+        t.Errorf("dispatched %d events of a rolled back transaction", dispatched)
+    }
+}
+
+func TestUpdateUserKeepsPasswordIfNotSet(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    member := testdb.CreateRole(t, db, "member", nil)
+    u := testdb.CreateUser(t, db, "someone", member.ID)
+
+    _, err := UpdateUser(ctx, db, uuid.Nil, &protoapi.UserUpdateRequest{
+        UserId: proto.UUIDToProtoUUID(u.ID),
+        Name:   "renamed",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    updated, err := db.User.Get(ctx, u.ID)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if updated.Name != "renamed" {
+        t.Errorf("user is named %s, want renamed", updated.Name)
+    }
+    if !hashing.VerifyHash(testdb.Password, updated.Password) {
+        t.Error("user's password changed though the update didn't set one")
+    }
+}
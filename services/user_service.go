@@ -2,21 +2,20 @@ package services
 
 import (
     "context"
-    "errors"
     "github.com/Encedeus/panel/ent"
-    "github.com/Encedeus/panel/ent/role"
+    "github.com/Encedeus/panel/ent/schema"
     "github.com/Encedeus/panel/ent/user"
+    "github.com/Encedeus/panel/events"
     "github.com/Encedeus/panel/hashing"
     "github.com/Encedeus/panel/proto"
     protoapi "github.com/Encedeus/panel/proto/go"
     "github.com/Encedeus/panel/validate"
     "github.com/google/uuid"
-    "golang.org/x/exp/slices"
     "strings"
-    "time"
 )
 
-func CreateUser(ctx context.Context, db *ent.Client, req *protoapi.UserCreateRequest) (*protoapi.UserCreateResponse, error) {
+// CreateUser creates a user with the role of req, createdBy has to be able to assign it unless it's uuid.Nil
+func CreateUser(ctx context.Context, db *ent.Client, createdBy uuid.UUID, req *protoapi.UserCreateRequest) (*protoapi.UserCreateResponse, error) {
     if !validate.IsUsername(req.Name) {
         return nil, ErrInvalidUsername
     }
@@ -27,143 +26,238 @@ func CreateUser(ctx context.Context, db *ent.Client, req *protoapi.UserCreateReq
         return nil, ErrInvalidPassword
     }
 
-    roleId, err := uuid.Parse(req.RoleId.Value)
-    if err != nil {
-        r, err := db.Role.Query().Where(role.NameEQ(req.RoleName)).First(ctx)
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.UserCreateResponse, error) {
+        roleId, err := FindRoleID(ctx, tx.Client(), req.RoleId, req.RoleName)
         if err != nil {
             return nil, err
         }
+        if err = checkRoleAssignable(ctx, tx.Client(), createdBy, roleId); err != nil {
+            return nil, err
+        }
 
-        roleId = r.ID
-    }
+        userData, err := tx.User.Create().
+            SetName(req.Name).
+            SetEmail(req.Email).
+            SetPassword(hashing.HashPassword(req.Password)).
+            SetRoleID(roleId).
+            Save(ctx)
+        if err != nil {
+            if ent.IsConstraintError(err) {
+                return nil, ErrUsernameTaken
+            }
 
-    userData, err := db.User.Create().
-        SetName(req.Name).
-        SetEmail(req.Email).
-        SetPassword(hashing.HashPassword(req.Password)).
-        SetRoleID(roleId).
-        Save(ctx)
+            return nil, err
+        }
 
-    resp := &protoapi.UserCreateResponse{
-        User: proto.EntUserEntityToProtoUser(userData),
-    }
+        resp := &protoapi.UserCreateResponse{
+            User: proto.EntUserEntityToProtoUser(userData),
+        }
+        if err := publishUser(ctx, db, events.UserCreated, resp.User); err != nil {
+            return nil, err
+        }
 
-    return resp, nil
+        return resp, nil
+    })
 }
 
-// DoesUserHavePermission checks if user's role have a permission
+// DoesUserHavePermission checks if user's role or one of its ancestors have a permission
 func DoesUserHavePermission(ctx context.Context, db *ent.Client, permission string, userID uuid.UUID) bool {
-    userData, err := db.User.Query().Where(user.IDEQ(userID)).Select("role_id").First(ctx)
-    if err != nil {
-        return false
-    }
-
-    if IsUserDeleted(userData) {
-        return false
-    }
-
-    roleData, err := db.Role.Query().Where(role.ID(userData.RoleID)).Select("permissions").First(ctx)
+    permissions, err := UserEffectivePermissions(ctx, db, userID)
     if err != nil {
         return false
     }
 
-    return slices.Contains(roleData.Permissions, permission) || slices.Contains(roleData.Permissions, "*")
+    return hasPermission(permissions, permission)
 }
 
-// UpdateUser updates the user given an updateInfo dto
-func UpdateUser(ctx context.Context, db *ent.Client, req *protoapi.UserUpdateRequest) (*protoapi.UserUpdateResponse, error) {
-    if !validate.IsEmail(req.Email) {
+// UpdateUser applies the fields set in req to the user in a single update,
+// updatedBy has to be able to assign the new role unless it's uuid.Nil
+func UpdateUser(ctx context.Context, db *ent.Client, updatedBy uuid.UUID, req *protoapi.UserUpdateRequest) (*protoapi.UserUpdateResponse, error) {
+    if req.Name != "" && !validate.IsUsername(req.Name) {
+        return nil, ErrInvalidUsername
+    }
+    if req.Email != "" && !validate.IsEmail(req.Email) {
         return nil, ErrInvalidEmail
     }
-    if !validate.IsPassword(req.Password) {
+    if req.Password != "" && !validate.IsPassword(req.Password) {
         return nil, ErrInvalidPassword
     }
-    if !validate.IsUsername(req.Name) {
-        return nil, ErrInvalidUserId
+    userId := proto.ProtoUUIDToUUID(req.UserId)
+
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.UserUpdateResponse, error) {
+        userData, err := tx.User.Get(ctx, userId)
+        if err != nil {
+            if ent.IsNotFound(err) && isUserSoftDeleted(ctx, tx.Client(), userId) {
+                return nil, ErrUserDeleted
+            }
+
+            return nil, err
+        }
+        if err = checkVersion(ctx, userData.Version); err != nil {
+            return nil, err
+        }
+
+        // fails if another request changed the user since it was read
+        update := userData.Update().Where(user.VersionEQ(userData.Version))
+        if req.Name != "" {
+            update.SetName(req.Name)
+        }
+        if req.Email != "" {
+            update.SetEmail(req.Email)
+        }
+        if req.Password != "" {
+            update.SetPassword(hashing.HashPassword(req.Password))
+        }
+        if req.RoleId.GetValue() != "" || req.RoleName != "" {
+            roleId, err := FindRoleID(ctx, tx.Client(), req.RoleId, req.RoleName)
+            if err != nil {
+                return nil, err
+            }
+            // FindRoleID takes the id as is, so it's checked here
+            if _, err = tx.Role.Get(ctx, roleId); err != nil {
+                return nil, err
+            }
+            if err = checkRoleAssignable(ctx, tx.Client(), updatedBy, roleId); err != nil {
+                return nil, err
+            }
+            update.SetRoleID(roleId)
+        }
+
+        userData, err = update.Save(ctx)
+        if err != nil {
+            if ent.IsNotFound(err) {
+                return nil, ErrVersionMismatch
+            }
+            if ent.IsConstraintError(err) {
+                return nil, ErrUsernameTaken
+            }
+
+            return nil, err
+        }
+
+        resp := &protoapi.UserUpdateResponse{
+            User: proto.EntUserEntityToProtoUser(userData),
+        }
+        if err := publishUser(ctx, db, events.UserUpdated, resp.User); err != nil {
+            return nil, err
+        }
+
+        return resp, nil
+    })
+}
+
+// ResetUserPassword sets a new password without requiring the old one
+func ResetUserPassword(ctx context.Context, db *ent.Client, userId uuid.UUID, password string) error {
+    if !validate.IsPassword(password) {
+        return ErrInvalidPassword
     }
 
-    userData, err := db.User.Query().Where(user.IDEQ(proto.ProtoUUIDToUUID(req.UserId))).First(ctx)
+    return db.User.UpdateOneID(userId).
+        SetPassword(hashing.HashPassword(password)).
+        Exec(ctx)
+}
 
+// FindAllUsers returns every user that isn't deleted ordered by name
+func FindAllUsers(ctx context.Context, db *ent.Client) ([]*protoapi.User, error) {
+    users, err := db.User.Query().Order(user.ByName()).All(ctx)
     if err != nil {
         return nil, err
     }
 
-    if IsUserDeleted(userData) {
-        return nil, errors.New("user deleted")
+    protoUsers := make([]*protoapi.User, len(users))
+    for i, u := range users {
+        protoUsers[i] = proto.EntUserEntityToProtoUser(u)
     }
 
-    if req.Name != "" {
-        _, err = userData.Update().SetName(req.Name).Save(ctx)
-    }
+    return protoUsers, nil
+}
 
-    if req.Password != "" {
-        _, err = userData.Update().SetPassword(req.Password).Save(ctx)
+// FindUserID returns the id of a user given either its id or its name, the id takes precedence
+func FindUserID(ctx context.Context, db *ent.Client, id *protoapi.UUID, name string) (uuid.UUID, error) {
+    if userId, err := uuid.Parse(id.GetValue()); err == nil {
+        return userId, nil
     }
 
-    if req.Email != "" {
-        _, err = userData.Update().SetEmail(req.Email).Save(ctx)
-    }
+    return db.User.Query().Where(user.NameEQ(name)).OnlyID(ctx)
+}
 
-    if req.RoleName != "" {
-        roleData, roleErr := db.Role.Query().Where(role.Name(req.RoleName)).First(ctx)
-        if roleErr != nil {
-            return nil, roleErr
-        }
-        _, err = userData.Update().SetRole(roleData).Save(ctx)
+// DeleteUser soft-deletes the user, the row is purged once the retention period passes
+func DeleteUser(ctx context.Context, db *ent.Client, req *protoapi.UserDeleteRequest) (*protoapi.UserDeleteResponse, error) {
+    userId := uuid.MustParse(req.UserId.Value)
 
-    }
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.UserDeleteResponse, error) {
+        deletion := tx.User.DeleteOneID(userId)
+        if versions, ok := ifMatch(ctx); ok {
+            deletion.Where(user.VersionIn(versions...))
+        }
 
-    if s := req.RoleId.Value; s != "" {
-        roleData, roleErr := db.Role.Query().Where(role.ID(proto.ProtoUUIDToUUID(req.RoleId))).First(ctx)
-        if roleErr != nil {
-            return nil, roleErr
+        err := deletion.Exec(ctx)
+        if ent.IsNotFound(err) {
+            if isUserSoftDeleted(ctx, tx.Client(), userId) {
+                return nil, ErrAlreadyDeleted
+            }
+            // the user exists, so the If-Match header didn't match it
+            if DoesUserWithUUIDExist(ctx, tx.Client(), userId) {
+                return nil, ErrVersionMismatch
+            }
+        }
+        if err != nil {
+            return nil, err
         }
-        _, err = userData.Update().SetRole(roleData).Save(ctx)
-    }
 
-    currUser, err := db.User.Get(ctx, proto.ProtoUUIDToUUID(req.UserId))
-    if err != nil {
-        return nil, err
-    }
+        if err := publishUserDeleted(ctx, db, userId); err != nil {
+            return nil, err
+        }
 
-    resp := &protoapi.UserUpdateResponse{
-        User: proto.EntUserEntityToProtoUser(currUser),
-    }
+        resp := &protoapi.UserDeleteResponse{}
 
-    return resp, nil
+        return resp, nil
+    })
 }
 
-func DeleteUser(ctx context.Context, db *ent.Client, req *protoapi.UserDeleteRequest) (*protoapi.UserDeleteResponse, error) {
-    userData, err := db.User.Query().Where(user.IDEQ(uuid.MustParse(req.UserId.Value))).First(ctx)
-    if err != nil {
-        return nil, err
-    }
+// RestoreUser undoes the soft-deletion of a user
+func RestoreUser(ctx context.Context, db *ent.Client, userId uuid.UUID) (*protoapi.User, error) {
+    ctx = schema.SkipSoftDelete(ctx)
 
-    if IsUserDeleted(userData) {
-        return nil, errors.New("already deleted")
-    }
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.User, error) {
+        userData, err := tx.User.Get(ctx, userId)
+        if err != nil {
+            return nil, err
+        }
 
-    userData, err = userData.Update().SetDeletedAt(time.Now()).Save(ctx)
-    if err != nil {
-        return nil, err
-    }
+        if userData.DeletedAt.IsZero() {
+            return nil, ErrUserNotDeleted
+        }
 
-    resp := &protoapi.UserDeleteResponse{}
+        userData, err = userData.Update().ClearDeletedAt().Save(ctx)
+        if err != nil {
+            if ent.IsConstraintError(err) {
+                return nil, ErrUsernameTaken
+            }
 
-    return resp, err
+            return nil, err
+        }
+
+        protoUser := proto.EntUserEntityToProtoUser(userData)
+        if err := publishUser(ctx, db, events.UserRestored, protoUser); err != nil {
+            return nil, err
+        }
+
+        return protoUser, nil
+    })
 }
 
 func FindOneUser(ctx context.Context, db *ent.Client, req *protoapi.UserFindOneRequest) (*protoapi.UserFindOneResponse, error) {
     userData, err := db.User.Query().
         Where(user.IDEQ(proto.ProtoUUIDToUUID(req.UserId))).
-        Select("id", "name", "created_at", "updated_at", "deleted_at", "email", "role_id").
+        Select("id", "name", "created_at", "updated_at", "deleted_at", "email", "role_id", "version").
         First(ctx)
     if err != nil {
-        return nil, err
-    }
+        if ent.IsNotFound(err) && isUserSoftDeleted(ctx, db, proto.ProtoUUIDToUUID(req.UserId)) {
+            return nil, ErrUserDeleted
+        }
 
-    if IsUserDeleted(userData) {
-        return nil, errors.New("user deleted")
+        return nil, err
     }
 
     resp := &protoapi.UserFindOneResponse{
@@ -174,18 +268,14 @@ func FindOneUser(ctx context.Context, db *ent.Client, req *protoapi.UserFindOneR
 }
 
 func DoesUserWithUUIDExist(ctx context.Context, db *ent.Client, userId uuid.UUID) bool {
-    userData, err := db.User.Query().Where(user.IDEQ(userId)).First(ctx)
-
-    if err != nil || IsUserDeleted(userData) {
-        return false
-    }
+    exists, err := db.User.Query().Where(user.IDEQ(userId)).Exist(ctx)
 
-    return true
+    return err == nil && exists
 }
 
 func GetLastUpdate(ctx context.Context, db *ent.Client, userId uuid.UUID) (int64, error) {
     userData, err := db.User.Query().
-        Where(user.IDEQ(userId), user.DeletedAtIsNil()).
+        Where(user.IDEQ(userId)).
         Select("updated_at").
         First(ctx)
 
@@ -195,8 +285,14 @@ func GetLastUpdate(ctx context.Context, db *ent.Client, userId uuid.UUID) (int64
 
     return userData.UpdatedAt.Unix(), nil
 }
-func IsUserDeleted(userData *ent.User) bool {
-    return userData.DeletedAt.Unix() != -62135596800
+
+// isUserSoftDeleted checks if a user exists but was soft-deleted
+func isUserSoftDeleted(ctx context.Context, db *ent.Client, userId uuid.UUID) bool {
+    exists, err := db.User.Query().
+        Where(user.IDEQ(userId), user.DeletedAtNotNil()).
+        Exist(schema.SkipSoftDelete(ctx))
+
+    return err == nil && exists
 }
 
 func IsUserUpdated(ctx context.Context, db *ent.Client, userId uuid.UUID, issuedAt int64) (bool, error) {
@@ -216,89 +312,128 @@ func ChangeUsername(ctx context.Context, db *ent.Client, req *protoapi.UserChang
     if !validate.IsUsername(req.NewUsername) || !validate.IsUsername(req.OldUsername) {
         return nil, ErrInvalidUsername
     }
-    if !validate.IsUserId(ctx, db, req.UserId) {
-        return nil, ErrInvalidUserId
-    }
 
-    userData, err := db.User.Query().Where(user.IDEQ(proto.ProtoUUIDToUUID(req.UserId))).Select(user.FieldName).First(ctx)
-    if err != nil {
-        if ent.IsNotFound(err) {
-            return nil, ErrUserNotFound
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.UserChangeUsernameResponse, error) {
+        if !validate.IsUserId(ctx, tx.Client(), req.UserId) {
+            return nil, ErrInvalidUserId
         }
 
-        return nil, err
-    }
-    if userData.Name != strings.TrimSpace(req.OldUsername) {
-        return nil, ErrOldUsernameDoesNotMatch
-    }
-    if userData.Name == strings.TrimSpace(req.NewUsername) {
-        return nil, ErrNewUsernameEqualsOld
-    }
+        userData, err := tx.User.Query().Where(user.IDEQ(proto.ProtoUUIDToUUID(req.UserId))).Select(user.FieldName, user.FieldVersion).First(ctx)
+        if err != nil {
+            if ent.IsNotFound(err) {
+                return nil, ErrUserNotFound
+            }
 
-    _, err = userData.Update().SetName(req.NewUsername).Save(ctx)
-    if err != nil {
-        return nil, err
-    }
+            return nil, err
+        }
+        if err = checkVersion(ctx, userData.Version); err != nil {
+            return nil, err
+        }
+        if userData.Name != strings.TrimSpace(req.OldUsername) {
+            return nil, ErrOldUsernameDoesNotMatch
+        }
+        if userData.Name == strings.TrimSpace(req.NewUsername) {
+            return nil, ErrNewUsernameEqualsOld
+        }
 
-    resp := &protoapi.UserChangeUsernameResponse{}
+        userData, err = userData.Update().Where(user.VersionEQ(userData.Version)).SetName(req.NewUsername).Save(ctx)
+        if err != nil {
+            if ent.IsNotFound(err) {
+                return nil, ErrVersionMismatch
+            }
+            if ent.IsConstraintError(err) {
+                return nil, ErrUsernameTaken
+            }
 
-    return resp, nil
+            return nil, err
+        }
+        if err := publishEntUser(ctx, db, events.UserUpdated, userData); err != nil {
+            return nil, err
+        }
+
+        resp := &protoapi.UserChangeUsernameResponse{}
+
+        return resp, nil
+    })
 }
 
 func ChangeUserPassword(ctx context.Context, db *ent.Client, req *protoapi.UserChangePasswordRequest) (*protoapi.UserChangePasswordResponse, error) {
     if !validate.IsPassword(req.NewPassword) || !validate.IsPassword(req.OldPassword) {
         return nil, ErrInvalidPassword
     }
-    if !validate.IsUserId(ctx, db, req.UserId) {
-        return nil, ErrInvalidUserId
-    }
 
-    userData, err := db.User.Query().Where(user.IDEQ(proto.ProtoUUIDToUUID(req.UserId))).Select(user.FieldPassword).First(ctx)
-    if err != nil {
-        return nil, err
-    }
-    if !hashing.VerifyHash(req.OldPassword, userData.Password) {
-        return nil, ErrOldPasswordDoesNotMatch
-    }
-    if userData.Password == hashing.HashPassword(req.NewPassword) {
-        return nil, ErrNewPasswordEqualsOld
-    }
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.UserChangePasswordResponse, error) {
+        if !validate.IsUserId(ctx, tx.Client(), req.UserId) {
+            return nil, ErrInvalidUserId
+        }
 
-    _, err = userData.Update().SetPassword(hashing.HashPassword(req.NewPassword)).Save(ctx)
-    if err != nil {
-        return nil, err
-    }
+        userData, err := tx.User.Query().Where(user.IDEQ(proto.ProtoUUIDToUUID(req.UserId))).Select(user.FieldPassword, user.FieldVersion).First(ctx)
+        if err != nil {
+            return nil, err
+        }
+        if err = checkVersion(ctx, userData.Version); err != nil {
+            return nil, err
+        }
+        if !hashing.VerifyHash(req.OldPassword, userData.Password) {
+            return nil, ErrOldPasswordDoesNotMatch
+        }
+        if userData.Password == hashing.HashPassword(req.NewPassword) {
+            return nil, ErrNewPasswordEqualsOld
+        }
+
+        _, err = userData.Update().Where(user.VersionEQ(userData.Version)).SetPassword(hashing.HashPassword(req.NewPassword)).Save(ctx)
+        if err != nil {
+            if ent.IsNotFound(err) {
+                return nil, ErrVersionMismatch
+            }
+
+            return nil, err
+        }
 
-    resp := &protoapi.UserChangePasswordResponse{}
+        resp := &protoapi.UserChangePasswordResponse{}
 
-    return resp, nil
+        return resp, nil
+    })
 }
 
 func ChangeUserEmail(ctx context.Context, db *ent.Client, req *protoapi.UserChangeEmailRequest) (*protoapi.UserChangeEmailResponse, error) {
     if !validate.IsEmail(req.NewEmail) || !validate.IsEmail(req.OldEmail) {
         return nil, ErrInvalidEmail
     }
-    if !validate.IsUserId(ctx, db, req.UserId) {
-        return nil, ErrInvalidUserId
-    }
 
-    userData, err := db.User.Query().Where(user.IDEQ(proto.ProtoUUIDToUUID(req.UserId))).Select(user.FieldEmail).First(ctx)
-    if err != nil {
-        return nil, err
-    }
-    if userData.Email != strings.TrimSpace(req.OldEmail) {
-        return nil, ErrOldEmailDoesNotMatch
-    }
-    if userData.Email == strings.TrimSpace(req.NewEmail) {
-        return nil, ErrNewEmailEqualsOld
-    }
+    return withTx(ctx, db, func(ctx context.Context, tx *ent.Tx) (*protoapi.UserChangeEmailResponse, error) {
+        if !validate.IsUserId(ctx, tx.Client(), req.UserId) {
+            return nil, ErrInvalidUserId
+        }
 
-    _, err = userData.Update().SetEmail(req.NewEmail).Save(ctx)
-    if err != nil {
-        return nil, err
-    }
+        userData, err := tx.User.Query().Where(user.IDEQ(proto.ProtoUUIDToUUID(req.UserId))).Select(user.FieldEmail, user.FieldVersion).First(ctx)
+        if err != nil {
+            return nil, err
+        }
+        if err = checkVersion(ctx, userData.Version); err != nil {
+            return nil, err
+        }
+        if userData.Email != strings.TrimSpace(req.OldEmail) {
+            return nil, ErrOldEmailDoesNotMatch
+        }
+        if userData.Email == strings.TrimSpace(req.NewEmail) {
+            return nil, ErrNewEmailEqualsOld
+        }
+
+        userData, err = userData.Update().Where(user.VersionEQ(userData.Version)).SetEmail(req.NewEmail).Save(ctx)
+        if err != nil {
+            if ent.IsNotFound(err) {
+                return nil, ErrVersionMismatch
+            }
+
+            return nil, err
+        }
+        if err := publishEntUser(ctx, db, events.UserUpdated, userData); err != nil {
+            return nil, err
+        }
 
-    resp := &protoapi.UserChangeEmailResponse{}
+        resp := &protoapi.UserChangeEmailResponse{}
 
-    return resp, nil
+        return resp, nil
+    })
 }
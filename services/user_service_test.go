@@ -0,0 +1,143 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "github.com/Encedeus/panel/hashing"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/testdb"
+    "github.com/google/uuid"
+    "testing"
+)
+
+func TestDeleteAndRestoreUser(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    member := testdb.CreateRole(t, db, "member", nil)
+    u := testdb.CreateUser(t, db, "someone", member.ID)
+    req := &protoapi.UserDeleteRequest{UserId: proto.UUIDToProtoUUID(u.ID)}
+
+    if _, err := DeleteUser(ctx, db, req); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := FindOneUser(ctx, db, &protoapi.UserFindOneRequest{UserId: req.UserId}); !errors.Is(err, ErrUserDeleted) {
+        t.Errorf("finding deleted user: got %v, want %v", err, ErrUserDeleted)
+    }
+    if _, err := DeleteUser(ctx, db, req); !errors.Is(err, ErrAlreadyDeleted) {
+        t.Errorf("deleting twice: got %v, want %v", err, ErrAlreadyDeleted)
+    }
+
+    restored, err := RestoreUser(ctx, db, u.ID)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if restored.DeletedAt != nil {
+        t.Error("restored user is still marked as deleted")
+    }
+    if _, err = FindOneUser(ctx, db, &protoapi.UserFindOneRequest{UserId: req.UserId}); err != nil {
+        t.Errorf("finding restored user: %v", err)
+    }
+    if _, err = RestoreUser(ctx, db, u.ID); !errors.Is(err, ErrUserNotDeleted) {
+        t.Errorf("restoring twice: got %v, want %v", err, ErrUserNotDeleted)
+    }
+}
+
+func TestRestoreUserWhoseNameWasReused(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    member := testdb.CreateRole(t, db, "member", nil)
+    u := testdb.CreateUser(t, db, "someone", member.ID)
+    if _, err := DeleteUser(ctx, db, &protoapi.UserDeleteRequest{UserId: proto.UUIDToProtoUUID(u.ID)}); err != nil {
+        t.Fatal(err)
+    }
+
+    // the name of a deleted user is free again
+    testdb.CreateUser(t, db, "someone", member.ID)
+
+    if _, err := RestoreUser(ctx, db, u.ID); !errors.Is(err, ErrUsernameTaken) {
+        t.Errorf("got %v, want %v", err, ErrUsernameTaken)
+    }
+}
+
+func TestResetUserPassword(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    member := testdb.CreateRole(t, db, "member", nil)
+    u := testdb.CreateUser(t, db, "someone", member.ID)
+
+    if err := ResetUserPassword(ctx, db, u.ID, "<b>markup</b>"); !errors.Is(err, ErrInvalidPassword) {
+        t.Errorf("got %v, want %v", err, ErrInvalidPassword)
+    }
+    if err := ResetUserPassword(ctx, db, u.ID, "new-password"); err != nil {
+        t.Fatal(err)
+    }
+
+    updated := db.User.GetX(ctx, u.ID)
+    if !hashing.VerifyHash("new-password", updated.Password) {
+        t.Error("password wasn't reset")
+    }
+}
+
+func TestFindUserID(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    member := testdb.CreateRole(t, db, "member", nil)
+    u := testdb.CreateUser(t, db, "someone", member.ID)
+    other := testdb.CreateUser(t, db, "other", member.ID)
+
+    tests := []struct {
+        name   string
+        id     *protoapi.UUID
+        byName string
+        want   uuid.UUID
+    }{
+        {"by name", nil, "someone", u.ID},
+        {"by id", proto.UUIDToProtoUUID(other.ID), "", other.ID},
+        {"id over name", proto.UUIDToProtoUUID(other.ID), "someone", other.ID},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := FindUserID(ctx, db, tt.id, tt.byName)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got != tt.want {
+                t.Errorf("got %s, want %s", got, tt.want)
+            }
+        })
+    }
+
+    if _, err := FindUserID(ctx, db, nil, "nobody"); err == nil {
+        t.Error("found a user that doesn't exist")
+    }
+}
+
+func TestFindAllUsersSkipsDeleted(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    member := testdb.CreateRole(t, db, "member", nil)
+    testdb.CreateUser(t, db, "zed", member.ID)
+    testdb.CreateUser(t, db, "amy", member.ID)
+    deleted := testdb.CreateUser(t, db, "gone", member.ID)
+    if _, err := DeleteUser(ctx, db, &protoapi.UserDeleteRequest{UserId: proto.UUIDToProtoUUID(deleted.ID)}); err != nil {
+        t.Fatal(err)
+    }
+
+    users, err := FindAllUsers(ctx, db)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var names []string
+    for _, u := range users {
+        names = append(names, u.Name)
+    }
+    if len(names) != 2 || names[0] != "amy" || names[1] != "zed" {
+        t.Errorf("got %v, want [amy zed]", names)
+    }
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+    "context"
+    "golang.org/x/exp/slices"
+    "strconv"
+    "strings"
+)
+
+// ErrVersionMismatch is returned when the resource isn't at the version the client sent in If-Match
+var ErrVersionMismatch = NewPreconditionFailedError("resource was modified since it was read")
+
+type ifMatchKey struct{}
+
+// ETag is the entity tag of a user, role or API key at the given version
+func ETag(version int64) string {
+    return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// WithIfMatch returns a context in which updates and deletes of users, roles and API keys fail with
+// ErrVersionMismatch unless the ETag of the resource is listed in header, the value of an If-Match header.
+// An empty header or * allows any version, weak ETags never match.
+func WithIfMatch(ctx context.Context, header string) context.Context {
+    header = strings.TrimSpace(header)
+    if header == "" || header == "*" {
+        return ctx
+    }
+
+    versions := []int64{}
+    for _, tag := range strings.Split(header, ",") {
+        tag = strings.TrimSpace(tag)
+        if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+            continue
+        }
+        if version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64); err == nil {
+            versions = append(versions, version)
+        }
+    }
+
+    return context.WithValue(ctx, ifMatchKey{}, versions)
+}
+
+// ifMatch returns the versions allowed by WithIfMatch, ok is false if any version is allowed
+func ifMatch(ctx context.Context) (versions []int64, ok bool) {
+    versions, ok = ctx.Value(ifMatchKey{}).([]int64)
+    return versions, ok
+}
+
+// checkVersion returns ErrVersionMismatch unless the version is allowed by WithIfMatch
+func checkVersion(ctx context.Context, version int64) error {
+    if versions, ok := ifMatch(ctx); ok && !slices.Contains(versions, version) {
+        return ErrVersionMismatch
+    }
+
+    return nil
+}
@@ -0,0 +1,572 @@
+package services
+
+import (
+    "context"
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "entgo.io/ent/dialect/sql"
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/ent/webhook"
+    "github.com/Encedeus/panel/ent/webhookdelivery"
+    "github.com/Encedeus/panel/events"
+    "github.com/Encedeus/panel/metrics"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/tracing"
+    "github.com/Encedeus/panel/version"
+    "github.com/google/uuid"
+    "google.golang.org/protobuf/encoding/protojson"
+    "io"
+    "log/slog"
+    "net/http"
+    "net/url"
+    "slices"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// AllWebhookEvents subscribes a webhook to every event type, including the ones added later
+const AllWebhookEvents = "*"
+
+// WebhookPingEvent is only sent by TestWebhook, webhooks don't have to subscribe to it
+const WebhookPingEvent = "ping"
+
+const (
+    // webhookDeliveryBatchSize is the most deliveries sent on each run of the deliverer
+    webhookDeliveryBatchSize = 100
+    // webhookDeliveryConcurrency is how many deliveries are sent at the same time
+    webhookDeliveryConcurrency = 8
+    // webhookDeliveryListLimit is how many of the latest deliveries of a webhook are listed
+    webhookDeliveryListLimit = 100
+    // webhookErrorMaxLen truncates the errors kept in the delivery log
+    webhookErrorMaxLen = 512
+)
+
+var (
+    ErrInvalidWebhookURL   = NewFieldError("url", "invalid webhook URL")
+    ErrInvalidWebhookEvent = NewFieldError("events", "unknown event type")
+    ErrWebhookNotFound     = NewNotFoundError("webhook not found")
+    ErrDeliveryNotFound    = NewNotFoundError("webhook delivery not found")
+)
+
+// webhookClient doesn't follow redirects, a webhook answering with one is treated as failing
+var webhookClient = &http.Client{
+    Transport: tracing.Transport(http.DefaultTransport),
+    CheckRedirect: func(*http.Request, []*http.Request) error {
+        return http.ErrUseLastResponse
+    },
+}
+
+// webhookPayload is the body of every delivery
+type webhookPayload struct {
+    ID         uuid.UUID       `json:"id"`
+    Type       string          `json:"type"`
+    OccurredAt time.Time       `json:"occurredAt"`
+    Data       json.RawMessage `json:"data"`
+}
+
+func CreateWebhook(ctx context.Context, db *ent.Client, req *protoapi.WebhookCreateRequest) (*protoapi.WebhookCreateResponse, error) {
+    if !isWebhookURL(req.Url) {
+        return nil, ErrInvalidWebhookURL
+    }
+    // a webhook without events would never be called, * subscribes to all of them
+    if len(req.Events) == 0 || !areWebhookEvents(req.Events) {
+        return nil, ErrInvalidWebhookEvent
+    }
+
+    secret := req.Secret
+    if secret == "" {
+        secret = generateWebhookSecret()
+    }
+
+    webhookData, err := db.Webhook.Create().
+        SetURL(req.Url).
+        SetEvents(req.Events).
+        SetDescription(req.Description).
+        SetSecret(secret).
+        Save(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    resp := &protoapi.WebhookCreateResponse{
+        Webhook: proto.EntWebhookToProtoWebhook(webhookData),
+    }
+    resp.Webhook.Secret = secret
+
+    return resp, nil
+}
+
+func UpdateWebhook(ctx context.Context, db *ent.Client, req *protoapi.WebhookUpdateRequest) (*protoapi.WebhookUpdateResponse, error) {
+    if req.Url != "" && !isWebhookURL(req.Url) {
+        return nil, ErrInvalidWebhookURL
+    }
+    if !areWebhookEvents(req.Events) {
+        return nil, ErrInvalidWebhookEvent
+    }
+
+    update := db.Webhook.UpdateOneID(proto.ProtoUUIDToUUID(req.Id))
+    if req.Url != "" {
+        update.SetURL(req.Url)
+    }
+    if len(req.Events) != 0 {
+        update.SetEvents(req.Events)
+    }
+    if req.Description != "" {
+        update.SetDescription(req.Description)
+    }
+    if req.Enabled != nil {
+        update.SetEnabled(*req.Enabled)
+    }
+
+    var secret string
+    if req.RotateSecret {
+        secret = generateWebhookSecret()
+        update.SetSecret(secret)
+    }
+
+    webhookData, err := update.Save(ctx)
+    if err != nil {
+        if ent.IsNotFound(err) {
+            return nil, ErrWebhookNotFound
+        }
+
+        return nil, err
+    }
+
+    resp := &protoapi.WebhookUpdateResponse{
+        Webhook: proto.EntWebhookToProtoWebhook(webhookData),
+    }
+    resp.Webhook.Secret = secret
+
+    return resp, nil
+}
+
+// DeleteWebhook deletes the webhook along with its delivery log
+func DeleteWebhook(ctx context.Context, db *ent.Client, req *protoapi.WebhookDeleteRequest) (*protoapi.WebhookDeleteResponse, error) {
+    err := db.Webhook.DeleteOneID(proto.ProtoUUIDToUUID(req.Id)).Exec(ctx)
+    if err != nil {
+        if ent.IsNotFound(err) {
+            return nil, ErrWebhookNotFound
+        }
+
+        return nil, err
+    }
+
+    resp := &protoapi.WebhookDeleteResponse{}
+
+    return resp, nil
+}
+
+func FindWebhook(ctx context.Context, db *ent.Client, req *protoapi.WebhookFindOneRequest) (*protoapi.WebhookFindOneResponse, error) {
+    webhookData, err := db.Webhook.Get(ctx, proto.ProtoUUIDToUUID(req.Id))
+    if err != nil {
+        if ent.IsNotFound(err) {
+            return nil, ErrWebhookNotFound
+        }
+
+        return nil, err
+    }
+
+    resp := &protoapi.WebhookFindOneResponse{
+        Webhook: proto.EntWebhookToProtoWebhook(webhookData),
+    }
+
+    return resp, nil
+}
+
+// FindAllWebhooks returns every webhook, oldest first
+func FindAllWebhooks(ctx context.Context, db *ent.Client) (*protoapi.WebhookFindManyResponse, error) {
+    webhooks, err := db.Webhook.Query().Order(webhook.ByCreatedAt()).All(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    resp := &protoapi.WebhookFindManyResponse{}
+    resp.Webhooks = make([]*protoapi.Webhook, len(webhooks))
+    for i, w := range webhooks {
+        resp.Webhooks[i] = proto.EntWebhookToProtoWebhook(w)
+    }
+
+    return resp, nil
+}
+
+// FindWebhookDeliveries returns the latest deliveries of a webhook, newest first
+func FindWebhookDeliveries(ctx context.Context, db *ent.Client, req *protoapi.WebhookDeliveryFindManyRequest) (*protoapi.WebhookDeliveryFindManyResponse, error) {
+    webhookId := proto.ProtoUUIDToUUID(req.WebhookId)
+
+    exists, err := db.Webhook.Query().Where(webhook.IDEQ(webhookId)).Exist(ctx)
+    if err != nil {
+        return nil, err
+    }
+    if !exists {
+        return nil, ErrWebhookNotFound
+    }
+
+    deliveries, err := db.WebhookDelivery.Query().
+        Where(webhookdelivery.WebhookIDEQ(webhookId)).
+        Order(webhookdelivery.ByCreatedAt(sql.OrderDesc())).
+        Limit(webhookDeliveryListLimit).
+        All(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    resp := &protoapi.WebhookDeliveryFindManyResponse{}
+    resp.Deliveries = make([]*protoapi.WebhookDelivery, len(deliveries))
+    for i, d := range deliveries {
+        resp.Deliveries[i] = proto.EntWebhookDeliveryToProtoWebhookDelivery(d)
+    }
+
+    return resp, nil
+}
+
+// TestWebhook sends a ping event to the webhook right away, even if it's disabled.
+// A failed ping is retried like any other delivery.
+func TestWebhook(ctx context.Context, db *ent.Client, req *protoapi.WebhookTestRequest) (*protoapi.WebhookTestResponse, error) {
+    webhookData, err := db.Webhook.Get(ctx, proto.ProtoUUIDToUUID(req.Id))
+    if err != nil {
+        if ent.IsNotFound(err) {
+            return nil, ErrWebhookNotFound
+        }
+
+        return nil, err
+    }
+
+    event := events.AnyEvent{
+        ID:         uuid.New(),
+        Type:       WebhookPingEvent,
+        OccurredAt: time.Now(),
+        Data:       proto.EntWebhookToProtoWebhook(webhookData),
+    }
+    payload, err := marshalWebhookPayload(event)
+    if err != nil {
+        return nil, err
+    }
+
+    delivery, err := db.WebhookDelivery.Create().
+        SetWebhookID(webhookData.ID).
+        SetEventID(event.ID).
+        SetEvent(event.Type).
+        SetPayload(payload).
+        SetNextAttemptAt(time.Now()).
+        Save(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    delivery, err = attemptWebhookDelivery(ctx, db, webhookData, delivery)
+    if err != nil {
+        return nil, err
+    }
+
+    resp := &protoapi.WebhookTestResponse{
+        Delivery: proto.EntWebhookDeliveryToProtoWebhookDelivery(delivery),
+    }
+
+    return resp, nil
+}
+
+// RedeliverWebhookDelivery sends the payload of a previous delivery right away as a new delivery,
+// keeping the previous one in the log as it is
+func RedeliverWebhookDelivery(ctx context.Context, db *ent.Client, req *protoapi.WebhookRedeliverRequest) (*protoapi.WebhookRedeliverResponse, error) {
+    previous, err := db.WebhookDelivery.Query().
+        Where(
+            webhookdelivery.IDEQ(proto.ProtoUUIDToUUID(req.DeliveryId)),
+            webhookdelivery.WebhookIDEQ(proto.ProtoUUIDToUUID(req.WebhookId)),
+        ).
+        WithWebhook().
+        Only(ctx)
+    if err != nil {
+        if ent.IsNotFound(err) {
+            return nil, ErrDeliveryNotFound
+        }
+
+        return nil, err
+    }
+
+    delivery, err := db.WebhookDelivery.Create().
+        SetWebhookID(previous.WebhookID).
+        SetEventID(previous.EventID).
+        SetEvent(previous.Event).
+        SetPayload(previous.Payload).
+        SetNextAttemptAt(time.Now()).
+        Save(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    delivery, err = attemptWebhookDelivery(ctx, db, previous.Edges.Webhook, delivery)
+    if err != nil {
+        return nil, err
+    }
+
+    resp := &protoapi.WebhookRedeliverResponse{
+        Delivery: proto.EntWebhookDeliveryToProtoWebhookDelivery(delivery),
+    }
+
+    return resp, nil
+}
+
+// SubscribeWebhooks queues a delivery of every published event for each enabled webhook subscribed to it,
+// the deliveries are sent by RunWebhookDeliverer
+func SubscribeWebhooks(db *ent.Client) (unsubscribe func()) {
+    return events.SubscribeAll(func(ctx context.Context, event events.AnyEvent) error {
+        return queueWebhookDeliveries(ctx, db, event)
+    })
+}
+
+func queueWebhookDeliveries(ctx context.Context, db *ent.Client, event events.AnyEvent) error {
+    webhooks, err := db.Webhook.Query().Where(webhook.EnabledEQ(true)).All(ctx)
+    if err != nil {
+        return err
+    }
+
+    var creates []*ent.WebhookDeliveryCreate
+    var payload string
+    for _, w := range webhooks {
+        if !slices.Contains(w.Events, event.Type) && !slices.Contains(w.Events, AllWebhookEvents) {
+            continue
+        }
+
+        if payload == "" {
+            if payload, err = marshalWebhookPayload(event); err != nil {
+                return err
+            }
+        }
+        creates = append(creates, db.WebhookDelivery.Create().
+            SetWebhookID(w.ID).
+            SetEventID(event.ID).
+            SetEvent(event.Type).
+            SetPayload(payload).
+            SetNextAttemptAt(event.OccurredAt))
+    }
+    if len(creates) == 0 {
+        return nil
+    }
+
+    return db.WebhookDelivery.CreateBulk(creates...).Exec(ctx)
+}
+
+func marshalWebhookPayload(event events.AnyEvent) (string, error) {
+    data, err := protojson.Marshal(event.Data)
+    if err != nil {
+        return "", err
+    }
+
+    payload, err := json.Marshal(webhookPayload{
+        ID:         event.ID,
+        Type:       event.Type,
+        OccurredAt: event.OccurredAt.UTC(),
+        Data:       data,
+    })
+
+    return string(payload), err
+}
+
+// DeliverDueWebhooks sends the pending deliveries of enabled webhooks whose next attempt is due
+func DeliverDueWebhooks(ctx context.Context, db *ent.Client, now time.Time) error {
+    deliveries, err := db.WebhookDelivery.Query().
+        Where(
+            webhookdelivery.StatusEQ(webhookdelivery.StatusPending),
+            webhookdelivery.NextAttemptAtLTE(now),
+            webhookdelivery.HasWebhookWith(webhook.EnabledEQ(true)),
+        ).
+        Order(webhookdelivery.ByNextAttemptAt()).
+        Limit(webhookDeliveryBatchSize).
+        WithWebhook().
+        All(ctx)
+    if err != nil {
+        return err
+    }
+
+    var wg sync.WaitGroup
+    sem := make(chan struct{}, webhookDeliveryConcurrency)
+    for _, d := range deliveries {
+        wg.Add(1)
+        sem <- struct{}{}
+        go func(d *ent.WebhookDelivery) {
+            defer wg.Done()
+            defer func() { <-sem }()
+
+            if _, err := attemptWebhookDelivery(ctx, db, d.Edges.Webhook, d); err != nil {
+                slog.ErrorContext(ctx, "error delivering webhook", "delivery", d.ID, "error", err)
+            }
+        }(d)
+    }
+    wg.Wait()
+
+    return nil
+}
+
+// RunWebhookDeliverer sends the deliveries that are due every interval until ctx is done
+func RunWebhookDeliverer(ctx context.Context, db *ent.Client, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        runCtx, span := tracing.Tracer().Start(ctx, "deliver webhooks")
+        if err := DeliverDueWebhooks(runCtx, db, time.Now()); err != nil {
+            slog.ErrorContext(runCtx, "error delivering webhooks", "error", err)
+        }
+        span.End()
+
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+// attemptWebhookDelivery sends the delivery once and records the result, scheduling a retry with exponential backoff
+// if it failed and has attempts left. It returns the updated delivery, or the given one if another run of the
+// deliverer claimed it first.
+func attemptWebhookDelivery(ctx context.Context, db *ent.Client, w *ent.Webhook, d *ent.WebhookDelivery) (*ent.WebhookDelivery, error) {
+    cfg := config.Current().Webhooks
+    timeout := cfg.TimeoutPeriod()
+
+    // claimed by bumping the attempts, so the delivery is sent once even if several panels share the database.
+    // The lease makes sure it's retried if the panel stops while sending it.
+    claimed, err := db.WebhookDelivery.Update().
+        Where(
+            webhookdelivery.IDEQ(d.ID),
+            webhookdelivery.StatusEQ(webhookdelivery.StatusPending),
+            webhookdelivery.AttemptsEQ(d.Attempts),
+        ).
+        AddAttempts(1).
+        SetNextAttemptAt(time.Now().Add(2 * timeout)).
+        Save(ctx)
+    if err != nil {
+        return nil, err
+    }
+    if claimed == 0 {
+        return d, nil
+    }
+    attempts := d.Attempts + 1
+
+    status, sendErr := sendWebhook(ctx, w, d, timeout)
+
+    update := db.WebhookDelivery.UpdateOneID(d.ID)
+    if status != 0 {
+        update.SetResponseStatus(status)
+    } else {
+        update.ClearResponseStatus()
+    }
+
+    switch {
+    case sendErr == nil:
+        update.SetStatus(webhookdelivery.StatusSucceeded).
+            SetDeliveredAt(time.Now()).
+            ClearNextAttemptAt().
+            ClearError()
+        metrics.WebhookDeliveryAttempts.WithLabelValues("succeeded").Inc()
+    case attempts >= cfg.MaxAttempts:
+        update.SetStatus(webhookdelivery.StatusFailed).
+            ClearNextAttemptAt().
+            SetError(truncate(sendErr.Error(), webhookErrorMaxLen))
+        metrics.WebhookDeliveryAttempts.WithLabelValues("failed").Inc()
+    default:
+        delay := webhookRetryDelay(attempts, cfg.RetryBackoffPeriod(), cfg.MaxRetryBackoffPeriod())
+        update.SetNextAttemptAt(time.Now().Add(delay)).
+            SetError(truncate(sendErr.Error(), webhookErrorMaxLen))
+        metrics.WebhookDeliveryAttempts.WithLabelValues("retrying").Inc()
+    }
+
+    return update.Save(ctx)
+}
+
+// sendWebhook posts the payload of the delivery to the webhook, failing unless it answers with a 2xx status.
+// The status is 0 if there was no answer.
+func sendWebhook(ctx context.Context, w *ent.Webhook, d *ent.WebhookDelivery, timeout time.Duration) (int, error) {
+    ctx, cancel := context.WithTimeout(ctx, timeout)
+    defer cancel()
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, strings.NewReader(d.Payload))
+    if err != nil {
+        return 0, err
+    }
+
+    timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("User-Agent", "Encedeus-Panel/"+version.Version)
+    req.Header.Set("X-Encedeus-Event", d.Event)
+    req.Header.Set("X-Encedeus-Delivery", d.ID.String())
+    req.Header.Set("X-Encedeus-Timestamp", timestamp)
+    req.Header.Set("X-Encedeus-Signature", WebhookSignature(w.Secret, timestamp, []byte(d.Payload)))
+
+    resp, err := webhookClient.Do(req)
+    if err != nil {
+        return 0, err
+    }
+    defer resp.Body.Close()
+    // drained so the connection can be reused
+    _, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+    if resp.StatusCode < 200 || resp.StatusCode > 299 {
+        return resp.StatusCode, fmt.Errorf("webhook answered with status %d", resp.StatusCode)
+    }
+
+    return resp.StatusCode, nil
+}
+
+// WebhookSignature is the X-Encedeus-Signature header of a delivery, the hex encoded HMAC-SHA256 of
+// the timestamp and the body joined by a dot, keyed with the webhook's secret
+func WebhookSignature(secret string, timestamp string, body []byte) string {
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write([]byte(timestamp))
+    mac.Write([]byte("."))
+    mac.Write(body)
+
+    return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay doubles the backoff with every attempt after the first, up to max
+func webhookRetryDelay(attempts int, backoff time.Duration, max time.Duration) time.Duration {
+    delay := backoff
+    for i := 1; i < attempts && delay < max; i++ {
+        delay *= 2
+    }
+
+    return min(delay, max)
+}
+
+func isWebhookURL(s string) bool {
+    u, err := url.Parse(s)
+
+    return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func areWebhookEvents(types []string) bool {
+    for _, t := range types {
+        if t != AllWebhookEvents && !events.IsType(t) {
+            return false
+        }
+    }
+
+    return true
+}
+
+func generateWebhookSecret() string {
+    secret := make([]byte, 32)
+    if _, err := rand.Read(secret); err != nil {
+        panic(err)
+    }
+
+    return hex.EncodeToString(secret)
+}
+
+func truncate(s string, maxLen int) string {
+    if len(s) <= maxLen {
+        return s
+    }
+
+    return s[:maxLen]
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+    "context"
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent/webhookdelivery"
+    "github.com/Encedeus/panel/events"
+    "github.com/Encedeus/panel/proto"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/testdb"
+    "github.com/google/uuid"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestWebhookSignature(t *testing.T) {
+    mac := hmac.New(sha256.New, []byte("secret"))
+    mac.Write([]byte("1700000000.{\"id\":1}"))
+    want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+    if got := WebhookSignature("secret", "1700000000", []byte(`{"id":1}`)); got != want {
+        t.Errorf("got %s, want %s", got, want)
+    }
+    if WebhookSignature("other", "1700000000", []byte(`{"id":1}`)) == want {
+        t.Error("signature doesn't depend on the secret")
+    }
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+    tests := []struct {
+        attempts int
+        want     time.Duration
+    }{
+        {1, 30 * time.Second},
+        {2, time.Minute},
+        {3, 2 * time.Minute},
+        {7, 32 * time.Minute},
+        {8, time.Hour},
+        {100, time.Hour},
+    }
+    for _, tt := range tests {
+        if got := webhookRetryDelay(tt.attempts, 30*time.Second, time.Hour); got != tt.want {
+            t.Errorf("delay after %d attempts is %s, want %s", tt.attempts, got, tt.want)
+        }
+    }
+}
+
+func TestCreateWebhookValidatesEvents(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+
+    tests := []struct {
+        name   string
+        events []string
+        want   error
+    }{
+        {"none", nil, ErrInvalidWebhookEvent},
+        {"unknown", []string{"user.created", "user.renamed"}, ErrInvalidWebhookEvent},
+        {"known", []string{"user.created"}, nil},
+        {"every event", []string{AllWebhookEvents}, nil},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := CreateWebhook(ctx, db, &protoapi.WebhookCreateRequest{
+                Url:    "https://example.com/hook",
+                Events: tt.events,
+            })
+            if !errors.Is(err, tt.want) {
+                t.Errorf("got %v, want %v", err, tt.want)
+            }
+        })
+    }
+}
+
+func TestWebhookDeliveryRetriesUntilDelivered(t *testing.T) {
+    db := testdb.NewClient(t)
+    ctx := context.Background()
+    config.Config = config.DefaultConfiguration()
+
+    var requests atomic.Int32
+    var validSignature atomic.Bool
+    receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        body, _ := io.ReadAll(r.Body)
+        validSignature.Store(r.Header.Get("X-Encedeus-Signature") == WebhookSignature("secret", r.Header.Get("X-Encedeus-Timestamp"), body))
+        // the first attempt fails
+        if requests.Add(1) == 1 {
+            w.WriteHeader(http.StatusInternalServerError)
+        }
+    }))
+    t.Cleanup(receiver.Close)
+
+    _, err := CreateWebhook(ctx, db, &protoapi.WebhookCreateRequest{
+        Url:    receiver.URL,
+        Events: []string{"server.started"},
+        Secret: "secret",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(SubscribeWebhooks(db))
+
+    if err = events.ServerStarted.Publish(ctx, db, &protoapi.Server{Id: proto.UUIDToProtoUUID(uuid.New()), Name: "lobby"}); err != nil {
+        t.Fatal(err)
+    }
+    // not subscribed to, so it isn't delivered
+    if err = events.ServerStopped.Publish(ctx, db, &protoapi.Server{Id: proto.UUIDToProtoUUID(uuid.New()), Name: "lobby"}); err != nil {
+        t.Fatal(err)
+    }
+
+    if err = DeliverDueWebhooks(ctx, db, time.Now()); err != nil {
+        t.Fatal(err)
+    }
+    d, err := db.WebhookDelivery.Query().Only(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if d.Status != webhookdelivery.StatusPending || d.Attempts != 1 || d.ResponseStatus != http.StatusInternalServerError {
+        t.Fatalf("failed delivery is %s after %d attempts, want pending after 1", d.Status, d.Attempts)
+    }
+    if retryIn := time.Until(*d.NextAttemptAt); retryIn < 25*time.Second || retryIn > 30*time.Second {
+        t.Errorf("retry is in %s, want the 30s retry backoff", retryIn)
+    }
+
+    // not due yet
+    if err = DeliverDueWebhooks(ctx, db, time.Now()); err != nil {
+        t.Fatal(err)
+    }
+    if requests.Load() != 1 {
+        t.Fatalf("retried before the backoff passed")
+    }
+
+    if err = DeliverDueWebhooks(ctx, db, time.Now().Add(time.Minute)); err != nil {
+        t.Fatal(err)
+    }
+    d, err = db.WebhookDelivery.Get(ctx, d.ID)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if d.Status != webhookdelivery.StatusSucceeded || d.Attempts != 2 {
+        t.Errorf("retried delivery is %s after %d attempts, want succeeded after 2", d.Status, d.Attempts)
+    }
+    if !validSignature.Load() {
+        t.Error("delivery signature doesn't match the webhook's secret")
+    }
+}
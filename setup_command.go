@@ -0,0 +1,53 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/services"
+    "log"
+)
+
+// runSetup handles `panel setup token`
+func runSetup(args []string) {
+    if len(args) != 1 || args[0] != "token" {
+        log.Fatalln("usage: panel setup token")
+    }
+
+    db := config.InitDB()
+    defer db.Close()
+
+    complete, err := services.IsSetupComplete(context.Background(), db)
+    if err != nil {
+        log.Fatalf("failed checking setup status: %v", err)
+    }
+    if complete {
+        log.Fatalln("setup is already complete, use `panel user create` to add users")
+    }
+
+    token, err := services.NewSetupToken(config.Config.Auth.SetupTokenFile)
+    if err != nil {
+        log.Fatalf("failed generating setup token: %v", err)
+    }
+
+    fmt.Println(token)
+}
+
+// logSetupToken prints the setup token on startup until the first user is created
+func logSetupToken(db *ent.Client) {
+    complete, err := services.IsSetupComplete(context.Background(), db)
+    if err != nil {
+        log.Fatalf("failed checking setup status: %v", err)
+    }
+    if complete {
+        return
+    }
+
+    token, err := services.EnsureSetupToken(config.Config.Auth.SetupTokenFile)
+    if err != nil {
+        log.Fatalf("failed generating setup token: %v", err)
+    }
+
+    log.Printf("The panel isn't set up yet, create the first user at POST /setup with the setup token %s", token)
+}
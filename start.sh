@@ -1,4 +1,8 @@
 #!/usr/bin/env sh
 
+# development secrets, tokens are invalidated on every restart unless these are set
+export ENCEDEUS_AUTH_JWT_SECRET_ACCESS="${ENCEDEUS_AUTH_JWT_SECRET_ACCESS:-$(head -c 32 /dev/urandom | base64)}"
+export ENCEDEUS_AUTH_JWT_SECRET_REFRESH="${ENCEDEUS_AUTH_JWT_SECRET_REFRESH:-$(head -c 32 /dev/urandom | base64)}"
+
 docker compose up -d
-go run .
+go run . migrate up && go run .
@@ -0,0 +1,140 @@
+// Package testdb opens ent clients for tests, backed by an in-memory SQLite database unless
+// ENCEDEUS_TEST_DATABASE_DRIVER and ENCEDEUS_TEST_DATABASE_DSN point them at another database,
+// where every client gets a schema of its own
+package testdb
+
+import (
+    "context"
+    "database/sql"
+    "entgo.io/ent/dialect"
+    entsql "entgo.io/ent/dialect/sql"
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent"
+    "github.com/Encedeus/panel/ent/enttest"
+    _ "github.com/Encedeus/panel/ent/runtime"
+    "github.com/Encedeus/panel/hashing"
+    "github.com/google/uuid"
+    _ "github.com/lib/pq"
+    _ "modernc.org/sqlite"
+    "os"
+    "strings"
+    "sync/atomic"
+)
+
+const (
+    EnvDriver = "ENCEDEUS_TEST_DATABASE_DRIVER"
+    EnvDSN    = "ENCEDEUS_TEST_DATABASE_DSN"
+)
+
+// Password is the password of every user created by CreateUser
+const Password = "password123"
+
+var databaseCount atomic.Int64
+
+// TestingT is the subset of testing.TB used to set up test databases
+type TestingT interface {
+    enttest.TestingT
+    Cleanup(func())
+    Fatalf(format string, args ...any)
+    Helper()
+}
+
+// NewClient returns a client of a freshly created schema, closed when the test finishes
+func NewClient(t TestingT, opts ...enttest.Option) *ent.Client {
+    driver := os.Getenv(EnvDriver)
+    if driver == "" {
+        driver = config.DriverSQLite
+    }
+
+    dsn := os.Getenv(EnvDSN)
+    switch {
+    case dsn == "" && driver == config.DriverSQLite:
+        // every test gets its own database, shared between the connections of its pool
+        dsn = fmt.Sprintf("file:test%d?mode=memory&cache=shared&_pragma=foreign_keys(1)", databaseCount.Add(1))
+    case driver == config.DriverPostgres:
+        dsn = newSchema(t, dsn)
+    }
+
+    db, err := sql.Open(driver, dsn)
+    if err != nil {
+        t.Error(err)
+        t.FailNow()
+    }
+
+    entDialect := dialect.Postgres
+    if driver == config.DriverSQLite {
+        entDialect = dialect.SQLite
+    }
+
+    opts = append([]enttest.Option{enttest.WithOptions(ent.Driver(entsql.OpenDB(entDialect, db)))}, opts...)
+    client := enttest.NewClient(t, opts...)
+    t.Cleanup(func() {
+        _ = client.Close()
+    })
+
+    return client
+}
+
+// newSchema creates a schema dropped when the test finishes, returning dsn with it as the search path
+func newSchema(t TestingT, dsn string) string {
+    t.Helper()
+
+    db, err := sql.Open(config.DriverPostgres, dsn)
+    if err != nil {
+        t.Fatalf("failed opening %s: %v", dsn, err)
+    }
+    schema := fmt.Sprintf("test_%d_%d", os.Getpid(), databaseCount.Add(1))
+    if _, err = db.Exec("CREATE SCHEMA " + schema); err != nil {
+        _ = db.Close()
+        t.Fatalf("failed creating schema %s: %v", schema, err)
+    }
+    t.Cleanup(func() {
+        _, _ = db.Exec("DROP SCHEMA " + schema + " CASCADE")
+        _ = db.Close()
+    })
+
+    if !strings.Contains(dsn, "://") {
+        return dsn + " search_path=" + schema
+    }
+    if strings.Contains(dsn, "?") {
+        return dsn + "&search_path=" + schema
+    }
+
+    return dsn + "?search_path=" + schema
+}
+
+// CreateRole creates a role straight in the database, the services validate more than the tests need
+func CreateRole(t TestingT, db *ent.Client, name string, permissions []string, parents ...*ent.Role) *ent.Role {
+    t.Helper()
+
+    create := db.Role.Create().SetName(name).SetPermissions(permissions)
+    for _, parent := range parents {
+        create.AddParentIDs(parent.ID)
+    }
+
+    r, err := create.Save(context.Background())
+    if err != nil {
+        t.Fatalf("failed creating role %s: %v", name, err)
+    }
+
+    return r
+}
+
+// CreateUser creates a user of roleId straight in the database with Password as its password,
+// services.CreateUser checks that the email's domain is reachable
+func CreateUser(t TestingT, db *ent.Client, name string, roleId uuid.UUID) *ent.User {
+    t.Helper()
+
+    u, err := db.User.Create().
+        SetName(name).
+        SetEmail(name + "@example.com").
+        SetPassword(hashing.HashPassword(Password)).
+        SetRoleID(roleId).
+        Save(context.Background())
+    if err != nil {
+        t.Fatalf("failed creating user %s: %v", name, err)
+    }
+
+    return u
+}
@@ -0,0 +1,128 @@
+package tracing
+
+import (
+    "context"
+    "database/sql"
+    "entgo.io/ent/dialect"
+    "fmt"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+    "go.opentelemetry.io/otel/trace"
+    "strings"
+)
+
+// Driver is an ent driver starting a span for every statement run through it, transactions included
+type Driver struct {
+    dialect.Driver
+    system attribute.KeyValue
+}
+
+// NewDriver wraps drv so its statements are traced
+func NewDriver(drv dialect.Driver) *Driver {
+    system := semconv.DBSystemPostgreSQL
+    if drv.Dialect() == dialect.SQLite {
+        system = semconv.DBSystemSqlite
+    }
+
+    return &Driver{drv, system}
+}
+
+func (d *Driver) Exec(ctx context.Context, query string, args, v any) error {
+    return d.trace(ctx, query, func(ctx context.Context) error {
+        return d.Driver.Exec(ctx, query, args, v)
+    })
+}
+
+func (d *Driver) Query(ctx context.Context, query string, args, v any) error {
+    return d.trace(ctx, query, func(ctx context.Context) error {
+        return d.Driver.Query(ctx, query, args, v)
+    })
+}
+
+func (d *Driver) Tx(ctx context.Context) (dialect.Tx, error) {
+    var tx dialect.Tx
+    err := d.trace(ctx, "BEGIN", func(ctx context.Context) (err error) {
+        tx, err = d.Driver.Tx(ctx)
+        return err
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &Tx{tx, d, ctx}, nil
+}
+
+// BeginTx starts a transaction with options, it's required by ent's Client.BeginTx
+func (d *Driver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+    drv, ok := d.Driver.(interface {
+        BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+    })
+    if !ok {
+        return nil, fmt.Errorf("driver.BeginTx is not supported")
+    }
+
+    var tx dialect.Tx
+    err := d.trace(ctx, "BEGIN", func(ctx context.Context) (err error) {
+        tx, err = drv.BeginTx(ctx, opts)
+        return err
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &Tx{tx, d, ctx}, nil
+}
+
+// trace runs fn in a span named after the statement's operation, e.g. SELECT
+func (d *Driver) trace(ctx context.Context, query string, fn func(ctx context.Context) error) error {
+    operation, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+    operation = strings.ToUpper(operation)
+
+    ctx, span := Tracer().Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+        d.system,
+        semconv.DBOperation(operation),
+        semconv.DBStatement(query),
+    ))
+    defer span.End()
+
+    err := fn(ctx)
+    if err != nil {
+        span.RecordError(err)
+        span.SetStatus(codes.Error, err.Error())
+    }
+
+    return err
+}
+
+// Tx is a transaction started by Driver, its statements are traced too.
+// Commit and rollback are traced in the context the transaction was started in.
+type Tx struct {
+    dialect.Tx
+    drv *Driver
+    ctx context.Context
+}
+
+func (t *Tx) Exec(ctx context.Context, query string, args, v any) error {
+    return t.drv.trace(ctx, query, func(ctx context.Context) error {
+        return t.Tx.Exec(ctx, query, args, v)
+    })
+}
+
+func (t *Tx) Query(ctx context.Context, query string, args, v any) error {
+    return t.drv.trace(ctx, query, func(ctx context.Context) error {
+        return t.Tx.Query(ctx, query, args, v)
+    })
+}
+
+func (t *Tx) Commit() error {
+    return t.drv.trace(t.ctx, "COMMIT", func(context.Context) error {
+        return t.Tx.Commit()
+    })
+}
+
+func (t *Tx) Rollback() error {
+    return t.drv.trace(t.ctx, "ROLLBACK", func(context.Context) error {
+        return t.Tx.Rollback()
+    })
+}
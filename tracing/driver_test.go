@@ -0,0 +1,57 @@
+package tracing
+
+import (
+    "context"
+    "database/sql"
+    "entgo.io/ent/dialect"
+    entsql "entgo.io/ent/dialect/sql"
+    "go.opentelemetry.io/otel/codes"
+    _ "modernc.org/sqlite"
+    "strings"
+    "testing"
+)
+
+func TestDriverTracesStatements(t *testing.T) {
+    recorder := recordSpans(t)
+
+    db, err := sql.Open("sqlite", "file:tracing?mode=memory")
+    if err != nil {
+        t.Fatal(err)
+    }
+    drv := NewDriver(entsql.OpenDB(dialect.SQLite, db))
+    t.Cleanup(func() {
+        _ = drv.Close()
+    })
+
+    ctx, parent := Tracer().Start(context.Background(), "request")
+    var res sql.Result
+    if err = drv.Exec(ctx, "CREATE TABLE t (id INTEGER)", []any{}, &res); err != nil {
+        t.Fatal(err)
+    }
+    tx, err := drv.Tx(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err = tx.Exec(ctx, "INSERT INTO missing (id) VALUES (1)", []any{}, &res); err == nil {
+        t.Fatal("inserted into a missing table")
+    }
+    if err = tx.Rollback(); err != nil {
+        t.Fatal(err)
+    }
+    parent.End()
+
+    spans := recorder.Ended()
+    var names []string
+    for _, span := range spans[:len(spans)-1] {
+        names = append(names, span.Name())
+        if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+            t.Errorf("span %s isn't a child of the request", span.Name())
+        }
+    }
+    if want := "CREATE BEGIN INSERT ROLLBACK"; strings.Join(names, " ") != want {
+        t.Errorf("got spans %s, want %s", strings.Join(names, " "), want)
+    }
+    if insert := spans[2]; insert.Status().Code != codes.Error {
+        t.Errorf("failed statement has status %v, want an error", insert.Status().Code)
+    }
+}
@@ -0,0 +1,117 @@
+// Package tracing sets up OpenTelemetry tracing with W3C trace context propagation
+// and instruments database statements and outbound HTTP calls
+package tracing
+
+import (
+    "context"
+    "fmt"
+    "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+    "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+    "go.opentelemetry.io/otel/trace"
+    "net/http"
+    "os"
+)
+
+const (
+    ExporterNone   = "none"
+    ExporterStdout = "stdout"
+    ExporterOTLP   = "otlp"
+)
+
+// tracerName identifies the spans started by the panel itself rather than by instrumentation libraries
+const tracerName = "github.com/Encedeus/panel"
+
+// Options describes where spans are exported to
+type Options struct {
+    ServiceName string
+    // Exporter is none, stdout or otlp, with none spans aren't recorded but trace context is still propagated
+    Exporter string
+    // Endpoint is the host:port of the OTLP collector
+    Endpoint string
+    // Protocol is grpc or http
+    Protocol string
+    // Insecure disables TLS towards the collector
+    Insecure bool
+    // SampleRatio is the fraction of traces started by the panel that are recorded,
+    // traces continued from a caller follow the caller's decision
+    SampleRatio float64
+}
+
+// Setup installs the global tracer provider and propagator, the returned function flushes the spans left and stops exporting
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+    otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+    exporter, err := newExporter(ctx, opts)
+    if err != nil {
+        return nil, err
+    }
+    if exporter == nil {
+        return func(context.Context) error { return nil }, nil
+    }
+
+    res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+        semconv.SchemaURL,
+        semconv.ServiceName(opts.ServiceName),
+    ))
+    if err != nil {
+        return nil, err
+    }
+
+    provider := sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(exporter),
+        sdktrace.WithResource(res),
+        sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+    )
+    otel.SetTracerProvider(provider)
+
+    return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, error) {
+    switch opts.Exporter {
+    case ExporterStdout:
+        return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+    case ExporterOTLP:
+        if opts.Protocol == "http" {
+            httpOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.Endpoint)}
+            if opts.Insecure {
+                httpOpts = append(httpOpts, otlptracehttp.WithInsecure())
+            }
+
+            return otlptracehttp.New(ctx, httpOpts...)
+        }
+
+        grpcOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+        if opts.Insecure {
+            grpcOpts = append(grpcOpts, otlptracegrpc.WithInsecure())
+        }
+
+        return otlptracegrpc.New(ctx, grpcOpts...)
+    case ExporterNone, "":
+        return nil, nil
+    default:
+        return nil, fmt.Errorf("unknown trace exporter %q", opts.Exporter)
+    }
+}
+
+// Tracer returns the tracer for spans started by the panel's own code
+func Tracer() trace.Tracer {
+    return otel.Tracer(tracerName)
+}
+
+// Transport wraps base, or http.DefaultTransport if it's nil, so outbound requests, e.g. to daemons on nodes,
+// are traced and carry the trace context of the request's context
+func Transport(base http.RoundTripper) http.RoundTripper {
+    if base == nil {
+        base = http.DefaultTransport
+    }
+
+    return otelhttp.NewTransport(base)
+}
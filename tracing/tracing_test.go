@@ -0,0 +1,55 @@
+package tracing
+
+import (
+    "context"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/propagation"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/sdk/trace/tracetest"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+// recordSpans installs a tracer provider recording every span until the test ends
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+    t.Helper()
+
+    previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+    recorder := tracetest.NewSpanRecorder()
+    otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+    otel.SetTextMapPropagator(propagation.TraceContext{})
+    t.Cleanup(func() {
+        otel.SetTracerProvider(previousProvider)
+        otel.SetTextMapPropagator(previousPropagator)
+    })
+
+    return recorder
+}
+
+func TestTransportPropagatesTraceContext(t *testing.T) {
+    recordSpans(t)
+
+    var traceparent string
+    daemon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        traceparent = r.Header.Get("traceparent")
+    }))
+    t.Cleanup(daemon.Close)
+
+    ctx, span := Tracer().Start(context.Background(), "request")
+    defer span.End()
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, daemon.URL, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    _ = resp.Body.Close()
+
+    if want := span.SpanContext().TraceID().String(); len(traceparent) < 35 || traceparent[3:35] != want {
+        t.Errorf("daemon got traceparent %q, want trace %s", traceparent, want)
+    }
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+    "bufio"
+    "context"
+    "errors"
+    "flag"
+    "fmt"
+    "github.com/Encedeus/panel/config"
+    "github.com/Encedeus/panel/ent"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/Encedeus/panel/services"
+    "github.com/google/uuid"
+    "io"
+    "log"
+    "os"
+    "strings"
+    "text/tabwriter"
+    "time"
+)
+
+// runUser handles `panel user create|reset-password|list|import|export`
+func runUser(args []string) {
+    if len(args) == 0 {
+        log.Fatalln("usage: panel user create|reset-password|list|import|export")
+    }
+
+    db := config.InitDB()
+    defer db.Close()
+    // queued here, the deliveries are sent by the running panel
+    services.SubscribeWebhooks(db)
+
+    ctx := context.Background()
+    switch args[0] {
+    case "create":
+        fs := flag.NewFlagSet("user create", flag.ExitOnError)
+        password := fs.String("password", "", "password of the user, read from stdin if not set")
+        _ = fs.Parse(args[1:])
+        if fs.NArg() != 3 {
+            log.Fatalln("usage: panel user create [--password password] <name> <email> <role>")
+        }
+
+        req := &protoapi.UserCreateRequest{
+            Name:     fs.Arg(0),
+            Email:    fs.Arg(1),
+            Password: passwordOrStdin(*password),
+            RoleId:   &protoapi.UUID{Value: fs.Arg(2)},
+            RoleName: fs.Arg(2),
+        }
+        // the CLI isn't limited to the roles of a user
+        resp, err := services.CreateUser(ctx, db, uuid.Nil, req)
+        if err != nil {
+            log.Fatalf("failed creating user: %v", err)
+        }
+
+        fmt.Printf("created user %s with id %s\n", resp.User.Name, resp.User.Id.Value)
+    case "reset-password":
+        fs := flag.NewFlagSet("user reset-password", flag.ExitOnError)
+        password := fs.String("password", "", "new password, read from stdin if not set")
+        _ = fs.Parse(args[1:])
+        if fs.NArg() != 1 {
+            log.Fatalln("usage: panel user reset-password [--password password] <user>")
+        }
+
+        userId := findUserID(ctx, db, fs.Arg(0))
+        if err := services.ResetUserPassword(ctx, db, userId, passwordOrStdin(*password)); err != nil {
+            log.Fatalf("failed resetting password: %v", err)
+        }
+
+        fmt.Printf("reset the password of %s\n", fs.Arg(0))
+    case "list":
+        users, err := services.FindAllUsers(ctx, db)
+        if err != nil {
+            log.Fatalf("failed listing users: %v", err)
+        }
+
+        w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+        _, _ = fmt.Fprintln(w, "ID\tNAME\tEMAIL\tROLE ID\tCREATED AT")
+        for _, u := range users {
+            _, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", u.Id.Value, u.Name, u.Email, u.RoleId.Value, u.CreatedAt.AsTime().Format(time.RFC3339))
+        }
+        _ = w.Flush()
+    case "import":
+        // the CLI isn't limited to the roles of a user
+        runImport(ctx, db, "user", args[1:], services.ReadUserRecords, func(ctx context.Context, db *ent.Client, records []*protoapi.UserRecord, opts services.ImportOptions) (*protoapi.ImportReport, error) {
+            return services.ImportUsers(ctx, db, uuid.Nil, records, opts)
+        })
+    case "export":
+        runExport(ctx, db, "user", args[1:], services.ExportUsers, services.WriteUserRecords)
+    default:
+        log.Fatalf("unknown user command %q, expected create, reset-password, list, import or export", args[0])
+    }
+}
+
+// findUserID resolves a user given by name or id, exiting if there's no such user
+func findUserID(ctx context.Context, db *ent.Client, nameOrId string) uuid.UUID {
+    userId, err := services.FindUserID(ctx, db, &protoapi.UUID{Value: nameOrId}, nameOrId)
+    if ent.IsNotFound(err) {
+        log.Fatalf("user %s not found", nameOrId)
+    }
+    if err != nil {
+        log.Fatalf("failed finding user %s: %v", nameOrId, err)
+    }
+
+    return userId
+}
+
+// passwordOrStdin returns password or, if it's empty, the first line of stdin so it stays out of the shell history
+func passwordOrStdin(password string) string {
+    if password != "" {
+        return password
+    }
+
+    _, _ = fmt.Fprint(os.Stderr, "password: ")
+    line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+    if err != nil && !errors.Is(err, io.EOF) {
+        log.Fatalf("failed reading password: %v", err)
+    }
+
+    password = strings.TrimRight(line, "\r\n")
+    if password == "" {
+        log.Fatalln("password can't be empty")
+    }
+
+    return password
+}
@@ -22,8 +22,7 @@ func IsUsername(username string) bool {
 }
 
 func IsEmail(email string) bool {
-    _, err := mail.ParseAddress(email)
-    if err != nil {
+    if !IsEmailSyntax(email) {
         return false
     }
 
@@ -35,7 +34,7 @@ func IsEmail(email string) bool {
     ch := make(chan error, 1)
     defer close(ch)
     go func() {
-        _, err = cli.Get("http://" + domain)
+        _, err := cli.Get("http://" + domain)
         ch <- err
     }()
 
@@ -46,6 +45,13 @@ func IsEmail(email string) bool {
     return true
 }
 
+// IsEmailSyntax only checks that email is an address, unlike IsEmail it doesn't check that its domain is reachable
+func IsEmailSyntax(email string) bool {
+    _, err := mail.ParseAddress(email)
+
+    return err == nil
+}
+
 func IsPassword(password string) bool {
     // if len(password) > 64 || len(password) < 8 {
     //     return false
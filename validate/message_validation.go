@@ -0,0 +1,178 @@
+package validate
+
+import (
+    "fmt"
+    protoapi "github.com/Encedeus/panel/proto/go"
+    "github.com/google/uuid"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/reflect/protoreflect"
+    "net/mail"
+    "regexp"
+    "strings"
+    "sync"
+    "unicode/utf8"
+)
+
+// Violation is a request field breaking one of the rules declared on it in the proto files
+type Violation struct {
+    // Field is the field's JSON name
+    Field   string
+    Message string
+}
+
+// Message checks the fields of msg against the field_rules and message_rules options of its definition,
+// returning the violations in field order
+func Message(msg proto.Message) []Violation {
+    m := msg.ProtoReflect()
+    md := m.Descriptor()
+
+    var violations []Violation
+    fields := md.Fields()
+    for i := 0; i < fields.Len(); i++ {
+        f := fields.Get(i)
+        rules, _ := proto.GetExtension(f.Options(), protoapi.E_FieldRules).(*protoapi.FieldRules)
+        if rules == nil {
+            continue
+        }
+
+        if message := checkField(m, f, rules); message != "" {
+            violations = append(violations, Violation{
+                Field:   f.JSONName(),
+                Message: message,
+            })
+        }
+    }
+
+    messageRules, _ := proto.GetExtension(md.Options(), protoapi.E_MessageRules).(*protoapi.MessageRules)
+    if names := messageRules.GetRequireOneOf(); len(names) != 0 {
+        var jsonNames []string
+        set := false
+        for _, name := range names {
+            f := fields.ByName(protoreflect.Name(name))
+            if f == nil {
+                continue
+            }
+            jsonNames = append(jsonNames, f.JSONName())
+            set = set || isSet(m, f)
+        }
+
+        if !set && len(jsonNames) != 0 {
+            violations = append(violations, Violation{
+                Field:   jsonNames[0],
+                Message: "one of " + strings.Join(jsonNames, ", ") + " is required",
+            })
+        }
+    }
+
+    return violations
+}
+
+// checkField returns why the value of f breaks the rules, or an empty string if it doesn't
+func checkField(m protoreflect.Message, f protoreflect.FieldDescriptor, rules *protoapi.FieldRules) string {
+    if !isSet(m, f) {
+        if rules.Required {
+            return "is required"
+        }
+
+        return ""
+    }
+
+    v := m.Get(f)
+    if f.IsList() {
+        list := v.List()
+        if rules.MaxItems != 0 && uint32(list.Len()) > rules.MaxItems {
+            return fmt.Sprintf("must have at most %d items", rules.MaxItems)
+        }
+        for i := 0; i < list.Len(); i++ {
+            if message := checkValue(f, list.Get(i), rules); message != "" {
+                return fmt.Sprintf("item %d %s", i, message)
+            }
+        }
+
+        return ""
+    }
+
+    return checkValue(f, v, rules)
+}
+
+func checkValue(f protoreflect.FieldDescriptor, v protoreflect.Value, rules *protoapi.FieldRules) string {
+    var s string
+    switch f.Kind() {
+    case protoreflect.StringKind:
+        s = v.String()
+    case protoreflect.MessageKind:
+        // only UUID messages have rules for their value
+        if f.Message().FullName() != uuidMessage {
+            return ""
+        }
+        s = v.Message().Interface().(*protoapi.UUID).GetValue()
+    default:
+        return ""
+    }
+
+    length := uint32(utf8.RuneCountInString(s))
+    switch {
+    case rules.MinLen != 0 && length < rules.MinLen:
+        return fmt.Sprintf("must be at least %d characters", rules.MinLen)
+    case rules.MaxLen != 0 && length > rules.MaxLen:
+        return fmt.Sprintf("must be at most %d characters", rules.MaxLen)
+    case rules.Pattern != "" && !compilePattern(rules.Pattern).MatchString(s):
+        return "must match " + rules.Pattern
+    case rules.Uuid && !isUUID(s):
+        return "must be a valid UUID"
+    case rules.Email && !isEmailAddress(s):
+        return "must be an email address"
+    }
+
+    return ""
+}
+
+func isUUID(s string) bool {
+    _, err := uuid.Parse(s)
+
+    return err == nil
+}
+
+var uuidMessage = (&protoapi.UUID{}).ProtoReflect().Descriptor().FullName()
+
+// isSet treats blank strings and UUID messages without a value as unset
+func isSet(m protoreflect.Message, f protoreflect.FieldDescriptor) bool {
+    if !m.Has(f) {
+        return false
+    }
+    if f.IsList() || f.IsMap() {
+        return true
+    }
+
+    switch f.Kind() {
+    case protoreflect.StringKind:
+        return strings.TrimSpace(m.Get(f).String()) != ""
+    case protoreflect.MessageKind:
+        if f.Message().FullName() == uuidMessage {
+            return strings.TrimSpace(m.Get(f).Message().Interface().(*protoapi.UUID).GetValue()) != ""
+        }
+    }
+
+    return true
+}
+
+// isEmailAddress only checks the syntax, unlike IsEmail it doesn't contact the domain
+func isEmailAddress(s string) bool {
+    address, err := mail.ParseAddress(s)
+
+    return err == nil && address.Address == s
+}
+
+var patterns sync.Map
+
+// compilePattern caches the compiled patterns, they come from the proto files so an invalid one is a bug
+func compilePattern(pattern string) *regexp.Regexp {
+    if re, ok := patterns.Load(pattern); ok {
+        return re.(*regexp.Regexp)
+    }
+
+    re := regexp.MustCompile(pattern)
+    patterns.Store(pattern, re)
+
+    return re
+}
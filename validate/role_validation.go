@@ -30,6 +30,20 @@ func IsRoleName(ctx context.Context, db *ent.Client, name string) bool {
     return err == nil
 }
 
+// IsRoleNameSyntax checks that name can be given to a role, unlike IsRoleName it doesn't look the role up
+func IsRoleNameSyntax(name string) bool {
+    if strings.TrimSpace(name) == "" || len(name) < 3 || len(name) > 24 {
+        return false
+    }
+
+    p := bluemonday.StrictPolicy()
+    if s := p.Sanitize(name); s != name {
+        return false
+    }
+
+    return true
+}
+
 func IsPermission(permission string) bool {
     if len(permission) > 64 || len(permission) < 3 {
         return false
@@ -0,0 +1,45 @@
+// Package version describes the build of the running panel
+package version
+
+import (
+    "runtime"
+    "runtime/debug"
+)
+
+// Version is set when building a release, e.g. go build -ldflags "-X github.com/Encedeus/panel/version.Version=v1.0.0"
+var Version = "dev"
+
+// Info is what the Go toolchain recorded about the build
+type Info struct {
+    Version    string
+    Commit     string
+    CommitTime string
+    // Modified reports whether the build included uncommitted changes
+    Modified  bool
+    GoVersion string
+}
+
+// Get returns the build info, the VCS details are empty if the binary wasn't built from a git checkout
+func Get() Info {
+    info := Info{
+        Version:   Version,
+        GoVersion: runtime.Version(),
+    }
+
+    build, ok := debug.ReadBuildInfo()
+    if !ok {
+        return info
+    }
+    for _, setting := range build.Settings {
+        switch setting.Key {
+        case "vcs.revision":
+            info.Commit = setting.Value
+        case "vcs.time":
+            info.CommitTime = setting.Value
+        case "vcs.modified":
+            info.Modified = setting.Value == "true"
+        }
+    }
+
+    return info
+}